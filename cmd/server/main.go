@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/service"
 	"auth-go/internal/infrastructure/config"
+	"auth-go/internal/infrastructure/email"
+	"auth-go/internal/infrastructure/geoip"
+	"auth-go/internal/infrastructure/outbox"
 	"auth-go/internal/infrastructure/persistence"
+	"auth-go/internal/infrastructure/ratelimit"
 	"auth-go/internal/infrastructure/security"
+	"auth-go/internal/infrastructure/webhook"
 	httpHandler "auth-go/internal/interface/http"
 	"auth-go/internal/interface/http/handler"
 	"auth-go/internal/interface/http/middleware"
@@ -41,34 +52,365 @@ func main() {
 	// Initialize repositories
 	userRepo := persistence.NewPostgresUserRepository(db)
 	refreshTokenRepo := persistence.NewPostgresRefreshTokenRepository(db)
+	sessionRepo := persistence.NewPostgresSessionRepository(db)
+	roleRepo := persistence.NewPostgresRoleRepository(db)
+	knownDeviceRepo := persistence.NewPostgresKnownDeviceRepository(db)
+	verificationTokenRepo := persistence.NewPostgresVerificationTokenRepository(db)
+	passwordHistoryRepo := persistence.NewPostgresPasswordHistoryRepository(db)
+	loginAttemptRepo := persistence.NewPostgresLoginAttemptRepository(db)
+	auditLogger := persistence.NewPostgresAuditLogger(db)
+	auditEventRepo := persistence.NewPostgresAuditEventRepository(db)
+	webhookEndpointRepo := persistence.NewPostgresWebhookEndpointRepository(db)
+	webhookDeliveryRepo := persistence.NewPostgresWebhookDeliveryRepository(db)
+	outboxRepo := persistence.NewPostgresOutboxRepository(db)
+	txManager := persistence.NewPostgresTxManager(db)
 
 	// Initialize services
-	passwordHasher := security.NewBcryptPasswordHasher()
+	passwordHasher, err := security.NewPasswordHasher(
+		cfg.Password.HashAlgorithm,
+		cfg.Password.BcryptCost,
+		security.Argon2idParams{
+			Memory:      uint32(cfg.Password.Argon2Memory),
+			Iterations:  uint32(cfg.Password.Argon2Iterations),
+			Parallelism: uint8(cfg.Password.Argon2Parallelism),
+		},
+	)
+	if err != nil {
+		log.Fatalf("Failed to initialize password hasher: %v", err)
+	}
+
+	var breachedPasswords *security.BreachedPasswordList
+	if cfg.Password.BreachedHashesFile != "" {
+		breachedPasswords, err = security.LoadBreachedPasswordList(cfg.Password.BreachedHashesFile)
+		if err != nil {
+			log.Fatalf("Failed to load breached password list: %v", err)
+		}
+		log.Printf("Loaded %d breached password hashes", breachedPasswords.Size())
+	}
+
+	passwordPolicy := security.NewPasswordPolicy(
+		security.PasswordPolicyConfig{
+			MinLength:        cfg.Password.MinLength,
+			MaxLength:        cfg.Password.MaxLength,
+			RequireUppercase: cfg.Password.RequireUppercase,
+			RequireLowercase: cfg.Password.RequireLowercase,
+			RequireDigit:     cfg.Password.RequireDigit,
+			RequireSymbol:    cfg.Password.RequireSymbol,
+			MinStrength:      cfg.Password.MinStrength,
+			HistorySize:      cfg.Password.HistorySize,
+		},
+		passwordHasher,
+		passwordHistoryRepo,
+		breachedPasswords,
+	)
 	tokenService := security.NewJWTTokenService(
 		cfg.JWT.SecretKey,
 		cfg.JWT.AccessTokenExpiry,
 		cfg.JWT.RefreshTokenExpiry,
 		cfg.JWT.Issuer,
 	)
+	opaqueTokenService := security.NewOpaqueTokenService(cfg.App.TokenPepper)
+
+	// Permissions are either embedded in access tokens or resolved from the roles per request
+	permissionResolver := security.NewRolePermissionResolver(roleRepo)
+	var tokenPermissions service.PermissionResolver
+	if cfg.JWT.EmbedPermissions {
+		tokenPermissions = permissionResolver
+	}
+
+	var emailSender service.EmailSender
+	if cfg.SMTP.Host != "" {
+		emailSender = email.NewSMTPEmailSender(email.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})
+	} else {
+		log.Println("SMTP_HOST not set, emails will be written to the log")
+		emailSender = email.NewLogEmailSender()
+	}
+	emailSender = email.NewAsyncEmailSender(emailSender)
+	webhookPublisher := webhook.NewPublisher(webhookEndpointRepo, webhookDeliveryRepo)
 
 	// Initialize use cases
-	registerUseCase := usecase.NewRegisterUseCase(userRepo, passwordHasher)
-	loginUseCase := usecase.NewLoginUseCase(userRepo, refreshTokenRepo, passwordHasher, tokenService)
-	refreshTokenUseCase := usecase.NewRefreshTokenUseCase(userRepo, refreshTokenRepo, tokenService)
-	logoutUseCase := usecase.NewLogoutUseCase(refreshTokenRepo)
+	loginThrottle := usecase.NewLoginThrottle(loginAttemptRepo, emailSender, usecase.LockoutPolicy{
+		MaxAttempts:   cfg.Lockout.MaxAttempts,
+		LockDuration:  cfg.Lockout.LockDuration,
+		BaseDelay:     cfg.Lockout.BaseDelay,
+		MaxDelay:      cfg.Lockout.MaxDelay,
+		IPMaxAttempts: cfg.Lockout.IPMaxAttempts,
+		ResetAfter:    cfg.Lockout.ResetAfter,
+	})
+	// The impossible travel check needs a local GeoIP database
+	var geoIP service.GeoIPResolver
+	if cfg.Device.GeoIPDatabaseFile != "" {
+		geoIPDatabase, err := geoip.LoadCSVDatabase(cfg.Device.GeoIPDatabaseFile)
+		if err != nil {
+			log.Fatalf("Failed to load GeoIP database: %v", err)
+		}
+		log.Printf("Loaded %d GeoIP ranges", geoIPDatabase.Size())
+		geoIP = geoIPDatabase
+	}
+	deviceMonitor := usecase.NewDeviceMonitor(knownDeviceRepo, opaqueTokenService, geoIP, emailSender, auditLogger, usecase.DeviceMonitorPolicy{
+		NotifyNewDevices:  cfg.Device.NotifyNewDevices,
+		MaxTravelSpeedKmh: float64(cfg.Device.MaxTravelSpeedKmh),
+	})
+	registerUseCase := usecase.NewRegisterUseCase(
+		userRepo,
+		txManager,
+		passwordHasher,
+		passwordPolicy,
+		emailSender,
+		auditLogger,
+		cfg.App.RegistrationEnumerationSafe,
+		cfg.App.BaseURL+"/web/login",
+		cfg.App.BaseURL+"/web/forgot-password",
+	)
+
+	// Session lifetime policy, global and per client
+	clientLifetimes, err := entity.ParseSessionLifetimes(cfg.Session.ClientLifetimes)
+	if err != nil {
+		log.Fatalf("Invalid session lifetime configuration: %v", err)
+	}
+	sessionLifetimes := entity.SessionLifetimePolicy{
+		Default: entity.SessionLifetime{MaxLifetime: cfg.Session.MaxLifetime, IdleTimeout: cfg.Session.IdleTimeout},
+		Clients: clientLifetimes,
+	}
+
+	// Concurrent session limits
+	roleSessionLimits, err := entity.ParseSessionLimits(cfg.Session.RoleLimits)
+	if err != nil {
+		log.Fatalf("Invalid session limit configuration: %v", err)
+	}
+	for role := range roleSessionLimits {
+		if _, err := roleRepo.FindByName(context.Background(), role); err != nil {
+			log.Printf("SESSION_ROLE_LIMITS names role %q, which is not defined or could not be looked up: %v", role, err)
+		}
+	}
+	if cfg.Session.LimitMode != "reject" && cfg.Session.LimitMode != "evict" {
+		log.Fatalf("Unsupported session limit mode %q", cfg.Session.LimitMode)
+	}
+	sessionLimits := entity.SessionLimitPolicy{
+		Default:     cfg.Session.MaxPerUser,
+		Roles:       roleSessionLimits,
+		EvictOldest: cfg.Session.LimitMode == "evict",
+	}
+
+	loginUseCase := usecase.NewLoginUseCase(userRepo, refreshTokenRepo, sessionRepo, txManager, passwordHasher, tokenService, tokenPermissions, opaqueTokenService, sessionLifetimes, sessionLimits, loginThrottle, deviceMonitor, auditLogger)
+	refreshTokenUseCase := usecase.NewRefreshTokenUseCase(userRepo, refreshTokenRepo, sessionRepo, txManager, tokenService, tokenPermissions, opaqueTokenService, sessionLifetimes, auditLogger, cfg.JWT.RefreshReuseGrace)
+	logoutUseCase := usecase.NewLogoutUseCase(refreshTokenRepo, auditLogger)
+	revokeSessionUseCase := usecase.NewRevokeSessionUseCase(sessionRepo, refreshTokenRepo, auditLogger)
+	reauthenticateUseCase := usecase.NewReauthenticateUseCase(userRepo, sessionRepo, passwordHasher, tokenService, tokenPermissions, loginThrottle, auditLogger, cfg.StepUp.TokenExpiry)
+	forgotPasswordUseCase := usecase.NewForgotPasswordUseCase(
+		userRepo,
+		verificationTokenRepo,
+		opaqueTokenService,
+		emailSender,
+		cfg.App.PasswordResetExpiry,
+		cfg.App.BaseURL+"/web/reset-password",
+	)
+	resetPasswordUseCase := usecase.NewResetPasswordUseCase(userRepo, verificationTokenRepo, refreshTokenRepo, txManager, passwordHasher, passwordPolicy, opaqueTokenService)
+	changePasswordUseCase := usecase.NewChangePasswordUseCase(userRepo, refreshTokenRepo, passwordHasher, passwordPolicy, loginThrottle, emailSender)
+	changeEmailUseCase := usecase.NewChangeEmailUseCase(
+		userRepo,
+		verificationTokenRepo,
+		passwordHasher,
+		loginThrottle,
+		opaqueTokenService,
+		emailSender,
+		cfg.App.EmailVerifyExpiry,
+		cfg.App.BaseURL+"/web/verify-email",
+	)
+	confirmEmailChangeUseCase := usecase.NewConfirmEmailChangeUseCase(userRepo, verificationTokenRepo, refreshTokenRepo, opaqueTokenService)
+	unlockAccountUseCase := usecase.NewUnlockAccountUseCase(userRepo, roleRepo, loginThrottle, auditLogger)
+	assignRoleUseCase := usecase.NewAssignRoleUseCase(userRepo, roleRepo, auditLogger)
+	revokeRoleUseCase := usecase.NewRevokeRoleUseCase(userRepo, roleRepo, auditLogger)
+	createUserUseCase := usecase.NewCreateUserUseCase(
+		userRepo,
+		roleRepo,
+		verificationTokenRepo,
+		opaqueTokenService,
+		emailSender,
+		auditLogger,
+		cfg.App.InviteExpiry,
+		cfg.App.BaseURL+"/web/reset-password",
+	)
+	updateUserUseCase := usecase.NewUpdateUserUseCase(userRepo, roleRepo, auditLogger)
+	setUserActiveUseCase := usecase.NewSetUserActiveUseCase(userRepo, roleRepo, refreshTokenRepo, auditLogger)
+	forcePasswordResetUseCase := usecase.NewForcePasswordResetUseCase(userRepo, roleRepo, refreshTokenRepo, forgotPasswordUseCase, auditLogger)
+	revokeSessionsUseCase := usecase.NewRevokeSessionsUseCase(userRepo, roleRepo, refreshTokenRepo, auditLogger)
+	deleteUserUseCase := usecase.NewDeleteUserUseCase(userRepo, roleRepo, auditLogger)
+	verifyAuditChainUseCase := usecase.NewVerifyAuditChainUseCase(auditEventRepo)
+	createWebhookUseCase := usecase.NewCreateWebhookUseCase(webhookEndpointRepo, opaqueTokenService, auditLogger)
+	updateWebhookUseCase := usecase.NewUpdateWebhookUseCase(webhookEndpointRepo, opaqueTokenService, auditLogger)
+	deleteWebhookUseCase := usecase.NewDeleteWebhookUseCase(webhookEndpointRepo, auditLogger)
+	redeliverWebhookUseCase := usecase.NewRedeliverWebhookUseCase(webhookDeliveryRepo, auditLogger)
+	createRoleUseCase := usecase.NewCreateRoleUseCase(roleRepo, auditLogger)
+	updateRoleUseCase := usecase.NewUpdateRoleUseCase(roleRepo, auditLogger)
+	deleteRoleUseCase := usecase.NewDeleteRoleUseCase(roleRepo, auditLogger)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(registerUseCase, loginUseCase, refreshTokenUseCase, logoutUseCase)
-	adminHandler := handler.NewAdminHandler(userRepo)
-	webHandler := handler.NewWebHandler(logoutUseCase, refreshTokenUseCase, userRepo)
+	adminHandler := handler.NewAdminHandler(
+		userRepo,
+		unlockAccountUseCase,
+		assignRoleUseCase,
+		revokeRoleUseCase,
+		createUserUseCase,
+		updateUserUseCase,
+		setUserActiveUseCase,
+		forcePasswordResetUseCase,
+		revokeSessionsUseCase,
+		deleteUserUseCase,
+	)
+	auditHandler := handler.NewAuditHandler(auditEventRepo, verifyAuditChainUseCase)
+	roleHandler := handler.NewRoleHandler(roleRepo, createRoleUseCase, updateRoleUseCase, deleteRoleUseCase)
+	webhookHandler := handler.NewWebhookHandler(
+		webhookEndpointRepo,
+		webhookDeliveryRepo,
+		createWebhookUseCase,
+		updateWebhookUseCase,
+		deleteWebhookUseCase,
+		redeliverWebhookUseCase,
+	)
+	passwordHandler := handler.NewPasswordHandler(forgotPasswordUseCase, resetPasswordUseCase)
+	accountHandler := handler.NewAccountHandler(sessionRepo, changePasswordUseCase, changeEmailUseCase, confirmEmailChangeUseCase, revokeSessionUseCase, reauthenticateUseCase)
+	webHandler := handler.NewWebHandler(loginUseCase, logoutUseCase, refreshTokenUseCase, userRepo, handler.WebCookieOptions{
+		Enabled:            cfg.Web.CookieMode,
+		Secure:             cfg.Web.CookieSecure,
+		RefreshTokenExpiry: cfg.JWT.RefreshTokenExpiry,
+	})
+
+	// Backend-for-frontend (optional); its session store keeps the tokens encrypted
+	var bffHandler *handler.BFFHandler
+	if cfg.BFF.Enabled {
+		secretBox, err := security.NewAESGCMSecretBox(cfg.BFF.EncryptionKey)
+		if err != nil {
+			log.Fatalf("Invalid BFF_ENCRYPTION_KEY: %v", err)
+		}
+		bffRoutes, err := handler.ParseBFFRoutes(cfg.BFF.Routes)
+		if err != nil {
+			log.Fatalf("Invalid BFF route configuration: %v", err)
+		}
+		bffSessionRepo := persistence.NewPostgresBFFSessionRepository(db, secretBox)
+		bffUseCase := usecase.NewBFFUseCase(bffSessionRepo, txManager, loginUseCase, refreshTokenUseCase, logoutUseCase, tokenService, opaqueTokenService)
+		bffHandler = handler.NewBFFHandler(bffUseCase, bffRoutes, cfg.Web.CookieSecure)
+		log.Printf("BFF enabled with %d upstream route(s)", len(bffRoutes))
+	}
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(tokenService)
+	authMiddleware := middleware.NewAuthMiddleware(tokenService, permissionResolver)
+	// Browsers authenticate with cookies on these paths, so state-changing requests need a CSRF token
+	var csrfPaths []string
+	if cfg.Web.CookieMode {
+		csrfPaths = append(csrfPaths, "/web/")
+	}
+	if cfg.BFF.Enabled {
+		csrfPaths = append(csrfPaths, "/bff/")
+	}
+	csrfMiddleware := middleware.NewCSRFMiddleware(cfg.Web.CookieSecure, csrfPaths...)
 	logMiddleware := middleware.NewLoggingMiddleware()
 	corsMiddleware := middleware.NewCORSMiddleware()
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid trusted proxy configuration: %v", err)
+	}
+	realIPMiddleware := middleware.NewRealIPMiddleware(cfg.Server.TrustProxyHeaders, trustedProxies, cfg.Server.TrustXRealIP)
+	deviceMiddleware := middleware.NewDeviceMiddleware(cfg.Web.CookieSecure)
+
+	rateLimitRules := map[string][]middleware.RateLimitRule{}
+	if cfg.RateLimit.Enabled {
+		for route, spec := range map[string]string{
+			httpHandler.RouteLogin:          cfg.RateLimit.Login,
+			httpHandler.RouteRegister:       cfg.RateLimit.Register,
+			httpHandler.RouteRefresh:        cfg.RateLimit.Refresh,
+			httpHandler.RouteForgotPassword: cfg.RateLimit.ForgotPassword,
+			httpHandler.RouteResetPassword:  cfg.RateLimit.ResetPassword,
+			httpHandler.RouteChangePassword: cfg.RateLimit.ChangePassword,
+			httpHandler.RouteChangeEmail:    cfg.RateLimit.ChangeEmail,
+			httpHandler.RouteReauthenticate: cfg.RateLimit.Reauthenticate,
+		} {
+			rules, err := middleware.ParseRateLimitRules(spec, strings.Split(cfg.RateLimit.Clients, ","))
+			if err != nil {
+				log.Fatalf("Invalid rate limit configuration for %s: %v", route, err)
+			}
+			rateLimitRules[route] = rules
+		}
+	}
+
+	var rateLimitStore ratelimit.Store
+	switch cfg.RateLimit.Backend {
+	case "memory":
+		rateLimitStore = ratelimit.NewMemoryStore()
+	case "postgres":
+		postgresStore := ratelimit.NewPostgresStore(db)
+		go func() {
+			// Buckets idle for an hour are full again and can be dropped
+			for range time.Tick(10 * time.Minute) {
+				if err := postgresStore.DeleteIdle(context.Background(), time.Hour); err != nil {
+					log.Printf("Error cleaning up rate limit buckets: %v", err)
+				}
+			}
+		}()
+		rateLimitStore = postgresStore
+	default:
+		log.Fatalf("Unsupported rate limit backend %q", cfg.RateLimit.Backend)
+	}
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimitStore, rateLimitRules)
+
+	// Start webhook delivery worker
+	if cfg.Webhook.WorkerEnabled {
+		webhookWorker := webhook.NewWorker(webhookEndpointRepo, webhookDeliveryRepo, webhook.WorkerConfig{
+			MaxAttempts:  cfg.Webhook.MaxAttempts,
+			BaseDelay:    cfg.Webhook.BaseDelay,
+			MaxDelay:     cfg.Webhook.MaxDelay,
+			Timeout:      cfg.Webhook.Timeout,
+			PollInterval: cfg.Webhook.PollInterval,
+			BatchSize:    cfg.Webhook.BatchSize,
+		})
+		go webhookWorker.Run(context.Background())
+	}
+
+	// Start outbox relay
+	if cfg.Outbox.RelayEnabled {
+		var sinks []outbox.Sink
+		for _, name := range strings.Split(cfg.Outbox.Sinks, ",") {
+			switch strings.TrimSpace(name) {
+			case "webhook":
+				sinks = append(sinks, outbox.NewWebhookSink(webhookPublisher))
+			case "nats":
+				natsSink, err := outbox.NewNATSSink(cfg.Outbox.NATSURL, cfg.Outbox.NATSSubjectPrefix)
+				if err != nil {
+					log.Fatalf("Failed to connect to NATS: %v", err)
+				}
+				defer func() {
+					if err := natsSink.Close(); err != nil {
+						log.Printf("Error closing NATS connection: %v", err)
+					}
+				}()
+				sinks = append(sinks, natsSink)
+			case "stdout":
+				sinks = append(sinks, outbox.NewStdoutSink(os.Stdout))
+			case "":
+			default:
+				log.Fatalf("Unsupported outbox sink %q", name)
+			}
+		}
+
+		relay := outbox.NewRelay(outboxRepo, sinks, outbox.RelayConfig{
+			PollInterval: cfg.Outbox.PollInterval,
+			BatchSize:    cfg.Outbox.BatchSize,
+			BaseDelay:    cfg.Outbox.BaseDelay,
+			MaxDelay:     cfg.Outbox.MaxDelay,
+			Retention:    cfg.Outbox.Retention,
+		})
+		go relay.Run(context.Background())
+	}
 
 	// Setup router
-	router := httpHandler.NewRouter(authHandler, adminHandler, webHandler, authMiddleware, logMiddleware, corsMiddleware)
+	router := httpHandler.NewRouter(authHandler, adminHandler, auditHandler, webhookHandler, roleHandler, passwordHandler, accountHandler, webHandler, bffHandler, authMiddleware, csrfMiddleware, logMiddleware, corsMiddleware, realIPMiddleware, deviceMiddleware, rateLimitMiddleware, cfg.StepUp.MaxAge)
 	httpHandler := router.Setup()
 
 	// Start server
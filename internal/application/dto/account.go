@@ -0,0 +1,24 @@
+package dto
+
+// ChangeEmailRequest represents an authenticated email change
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email" validate:"required,email"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+// ConfirmEmailChangeRequest represents the verification of a new email address
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ReauthenticateRequest represents a re-authentication of the signed-in user before a high-risk operation
+type ReauthenticateRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// StepUpResponse carries a short-lived access token proving a recent re-authentication
+type StepUpResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// Actor identifies who performs an action, for the audit trail
+type Actor struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+	// Permissions are those the actor holds, on routes guarded by a permission
+	Permissions []entity.Permission
+}
+
+// RoleRequest represents a role assignment or revocation request
+type RoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// CreateUserRequest represents an admin request to create (invite) a user
+type CreateUserRequest struct {
+	Email string   `json:"email" validate:"required,email"`
+	Roles []string `json:"roles" validate:"omitempty,dive,required"`
+}
+
+// UpdateUserRequest represents an admin update of a user; omitted fields are left unchanged
+type UpdateUserRequest struct {
+	Email *string  `json:"email,omitempty" validate:"omitempty,email"`
+	Roles []string `json:"roles,omitempty" validate:"omitempty,dive,required"`
+}
+
+// AdminUserResponse represents a user as seen by admins
+type AdminUserResponse struct {
+	ID          string   `json:"id"`
+	Email       string   `json:"email"`
+	Roles       []string `json:"roles"`
+	IsActive    bool     `json:"is_active"`
+	HasPassword bool     `json:"has_password"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	LastLoginAt *string  `json:"last_login_at,omitempty"`
+}
+
+// UserListResponse represents one page of users
+type UserListResponse struct {
+	Users []AdminUserResponse `json:"users"`
+	// NextCursor is passed as the cursor parameter to fetch the next page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Total is the number of users matching the filters
+	Total int `json:"total"`
+}
+
+// AuditEventResponse represents an audit trail entry
+type AuditEventResponse struct {
+	Sequence  int64                  `json:"sequence"`
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	ActorID   *string                `json:"actor_id,omitempty"`
+	SubjectID *string                `json:"subject_id,omitempty"`
+	IPAddress string                 `json:"ip_address"`
+	UserAgent string                 `json:"user_agent"`
+	Details   map[string]interface{} `json:"details"`
+	CreatedAt string                 `json:"created_at"`
+	Hash      string                 `json:"hash"`
+}
+
+// AuditEventListResponse represents one page of audit events, newest first
+type AuditEventListResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	// NextCursor is passed as the cursor parameter to fetch older events; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// AuditIntegrityReport is the result of verifying the audit trail hash chain
+type AuditIntegrityReport struct {
+	Valid   bool `json:"valid"`
+	Checked int  `json:"checked"`
+	// BrokenAtSequence is the first event that failed verification
+	BrokenAtSequence *int64 `json:"broken_at_sequence,omitempty"`
+	Reason           string `json:"reason,omitempty"`
+}
+
+// Broken marks the report as failed at the given event
+func (r *AuditIntegrityReport) Broken(sequence int64, reason string) *AuditIntegrityReport {
+	r.Valid = false
+	r.BrokenAtSequence = &sequence
+	r.Reason = reason
+	return r
+}
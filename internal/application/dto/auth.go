@@ -2,20 +2,36 @@ package dto
 
 // RegisterRequest represents user registration request
 type RegisterRequest struct {
-	Email    string   `json:"email" validate:"required,email"`
-	Password string   `json:"password" validate:"required,min=8"`
-	Roles    []string `json:"roles" validate:"omitempty,dive,oneof=user moderator admin"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+
+	// Request metadata, filled in by the HTTP layer
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 // LoginRequest represents user login request
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// DeviceName optionally labels the new session (e.g. "Work laptop")
+	DeviceName string `json:"device_name,omitempty" validate:"omitempty,max=100"`
+
+	// Request metadata, filled in by the HTTP layer
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+	ClientID  string `json:"-"`
+	// DeviceID is the ID of the browser's device cookie, if any
+	DeviceID string `json:"-"`
 }
 
 // RefreshTokenRequest represents refresh token request
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
+
+	// Request metadata, filled in by the HTTP layer
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 // AuthResponse represents authentication response
@@ -26,9 +42,33 @@ type AuthResponse struct {
 	ExpiresIn    int64  `json:"expires_in"`
 }
 
+// SessionResponse represents a session (signed-in device) of the authenticated user
+type SessionResponse struct {
+	ID         string  `json:"id"`
+	DeviceName *string `json:"device_name,omitempty"`
+	UserAgent  string  `json:"user_agent"`
+	IPAddress  string  `json:"ip_address"`
+	CreatedAt  string  `json:"created_at"`
+	LastUsedAt string  `json:"last_used_at"`
+	// Current is true for the session the request was made with
+	Current bool `json:"current"`
+}
+
+// SessionListResponse represents the active sessions of the authenticated user
+type SessionListResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
 // UserResponse represents user information response
 type UserResponse struct {
 	ID    string   `json:"id"`
 	Email string   `json:"email"`
 	Roles []string `json:"roles"`
 }
+
+// BFFSessionResponse describes a backend-for-frontend session; the tokens never leave the server
+type BFFSessionResponse struct {
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+	ExpiresAt string `json:"expires_at"`
+}
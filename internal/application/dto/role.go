@@ -0,0 +1,32 @@
+package dto
+
+// CreateRoleRequest represents an admin request to define a new role
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	// Parent is the role whose permissions the new role inherits, if any
+	Parent string `json:"parent,omitempty"`
+}
+
+// UpdateRoleRequest represents an admin update of a role; omitted fields are left unchanged
+type UpdateRoleRequest struct {
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	// Parent replaces the inherited role; an empty string removes it
+	Parent *string `json:"parent,omitempty"`
+}
+
+// RoleResponse represents a role
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	Parent      *string  `json:"parent,omitempty"`
+	// EffectivePermissions includes the permissions inherited from parent roles
+	EffectivePermissions []string `json:"effective_permissions"`
+	// BuiltIn roles cannot be deleted
+	BuiltIn   bool   `json:"built_in"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+// CreateWebhookRequest represents an admin request to subscribe an endpoint to webhook events
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1"`
+}
+
+// UpdateWebhookRequest represents an admin update of a webhook endpoint; omitted fields are left unchanged
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url,omitempty" validate:"omitempty,url"`
+	Description *string  `json:"description,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+	// RotateSecret replaces the signing secret; the new one is returned once
+	RotateSecret bool `json:"rotate_secret,omitempty"`
+}
+
+// WebhookResponse represents a webhook endpoint
+type WebhookResponse struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+	IsActive    bool     `json:"is_active"`
+	// Secret is only returned when the endpoint is created or its secret rotated
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse represents one entry of the webhook delivery log
+type WebhookDeliveryResponse struct {
+	ID             string  `json:"id"`
+	EndpointID     string  `json:"endpoint_id"`
+	EventID        string  `json:"event_id"`
+	EventType      string  `json:"event_type"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	NextAttemptAt  *string `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *string `json:"last_attempt_at,omitempty"`
+	LastStatusCode *int    `json:"last_status_code,omitempty"`
+	LastError      string  `json:"last_error,omitempty"`
+	DeliveredAt    *string `json:"delivered_at,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	// Payload is the exact body sent to the endpoint
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// WebhookDeliveryListResponse represents one page of the delivery log, newest first
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	// NextCursor is passed as the cursor parameter to fetch older deliveries; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// AssignRoleUseCase grants a role to a user (admin only)
+type AssignRoleUseCase struct {
+	userRepo    repository.UserRepository
+	roleRepo    repository.RoleRepository
+	auditLogger service.AuditLogger
+}
+
+// NewAssignRoleUseCase creates a new assign role use case
+func NewAssignRoleUseCase(userRepo repository.UserRepository, roleRepo repository.RoleRepository, auditLogger service.AuditLogger) *AssignRoleUseCase {
+	return &AssignRoleUseCase{
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute executes the assign role use case
+func (uc *AssignRoleUseCase) Execute(ctx context.Context, actor dto.Actor, userID uuid.UUID, req dto.RoleRequest) (*entity.User, error) {
+	role, err := parseRole(ctx, uc.roleRepo, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := rolePermissions(ctx, uc.roleRepo, []entity.Role{role})
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGrant(actor, permissions); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Assigning a role the user already has is a no-op
+	if user.HasRole(role) {
+		return user, nil
+	}
+
+	user.AddRole(role)
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventRoleAssigned, user.ID, map[string]interface{}{
+		"role": role.String(),
+	})
+
+	return user, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+)
+
+func TestAssignRoleUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		roles     []entity.Role
+		role      string
+		wantErr   error
+		wantRoles []entity.Role
+		wantAudit bool
+	}{
+		{name: "new role", roles: []entity.Role{entity.RoleUser}, role: "moderator", wantRoles: []entity.Role{entity.RoleUser, entity.RoleModerator}, wantAudit: true},
+		{name: "role already held", roles: []entity.Role{entity.RoleModerator}, role: "moderator", wantRoles: []entity.Role{entity.RoleModerator}},
+		{name: "unknown role", roles: []entity.Role{entity.RoleUser}, role: "root", wantErr: apperrors.ErrRoleNotFound, wantRoles: []entity.Role{entity.RoleUser}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("target@example.com", "hashed:pw")
+			user.SetRoles(tt.roles)
+			audit := &fakeAuditLogger{}
+			uc := NewAssignRoleUseCase(newFakeUserRepo(user), grantRoles(), audit)
+
+			_, err := uc.Execute(context.Background(), roleManager(), user.ID, dto.RoleRequest{Role: tt.role})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(user.Roles, tt.wantRoles) {
+				t.Errorf("roles = %v, want %v", user.Roles, tt.wantRoles)
+			}
+			if audited := len(audit.types()) == 1 && audit.types()[0] == entity.AuditEventRoleAssigned; audited != tt.wantAudit {
+				t.Errorf("audit events = %v, want role assigned %v", audit.types(), tt.wantAudit)
+			}
+		})
+	}
+}
+
+func TestRevokeRoleUseCase_Execute(t *testing.T) {
+	admin := entity.NewUser("admin@example.com", "hashed:pw")
+
+	tests := []struct {
+		name      string
+		actor     dto.Actor
+		roles     []entity.Role
+		role      string
+		wantErr   error
+		wantRoles []entity.Role
+		wantAudit bool
+	}{
+		{name: "held role", actor: roleManager(), roles: []entity.Role{entity.RoleUser, entity.RoleModerator}, role: "moderator", wantRoles: []entity.Role{entity.RoleUser}, wantAudit: true},
+		{name: "role not held", actor: roleManager(), roles: []entity.Role{entity.RoleUser}, role: "moderator", wantRoles: []entity.Role{entity.RoleUser}},
+		{name: "last role", actor: roleManager(), roles: []entity.Role{entity.RoleModerator}, role: "moderator", wantErr: apperrors.ErrInvalidInput, wantRoles: []entity.Role{entity.RoleModerator}},
+		{name: "own admin role", actor: dto.Actor{UserID: admin.ID, Permissions: entity.AllPermissions}, roles: []entity.Role{entity.RoleUser, entity.RoleAdmin}, role: "admin", wantErr: apperrors.ErrForbidden, wantRoles: []entity.Role{entity.RoleUser, entity.RoleAdmin}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := admin
+			if tt.actor.UserID != admin.ID {
+				user = entity.NewUser("target@example.com", "hashed:pw")
+			}
+			user.SetRoles(tt.roles)
+			audit := &fakeAuditLogger{}
+			uc := NewRevokeRoleUseCase(newFakeUserRepo(user), grantRoles(), audit)
+
+			_, err := uc.Execute(context.Background(), tt.actor, user.ID, dto.RoleRequest{Role: tt.role})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(user.Roles, tt.wantRoles) {
+				t.Errorf("roles = %v, want %v", user.Roles, tt.wantRoles)
+			}
+			if audited := len(audit.types()) == 1 && audit.types()[0] == entity.AuditEventRoleRevoked; audited != tt.wantAudit {
+				t.Errorf("audit events = %v, want role revoked %v", audit.types(), tt.wantAudit)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// recordAudit records an action performed by actor on subjectID.
+// A zero actor user ID records an anonymous action, a zero subjectID an action on no user.
+// Failures are logged rather than returned because the action has already happened.
+func recordAudit(
+	ctx context.Context,
+	auditLogger service.AuditLogger,
+	actor dto.Actor,
+	eventType entity.AuditEventType,
+	subjectID uuid.UUID,
+	details map[string]interface{},
+) {
+	var actorID, subject *uuid.UUID
+	if actor.UserID != uuid.Nil {
+		id := actor.UserID
+		actorID = &id
+	}
+	if subjectID != uuid.Nil {
+		subject = &subjectID
+	}
+
+	event := entity.NewAuditEvent(eventType, actorID, subject, details)
+	event.IPAddress = actor.IPAddress
+	event.UserAgent = actor.UserAgent
+
+	if err := auditLogger.Record(ctx, event); err != nil {
+		log.Printf("Failed to record audit event %s for user %s: %v", eventType, subjectID, err)
+	}
+}
+
+// parseRoles looks up and de-duplicates role names; at least one role is required
+// and every role must be defined
+func parseRoles(ctx context.Context, roleRepo repository.RoleRepository, names []string) ([]entity.Role, error) {
+	if len(names) == 0 {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	roles := make([]entity.Role, 0, len(names))
+	seen := make(map[entity.Role]bool, len(names))
+	for _, name := range names {
+		role, err := parseRole(ctx, roleRepo, name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	return roles, nil
+}
+
+// parseRole looks up a role name, failing with ErrRoleNotFound for undefined roles
+func parseRole(ctx context.Context, roleRepo repository.RoleRepository, name string) (entity.Role, error) {
+	definition, err := roleRepo.FindByName(ctx, entity.Role(name))
+	if err != nil {
+		return "", err
+	}
+	return definition.Name, nil
+}
+
+// roleNames converts roles to their string representation
+func roleNames(roles []entity.Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return names
+}
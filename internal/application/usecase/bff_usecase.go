@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+)
+
+// accessTokenRefreshMargin refreshes access tokens this long before they expire,
+// so they do not expire on their way to the upstream service
+const accessTokenRefreshMargin = 30 * time.Second
+
+// BFFUseCase handles backend-for-frontend sessions: it signs in on behalf of the browser and
+// keeps the tokens on the server, refreshing the access token when it is about to expire
+type BFFUseCase struct {
+	bffSessionRepo      repository.BFFSessionRepository
+	txManager           repository.TxManager
+	loginUseCase        *LoginUseCase
+	refreshTokenUseCase *RefreshTokenUseCase
+	logoutUseCase       *LogoutUseCase
+	tokenService        service.TokenService
+	opaqueTokens        service.OpaqueTokenService
+}
+
+// NewBFFUseCase creates a new BFF use case
+func NewBFFUseCase(
+	bffSessionRepo repository.BFFSessionRepository,
+	txManager repository.TxManager,
+	loginUseCase *LoginUseCase,
+	refreshTokenUseCase *RefreshTokenUseCase,
+	logoutUseCase *LogoutUseCase,
+	tokenService service.TokenService,
+	opaqueTokens service.OpaqueTokenService,
+) *BFFUseCase {
+	return &BFFUseCase{
+		bffSessionRepo:      bffSessionRepo,
+		txManager:           txManager,
+		loginUseCase:        loginUseCase,
+		refreshTokenUseCase: refreshTokenUseCase,
+		logoutUseCase:       logoutUseCase,
+		tokenService:        tokenService,
+		opaqueTokens:        opaqueTokens,
+	}
+}
+
+// Login signs in and starts a BFF session; it returns the raw session token for the cookie
+func (uc *BFFUseCase) Login(ctx context.Context, req dto.LoginRequest) (string, *entity.BFFSession, error) {
+	response, err := uc.loginUseCase.Execute(ctx, req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	claims, err := uc.tokenService.ValidateAccessToken(response.AccessToken)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sessionToken, err := uc.opaqueTokens.Generate()
+	if err != nil {
+		return "", nil, err
+	}
+
+	session := entity.NewBFFSession(uc.opaqueTokens.Hash(sessionToken), claims.UserID, claims.SessionID)
+	uc.setTokens(session, response)
+	if err := uc.bffSessionRepo.Create(ctx, session); err != nil {
+		return "", nil, err
+	}
+
+	return sessionToken, session, nil
+}
+
+// Session returns the BFF session of a session token with an access token that is good
+// for at least accessTokenRefreshMargin. When the refresh token is no longer accepted the
+// BFF session is ended; after other errors it is kept so the next request can retry.
+func (uc *BFFUseCase) Session(ctx context.Context, sessionToken string, actor dto.Actor) (*entity.BFFSession, error) {
+	var session *entity.BFFSession
+	var refreshErr error
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The row stays locked until commit, so concurrent requests refresh only once
+		var err error
+		session, err = uc.bffSessionRepo.FindByTokenHash(ctx, uc.opaqueTokens.Hash(sessionToken))
+		if err != nil {
+			return err
+		}
+		if !session.AccessTokenExpiresWithin(accessTokenRefreshMargin) {
+			return nil
+		}
+
+		response, err := uc.refreshTokenUseCase.Execute(ctx, dto.RefreshTokenRequest{
+			RefreshToken: session.RefreshToken,
+			IPAddress:    actor.IPAddress,
+			UserAgent:    actor.UserAgent,
+		})
+		if err != nil {
+			if !endsBFFSession(err) {
+				return err
+			}
+			// Commit anyway: the refresh may have revoked tokens, and the browser has to sign in again
+			refreshErr = err
+			return uc.bffSessionRepo.Delete(ctx, session.ID)
+		}
+
+		uc.setTokens(session, response)
+		return uc.bffSessionRepo.Update(ctx, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if refreshErr != nil {
+		return nil, refreshErr
+	}
+
+	return session, nil
+}
+
+// Logout ends a BFF session together with its underlying session
+func (uc *BFFUseCase) Logout(ctx context.Context, sessionToken string, actor dto.Actor) error {
+	session, err := uc.bffSessionRepo.FindByTokenHash(ctx, uc.opaqueTokens.Hash(sessionToken))
+	if err != nil {
+		return err
+	}
+
+	if err := uc.bffSessionRepo.Delete(ctx, session.ID); err != nil {
+		return err
+	}
+
+	actor.UserID = session.UserID
+	return uc.logoutUseCase.Execute(ctx, actor, session.SessionID, false)
+}
+
+// endsBFFSession reports whether a refresh error means the session cannot be refreshed anymore
+func endsBFFSession(err error) bool {
+	switch err {
+	case apperrors.ErrInvalidToken, apperrors.ErrTokenRevoked, apperrors.ErrExpiredToken, apperrors.ErrSessionExpired,
+		apperrors.ErrTokenReuse, apperrors.ErrUserInactive:
+		return true
+	default:
+		return false
+	}
+}
+
+// setTokens stores a token response in the BFF session
+func (uc *BFFUseCase) setTokens(session *entity.BFFSession, response *dto.AuthResponse) {
+	now := time.Now()
+	session.SetTokens(
+		response.AccessToken,
+		now.Add(time.Duration(response.ExpiresIn)*time.Second),
+		response.RefreshToken,
+		now.Add(uc.tokenService.GetRefreshTokenExpiry()),
+	)
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+)
+
+func TestBFFUseCase_Session(t *testing.T) {
+	errDatabase := errors.New("database unavailable")
+
+	tests := []struct {
+		name             string
+		prepare          func(t *testing.T, f *refreshFixture)
+		wantErr          error
+		wantRefreshToken string
+		wantDeleted      bool
+	}{
+		{
+			name:             "refreshes an expiring access token",
+			wantRefreshToken: "login-token.next",
+		},
+		{
+			// The next request retries with the same refresh token
+			name:             "keeps the session after a transient error",
+			prepare:          func(t *testing.T, f *refreshFixture) { f.sessions.findErr = errDatabase },
+			wantErr:          errDatabase,
+			wantRefreshToken: "login-token",
+		},
+		{
+			name: "ends the session when the refresh token was reused",
+			prepare: func(t *testing.T, f *refreshFixture) {
+				if _, err := f.refreshWith("login-token"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr:     apperrors.ErrTokenReuse,
+			wantDeleted: true,
+		},
+		{
+			name:        "ends the session of an inactive user",
+			prepare:     func(t *testing.T, f *refreshFixture) { f.user.Deactivate() },
+			wantErr:     apperrors.ErrUserInactive,
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRefreshFixture(t, 0)
+			opaqueTokens := &fakeOpaqueTokens{}
+			bffSession := entity.NewBFFSession(opaqueTokens.Hash("cookie-token"), f.user.ID, f.session.ID)
+			bffSession.SetTokens("access-token", time.Now(), "login-token", time.Now().Add(time.Hour))
+			bffSessions := newFakeBFFSessionRepo(bffSession)
+			uc := NewBFFUseCase(bffSessions, &fakeTxManager{}, nil, f.refresh, nil, fakeTokenService{}, opaqueTokens)
+			if tt.prepare != nil {
+				tt.prepare(t, f)
+			}
+
+			_, err := uc.Session(context.Background(), "cookie-token", dto.Actor{IPAddress: "203.0.113.7", UserAgent: "test"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Session() error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, err := bffSessions.FindByTokenHash(context.Background(), bffSession.TokenHash)
+			if deleted := err == apperrors.ErrSessionNotFound; deleted != tt.wantDeleted {
+				t.Fatalf("session deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if !tt.wantDeleted && stored.RefreshToken != tt.wantRefreshToken {
+				t.Errorf("RefreshToken = %q, want %q", stored.RefreshToken, tt.wantRefreshToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	"auth-go/internal/domain/valueobject"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// ChangeEmailUseCase starts an email change for an authenticated user.
+// The new address only takes effect once it has been verified (see ConfirmEmailChangeUseCase).
+type ChangeEmailUseCase struct {
+	userRepo          repository.UserRepository
+	verificationRepo  repository.VerificationTokenRepository
+	passwordHasher    service.PasswordHasher
+	loginThrottle     *LoginThrottle
+	opaqueTokens      service.OpaqueTokenService
+	emailSender       service.EmailSender
+	verifyTokenExpiry time.Duration
+	verifyEmailURL    string
+}
+
+// NewChangeEmailUseCase creates a new change email use case.
+// verifyEmailURL is the page the emailed link points to; the token is appended as a query parameter.
+func NewChangeEmailUseCase(
+	userRepo repository.UserRepository,
+	verificationRepo repository.VerificationTokenRepository,
+	passwordHasher service.PasswordHasher,
+	loginThrottle *LoginThrottle,
+	opaqueTokens service.OpaqueTokenService,
+	emailSender service.EmailSender,
+	verifyTokenExpiry time.Duration,
+	verifyEmailURL string,
+) *ChangeEmailUseCase {
+	return &ChangeEmailUseCase{
+		userRepo:          userRepo,
+		verificationRepo:  verificationRepo,
+		passwordHasher:    passwordHasher,
+		loginThrottle:     loginThrottle,
+		opaqueTokens:      opaqueTokens,
+		emailSender:       emailSender,
+		verifyTokenExpiry: verifyTokenExpiry,
+		verifyEmailURL:    verifyEmailURL,
+	}
+}
+
+// Execute executes the change email use case for the actor, signed in with session sessionID.
+// Once the change is confirmed, all sessions except this one are revoked.
+// Wrong current passwords count towards the login lockout.
+func (uc *ChangeEmailUseCase) Execute(ctx context.Context, actor dto.Actor, sessionID uuid.UUID, req dto.ChangeEmailRequest) error {
+	// Validate email
+	newEmail, err := valueobject.NewEmail(req.NewEmail)
+	if err != nil {
+		return apperrors.ErrInvalidEmail
+	}
+
+	// Get user
+	user, err := uc.userRepo.FindByID(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsActive {
+		return apperrors.ErrUserInactive
+	}
+
+	// Verify current password
+	if err := verifyCurrentPassword(ctx, uc.loginThrottle, uc.passwordHasher, actor, user, req.CurrentPassword); err != nil {
+		return err
+	}
+
+	if newEmail.Value() == user.Email {
+		return apperrors.ErrInvalidInput
+	}
+
+	exists, err := uc.userRepo.ExistsByEmail(ctx, newEmail.Value())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return apperrors.ErrUserAlreadyExists
+	}
+
+	// Only the most recently requested change stays pending
+	if err := uc.verificationRepo.InvalidateByUserID(ctx, user.ID, entity.TokenPurposeEmailChange); err != nil {
+		return err
+	}
+
+	tokenStr, err := uc.opaqueTokens.Generate()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(uc.verifyTokenExpiry)
+	token := entity.NewVerificationToken(user.ID, entity.TokenPurposeEmailChange, uc.opaqueTokens.Hash(tokenStr), expiresAt)
+	pending := newEmail.Value()
+	token.NewEmail = &pending
+	if sessionID != uuid.Nil {
+		token.SessionID = &sessionID
+	}
+	if err := uc.verificationRepo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	link := uc.verifyEmailURL + "?token=" + url.QueryEscape(tokenStr)
+	if err := uc.emailSender.Send(ctx, service.EmailMessage{
+		To:      newEmail.Value(),
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf(
+			"Confirm that you want to use this address for your account by opening the link below.\n"+
+				"The link expires in %s and can only be used once.\n\n%s",
+			uc.verifyTokenExpiry, link,
+		),
+	}); err != nil {
+		return err
+	}
+
+	// Let the current owner of the account know
+	return uc.emailSender.Send(ctx, service.EmailMessage{
+		To:      user.Email,
+		Subject: "Email change requested",
+		Body: fmt.Sprintf(
+			"A request was made to change the email address of your account to %s.\n"+
+				"All other sessions will be signed out once the new address is confirmed.\n\n"+
+				"If you did not make this request, reset your password immediately.",
+			newEmail.Value(),
+		),
+	})
+}
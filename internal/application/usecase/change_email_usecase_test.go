@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	apperrors "auth-go/pkg/errors"
+)
+
+func TestChangeEmailUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		password     string
+		wantErr      error
+		wantFailures int
+	}{
+		{name: "correct password", password: "secret"},
+		{name: "wrong password", password: "guess", wantErr: apperrors.ErrInvalidCredentials, wantFailures: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSignedInFixture(t)
+			throttle, attempts := newTestThrottle()
+			verificationRepo := &fakeVerificationRepo{}
+			uc := NewChangeEmailUseCase(newFakeUserRepo(f.user), verificationRepo, &fakePasswordHasher{}, throttle,
+				&fakeOpaqueTokens{}, newFakeEmailSender(), time.Hour, "https://example.com/verify")
+
+			err := uc.Execute(context.Background(), dto.Actor{UserID: f.user.ID, IPAddress: "203.0.113.7"}, f.current,
+				dto.ChangeEmailRequest{NewEmail: "new@example.com", CurrentPassword: tt.password})
+			if err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Nothing changes until the new address is confirmed
+			if current, other := f.revoked(); current || other {
+				t.Errorf("sessions revoked = (current %v, other %v), want none before confirmation", current, other)
+			}
+			if failures := attempts.attempt(accountThrottleKey(f.user.Email)).Failures; failures != tt.wantFailures {
+				t.Errorf("recorded failures = %d, want %d", failures, tt.wantFailures)
+			}
+			if tt.wantErr == nil {
+				if len(verificationRepo.tokens) != 1 || *verificationRepo.tokens[0].SessionID != f.current {
+					t.Errorf("pending tokens = %+v, want one requested by the current session", verificationRepo.tokens)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmEmailChangeUseCase_Execute(t *testing.T) {
+	f := newSignedInFixture(t)
+	userRepo := newFakeUserRepo(f.user)
+	verificationRepo := &fakeVerificationRepo{}
+	opaqueTokens := &fakeOpaqueTokens{}
+	emailSender := newFakeEmailSender()
+	throttle, _ := newTestThrottle()
+
+	change := NewChangeEmailUseCase(userRepo, verificationRepo, &fakePasswordHasher{}, throttle, opaqueTokens, emailSender, time.Hour, "https://example.com/verify")
+	if err := change.Execute(context.Background(), dto.Actor{UserID: f.user.ID}, f.current,
+		dto.ChangeEmailRequest{NewEmail: "new@example.com", CurrentPassword: "secret"}); err != nil {
+		t.Fatalf("change Execute() error = %v", err)
+	}
+
+	msg := <-emailSender.sent
+	const prefix = "https://example.com/verify?token="
+	i := strings.Index(msg.Body, prefix)
+	if i < 0 {
+		t.Fatalf("email = %+v, want a verification link", msg)
+	}
+	tokenStr := strings.Fields(msg.Body[i+len(prefix):])[0]
+
+	confirm := NewConfirmEmailChangeUseCase(userRepo, verificationRepo, f.tokens, opaqueTokens)
+	if err := confirm.Execute(context.Background(), dto.ConfirmEmailChangeRequest{Token: tokenStr}); err != nil {
+		t.Fatalf("confirm Execute() error = %v", err)
+	}
+
+	if f.user.Email != "new@example.com" {
+		t.Errorf("Email = %q, want new@example.com", f.user.Email)
+	}
+	if current, other := f.revoked(); current || !other {
+		t.Errorf("sessions revoked = (current %v, other %v), want only the other session", current, other)
+	}
+}
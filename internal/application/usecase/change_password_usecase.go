@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// ChangePasswordUseCase handles a password change by an authenticated user
+type ChangePasswordUseCase struct {
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	passwordHasher   service.PasswordHasher
+	passwordPolicy   service.PasswordPolicy
+	loginThrottle    *LoginThrottle
+	emailSender      service.EmailSender
+}
+
+// NewChangePasswordUseCase creates a new change password use case
+func NewChangePasswordUseCase(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	passwordHasher service.PasswordHasher,
+	passwordPolicy service.PasswordPolicy,
+	loginThrottle *LoginThrottle,
+	emailSender service.EmailSender,
+) *ChangePasswordUseCase {
+	return &ChangePasswordUseCase{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		passwordHasher:   passwordHasher,
+		passwordPolicy:   passwordPolicy,
+		loginThrottle:    loginThrottle,
+		emailSender:      emailSender,
+	}
+}
+
+// Execute executes the change password use case for the actor, signed in with session sessionID.
+// All sessions except the current one are revoked. Wrong current passwords count towards the login lockout.
+func (uc *ChangePasswordUseCase) Execute(ctx context.Context, actor dto.Actor, sessionID uuid.UUID, req dto.ChangePasswordRequest) error {
+	// Get user
+	user, err := uc.userRepo.FindByID(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsActive {
+		return apperrors.ErrUserInactive
+	}
+
+	// Verify current password
+	if err := verifyCurrentPassword(ctx, uc.loginThrottle, uc.passwordHasher, actor, user, req.CurrentPassword); err != nil {
+		return err
+	}
+
+	// Validate new password against the password policy
+	if err := uc.passwordPolicy.Validate(ctx, req.NewPassword, user); err != nil {
+		return err
+	}
+
+	// Hash password
+	passwordHash, err := uc.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	user.ChangePassword(passwordHash)
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := uc.passwordPolicy.Remember(ctx, user); err != nil {
+		return err
+	}
+
+	// Sign out other devices but keep the session that made the change
+	if err := uc.refreshTokenRepo.RevokeByUserIDExceptFamily(ctx, user.ID, sessionID); err != nil {
+		return err
+	}
+
+	return uc.emailSender.Send(ctx, service.EmailMessage{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: "The password for your account was just changed and all other sessions were signed out.\n\n" +
+			"If you did not make this change, reset your password immediately.",
+	})
+}
+
+// verifyCurrentPassword confirms the password of a signed-in user before an account change.
+// Wrong passwords count towards the login lockout, like failed logins.
+func verifyCurrentPassword(
+	ctx context.Context,
+	loginThrottle *LoginThrottle,
+	passwordHasher service.PasswordHasher,
+	actor dto.Actor,
+	user *entity.User,
+	password string,
+) error {
+	if err := loginThrottle.Check(ctx, user.Email, actor.IPAddress); err != nil {
+		return err
+	}
+
+	if err := passwordHasher.Compare(password, user.PasswordHash); err != nil || !user.HasPassword() {
+		loginThrottle.RecordFailure(ctx, user.Email, actor.IPAddress, user)
+		return apperrors.ErrInvalidCredentials
+	}
+
+	if err := loginThrottle.Reset(ctx, user.Email); err != nil {
+		log.Printf("Failed to reset login failures for user %s: %v", user.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// signedInFixture is a user with two sessions, the current one and another device
+type signedInFixture struct {
+	user    *entity.User
+	current uuid.UUID
+	other   uuid.UUID
+	tokens  *fakeRefreshTokenRepo
+}
+
+func newSignedInFixture(t *testing.T) *signedInFixture {
+	t.Helper()
+
+	f := &signedInFixture{
+		user:    entity.NewUser("ada@example.com", "hashed:secret"),
+		current: uuid.New(),
+		other:   uuid.New(),
+		tokens:  newFakeRefreshTokenRepo(),
+	}
+	for _, session := range []uuid.UUID{f.current, f.other} {
+		token := entity.NewRefreshToken(f.user.ID, "hash:"+session.String(), time.Now().Add(time.Hour), session)
+		if err := f.tokens.Create(context.Background(), token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f
+}
+
+// revoked reports which of the current and the other session were revoked
+func (f *signedInFixture) revoked() (current, other bool) {
+	tokens, _ := f.tokens.FindByUserID(context.Background(), f.user.ID)
+	for _, token := range tokens {
+		switch token.TokenFamily {
+		case f.current:
+			current = token.IsRevoked
+		case f.other:
+			other = token.IsRevoked
+		}
+	}
+	return current, other
+}
+
+// newTestThrottle locks an account after two wrong passwords
+func newTestThrottle() (*LoginThrottle, *fakeLoginAttemptRepo) {
+	attempts := newFakeLoginAttemptRepo()
+	return NewLoginThrottle(attempts, newFakeEmailSender(), LockoutPolicy{MaxAttempts: 2, LockDuration: time.Minute}), attempts
+}
+
+func TestChangePasswordUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		password     string
+		wantErr      error
+		wantFailures int
+		wantChanged  bool
+	}{
+		{name: "correct password", password: "secret", wantChanged: true},
+		{name: "wrong password", password: "guess", wantErr: apperrors.ErrInvalidCredentials, wantFailures: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSignedInFixture(t)
+			throttle, attempts := newTestThrottle()
+			uc := NewChangePasswordUseCase(newFakeUserRepo(f.user), f.tokens, &fakePasswordHasher{}, &fakePasswordPolicy{}, throttle, newFakeEmailSender())
+
+			err := uc.Execute(context.Background(), dto.Actor{UserID: f.user.ID, IPAddress: "203.0.113.7"}, f.current,
+				dto.ChangePasswordRequest{CurrentPassword: tt.password, NewPassword: "new-secret"})
+			if err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if changed := f.user.PasswordHash == "hashed:new-secret"; changed != tt.wantChanged {
+				t.Errorf("password changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if current, other := f.revoked(); current || other != tt.wantChanged {
+				t.Errorf("sessions revoked = (current %v, other %v), want (false, %v)", current, other, tt.wantChanged)
+			}
+			if failures := attempts.attempt(accountThrottleKey(f.user.Email)).Failures; failures != tt.wantFailures {
+				t.Errorf("recorded failures = %d, want %d", failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestChangePasswordUseCase_LockedAfterWrongPasswords(t *testing.T) {
+	f := newSignedInFixture(t)
+	throttle, _ := newTestThrottle()
+	uc := NewChangePasswordUseCase(newFakeUserRepo(f.user), f.tokens, &fakePasswordHasher{}, &fakePasswordPolicy{}, throttle, newFakeEmailSender())
+	actor := dto.Actor{UserID: f.user.ID, IPAddress: "203.0.113.7"}
+
+	for i := 0; i < 2; i++ {
+		if err := uc.Execute(context.Background(), actor, f.current, dto.ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "new-secret"}); err != apperrors.ErrInvalidCredentials {
+			t.Fatalf("guess %d: Execute() error = %v, want %v", i+1, err, apperrors.ErrInvalidCredentials)
+		}
+	}
+
+	// Even the right password is refused while the account is locked
+	err := uc.Execute(context.Background(), actor, f.current, dto.ChangePasswordRequest{CurrentPassword: "secret", NewPassword: "new-secret"})
+	var lockedErr *apperrors.AccountLockedError
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("Execute() error = %v, want an AccountLockedError", err)
+	}
+	if f.user.PasswordHash != "hashed:secret" {
+		t.Error("password changed while the account was locked")
+	}
+}
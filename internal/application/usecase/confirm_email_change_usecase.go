@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+)
+
+// ConfirmEmailChangeUseCase applies a pending email change once the new address is verified
+type ConfirmEmailChangeUseCase struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.VerificationTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	opaqueTokens     service.OpaqueTokenService
+}
+
+// NewConfirmEmailChangeUseCase creates a new confirm email change use case
+func NewConfirmEmailChangeUseCase(
+	userRepo repository.UserRepository,
+	verificationRepo repository.VerificationTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	opaqueTokens service.OpaqueTokenService,
+) *ConfirmEmailChangeUseCase {
+	return &ConfirmEmailChangeUseCase{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		opaqueTokens:     opaqueTokens,
+	}
+}
+
+// Execute executes the confirm email change use case.
+// All sessions except the one that requested the change are revoked.
+func (uc *ConfirmEmailChangeUseCase) Execute(ctx context.Context, req dto.ConfirmEmailChangeRequest) error {
+	// Find verification token by its hash
+	token, err := uc.verificationRepo.FindByTokenHash(ctx, uc.opaqueTokens.Hash(req.Token))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidToken) {
+			return apperrors.ErrInvalidToken
+		}
+		return err
+	}
+
+	if token.Purpose != entity.TokenPurposeEmailChange || token.NewEmail == nil || token.IsUsed() {
+		return apperrors.ErrInvalidToken
+	}
+
+	if token.IsExpired() {
+		return apperrors.ErrExpiredToken
+	}
+
+	// Get user
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return apperrors.ErrInvalidToken
+	}
+
+	if !user.IsActive {
+		return apperrors.ErrUserInactive
+	}
+
+	// Consume token first so concurrent requests cannot use it twice
+	if err := uc.verificationRepo.Consume(ctx, token.ID); err != nil {
+		return err
+	}
+
+	// The unique constraint on users.email rejects addresses taken in the meantime
+	user.VerifyEmail(*token.NewEmail)
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// Sign out other devices but keep the session that requested the change
+	if token.SessionID == nil {
+		return uc.refreshTokenRepo.RevokeByUserID(ctx, user.ID)
+	}
+	return uc.refreshTokenRepo.RevokeByUserIDExceptFamily(ctx, user.ID, *token.SessionID)
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// CreateRoleUseCase defines a new role (admin only)
+type CreateRoleUseCase struct {
+	roleRepo    repository.RoleRepository
+	auditLogger service.AuditLogger
+}
+
+// NewCreateRoleUseCase creates a new create role use case
+func NewCreateRoleUseCase(roleRepo repository.RoleRepository, auditLogger service.AuditLogger) *CreateRoleUseCase {
+	return &CreateRoleUseCase{
+		roleRepo:    roleRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute executes the create role use case
+func (uc *CreateRoleUseCase) Execute(ctx context.Context, actor dto.Actor, req dto.CreateRoleRequest) (*entity.RoleDefinition, error) {
+	if !entity.IsValidRoleName(req.Name) {
+		return nil, apperrors.ErrInvalidInput
+	}
+	name := entity.Role(req.Name)
+
+	permissions, err := parsePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := parseParentRole(ctx, uc.roleRepo, name, req.Parent)
+	if err != nil {
+		return nil, err
+	}
+
+	role := entity.NewRoleDefinition(name, strings.TrimSpace(req.Description), permissions, parent)
+
+	granted, err := definitionPermissions(ctx, uc.roleRepo, role)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGrant(actor, granted); err != nil {
+		return nil, err
+	}
+
+	if err := uc.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventRoleCreated, uuid.Nil, map[string]interface{}{
+		"role":        role.Name.String(),
+		"permissions": req.Permissions,
+		"parent":      req.Parent,
+	})
+
+	return role, nil
+}
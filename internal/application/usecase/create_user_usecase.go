@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	"auth-go/internal/domain/valueobject"
+	apperrors "auth-go/pkg/errors"
+)
+
+// CreateUserUseCase creates a user on behalf of an admin and emails them an invite
+// to choose their password (admin only)
+type CreateUserUseCase struct {
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	verificationRepo repository.VerificationTokenRepository
+	opaqueTokens     service.OpaqueTokenService
+	emailSender      service.EmailSender
+	auditLogger      service.AuditLogger
+	inviteExpiry     time.Duration
+	inviteURL        string
+}
+
+// NewCreateUserUseCase creates a new create user use case.
+// inviteURL is the page the emailed link points to; the token is appended as a query parameter.
+func NewCreateUserUseCase(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	verificationRepo repository.VerificationTokenRepository,
+	opaqueTokens service.OpaqueTokenService,
+	emailSender service.EmailSender,
+	auditLogger service.AuditLogger,
+	inviteExpiry time.Duration,
+	inviteURL string,
+) *CreateUserUseCase {
+	return &CreateUserUseCase{
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		verificationRepo: verificationRepo,
+		opaqueTokens:     opaqueTokens,
+		emailSender:      emailSender,
+		auditLogger:      auditLogger,
+		inviteExpiry:     inviteExpiry,
+		inviteURL:        inviteURL,
+	}
+}
+
+// Execute executes the create user use case
+func (uc *CreateUserUseCase) Execute(ctx context.Context, actor dto.Actor, req dto.CreateUserRequest) (*entity.User, error) {
+	email, err := valueobject.NewEmail(req.Email)
+	if err != nil {
+		return nil, apperrors.ErrInvalidEmail
+	}
+
+	names := req.Roles
+	if len(names) == 0 {
+		names = []string{entity.RoleUser.String()}
+	}
+	roles, err := parseRoles(ctx, uc.roleRepo, names)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := rolePermissions(ctx, uc.roleRepo, roles)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGrant(actor, permissions); err != nil {
+		return nil, err
+	}
+
+	exists, err := uc.userRepo.ExistsByEmail(ctx, email.Value())
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, apperrors.ErrUserAlreadyExists
+	}
+
+	// The user has no password until the invite is accepted
+	user := entity.NewUser(email.Value(), "")
+	user.Roles = roles
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	tokenStr, err := uc.opaqueTokens.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(uc.inviteExpiry)
+	token := entity.NewVerificationToken(user.ID, entity.TokenPurposeInvite, uc.opaqueTokens.Hash(tokenStr), expiresAt)
+	if err := uc.verificationRepo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventUserCreated, user.ID, map[string]interface{}{
+		"email": user.Email,
+		"roles": roleNames(user.Roles),
+	})
+
+	link := uc.inviteURL + "?token=" + url.QueryEscape(tokenStr)
+	if err := uc.emailSender.Send(ctx, service.EmailMessage{
+		To:      user.Email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf(
+			"An account has been created for you.\n\n"+
+				"Use the link below to choose your password. The link expires in %s and can only be used once.\n\n%s",
+			uc.inviteExpiry, link,
+		),
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
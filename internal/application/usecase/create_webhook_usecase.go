@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// CreateWebhookUseCase subscribes an endpoint to webhook events (admin only)
+type CreateWebhookUseCase struct {
+	endpointRepo repository.WebhookEndpointRepository
+	opaqueTokens service.OpaqueTokenService
+	auditLogger  service.AuditLogger
+}
+
+// NewCreateWebhookUseCase creates a new create webhook use case
+func NewCreateWebhookUseCase(
+	endpointRepo repository.WebhookEndpointRepository,
+	opaqueTokens service.OpaqueTokenService,
+	auditLogger service.AuditLogger,
+) *CreateWebhookUseCase {
+	return &CreateWebhookUseCase{
+		endpointRepo: endpointRepo,
+		opaqueTokens: opaqueTokens,
+		auditLogger:  auditLogger,
+	}
+}
+
+// Execute executes the create webhook use case
+func (uc *CreateWebhookUseCase) Execute(ctx context.Context, actor dto.Actor, req dto.CreateWebhookRequest) (*entity.WebhookEndpoint, error) {
+	webhookURL := strings.TrimSpace(req.URL)
+	if err := validateWebhookURL(webhookURL); err != nil {
+		return nil, err
+	}
+
+	eventTypes, err := parseWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret(uc.opaqueTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := entity.NewWebhookEndpoint(webhookURL, strings.TrimSpace(req.Description), secret, eventTypes)
+	if err := uc.endpointRepo.Create(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventWebhookCreated, uuid.Nil, map[string]interface{}{
+		"webhook_id":  endpoint.ID.String(),
+		"url":         endpoint.URL,
+		"event_types": req.EventTypes,
+	})
+
+	return endpoint, nil
+}
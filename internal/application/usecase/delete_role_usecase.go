@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// DeleteRoleUseCase removes a role that is neither built in nor in use (admin only)
+type DeleteRoleUseCase struct {
+	roleRepo    repository.RoleRepository
+	auditLogger service.AuditLogger
+}
+
+// NewDeleteRoleUseCase creates a new delete role use case
+func NewDeleteRoleUseCase(roleRepo repository.RoleRepository, auditLogger service.AuditLogger) *DeleteRoleUseCase {
+	return &DeleteRoleUseCase{
+		roleRepo:    roleRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute executes the delete role use case
+func (uc *DeleteRoleUseCase) Execute(ctx context.Context, actor dto.Actor, name string) error {
+	role := entity.Role(name)
+	if role.IsBuiltIn() {
+		return apperrors.ErrForbidden
+	}
+
+	// Roles held by users or inherited by other roles are rejected by the repository
+	if err := uc.roleRepo.Delete(ctx, role); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventRoleDeleted, uuid.Nil, map[string]interface{}{
+		"role": role.String(),
+	})
+
+	return nil
+}
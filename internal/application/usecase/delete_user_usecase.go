@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// DeleteUserUseCase permanently deletes a user and their tokens (admin only)
+type DeleteUserUseCase struct {
+	userRepo    repository.UserRepository
+	roleRepo    repository.RoleRepository
+	auditLogger service.AuditLogger
+}
+
+// NewDeleteUserUseCase creates a new delete user use case
+func NewDeleteUserUseCase(userRepo repository.UserRepository, roleRepo repository.RoleRepository, auditLogger service.AuditLogger) *DeleteUserUseCase {
+	return &DeleteUserUseCase{
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute executes the delete user use case
+func (uc *DeleteUserUseCase) Execute(ctx context.Context, actor dto.Actor, userID uuid.UUID) error {
+	// Admins cannot delete their own account
+	if actor.UserID == userID {
+		return apperrors.ErrForbidden
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeTarget(ctx, uc.roleRepo, actor, user); err != nil {
+		return err
+	}
+
+	// Refresh and verification tokens are removed by ON DELETE CASCADE
+	if err := uc.userRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventUserDeleted, user.ID, map[string]interface{}{
+		"email": user.Email,
+	})
+
+	return nil
+}
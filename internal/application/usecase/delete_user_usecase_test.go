@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestDeleteUserUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		self        bool
+		unknown     bool
+		wantErr     error
+		wantDeleted bool
+	}{
+		{name: "other user", wantDeleted: true},
+		{name: "own account", self: true, wantErr: apperrors.ErrForbidden},
+		{name: "unknown user", unknown: true, wantErr: apperrors.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("target@example.com", "hashed:pw")
+			userRepo := newFakeUserRepo(user)
+			actor := roleManager()
+			if tt.self {
+				actor.UserID = user.ID
+			}
+			userID := user.ID
+			if tt.unknown {
+				userID = uuid.New()
+			}
+			audit := &fakeAuditLogger{}
+			uc := NewDeleteUserUseCase(userRepo, grantRoles(), audit)
+
+			err := uc.Execute(context.Background(), actor, userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := userRepo.FindByID(context.Background(), user.ID); (err != nil) != tt.wantDeleted {
+				t.Errorf("user deleted = %v, want %v", err != nil, tt.wantDeleted)
+			}
+			if tt.wantDeleted {
+				if len(audit.events) != 1 || audit.events[0].Type != entity.AuditEventUserDeleted || audit.events[0].Details["email"] != user.Email {
+					t.Errorf("audit events = %+v, want the deletion with the email", audit.events)
+				}
+			} else if len(audit.events) != 0 {
+				t.Errorf("audit events = %v, want none", audit.types())
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// DeleteWebhookUseCase removes a webhook endpoint and its delivery log (admin only)
+type DeleteWebhookUseCase struct {
+	endpointRepo repository.WebhookEndpointRepository
+	auditLogger  service.AuditLogger
+}
+
+// NewDeleteWebhookUseCase creates a new delete webhook use case
+func NewDeleteWebhookUseCase(endpointRepo repository.WebhookEndpointRepository, auditLogger service.AuditLogger) *DeleteWebhookUseCase {
+	return &DeleteWebhookUseCase{
+		endpointRepo: endpointRepo,
+		auditLogger:  auditLogger,
+	}
+}
+
+// Execute executes the delete webhook use case
+func (uc *DeleteWebhookUseCase) Execute(ctx context.Context, actor dto.Actor, webhookID uuid.UUID) error {
+	endpoint, err := uc.endpointRepo.FindByID(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+
+	// Pending deliveries are removed by ON DELETE CASCADE
+	if err := uc.endpointRepo.Delete(ctx, endpoint.ID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventWebhookDeleted, uuid.Nil, map[string]interface{}{
+		"webhook_id": endpoint.ID.String(),
+		"url":        endpoint.URL,
+	})
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+)
+
+// DeviceMonitorPolicy configures suspicious login detection
+type DeviceMonitorPolicy struct {
+	// NotifyNewDevices emails the user when they sign in from a device not seen before
+	NotifyNewDevices bool
+	// MaxTravelSpeedKmh flags consecutive sign ins further apart than this speed allows
+	// (0 disables the impossible travel check)
+	MaxTravelSpeedKmh float64
+}
+
+// DeviceMonitor remembers the devices users sign in from and reports sign ins from new
+// devices, and from places the user cannot have travelled to since their last sign in
+type DeviceMonitor struct {
+	deviceRepo   repository.KnownDeviceRepository
+	opaqueTokens service.OpaqueTokenService
+	// geoIP is nil when no GeoIP database is configured
+	geoIP       service.GeoIPResolver
+	emailSender service.EmailSender
+	auditLogger service.AuditLogger
+	policy      DeviceMonitorPolicy
+}
+
+// NewDeviceMonitor creates a new device monitor; geoIP may be nil
+func NewDeviceMonitor(
+	deviceRepo repository.KnownDeviceRepository,
+	opaqueTokens service.OpaqueTokenService,
+	geoIP service.GeoIPResolver,
+	emailSender service.EmailSender,
+	auditLogger service.AuditLogger,
+	policy DeviceMonitorPolicy,
+) *DeviceMonitor {
+	return &DeviceMonitor{
+		deviceRepo:   deviceRepo,
+		opaqueTokens: opaqueTokens,
+		geoIP:        geoIP,
+		emailSender:  emailSender,
+		auditLogger:  auditLogger,
+		policy:       policy,
+	}
+}
+
+// Observe records a successful sign in of the user. Failures are logged, never returned,
+// so they cannot block the sign in.
+func (m *DeviceMonitor) Observe(ctx context.Context, user *entity.User, actor dto.Actor, deviceID string) {
+	var location *entity.GeoLocation
+	if m.geoIP != nil {
+		location = m.geoIP.Lookup(actor.IPAddress)
+	}
+
+	// The previous sign in, from whichever device, is where the user was last seen
+	lastSeen, err := m.deviceRepo.FindLastSeenByUserID(ctx, user.ID)
+	if err != nil && err != apperrors.ErrDeviceNotFound {
+		log.Printf("Failed to load known devices of user %s: %v", user.ID, err)
+		return
+	}
+	travelled := lastSeen != nil && lastSeen.IsImpossibleTravel(location, m.policy.MaxTravelSpeedKmh)
+	if travelled {
+		m.reportImpossibleTravel(ctx, user, actor, lastSeen, location)
+	}
+
+	fingerprint := m.opaqueTokens.Hash(entity.DeviceSignature(actor.UserAgent, actor.IPAddress, deviceID))
+	device, err := m.deviceRepo.FindByFingerprint(ctx, user.ID, fingerprint)
+	switch err {
+	case nil:
+		device.Seen(actor.IPAddress, location)
+		if err := m.deviceRepo.Update(ctx, device); err != nil {
+			log.Printf("Failed to update known device of user %s: %v", user.ID, err)
+		}
+	case apperrors.ErrDeviceNotFound:
+		device = entity.NewKnownDevice(user.ID, fingerprint, actor.UserAgent, actor.IPAddress, location)
+		if err := m.deviceRepo.Create(ctx, device); err != nil {
+			log.Printf("Failed to save known device of user %s: %v", user.ID, err)
+			return
+		}
+		// The first device of a user is not news; an impossible travel email already covers this one
+		if lastSeen != nil {
+			m.reportNewDevice(ctx, user, actor, location, m.policy.NotifyNewDevices && !travelled)
+		}
+	default:
+		log.Printf("Failed to look up known device of user %s: %v", user.ID, err)
+	}
+}
+
+// reportNewDevice records and notifies a sign in from a device not seen before
+func (m *DeviceMonitor) reportNewDevice(ctx context.Context, user *entity.User, actor dto.Actor, location *entity.GeoLocation, notify bool) {
+	details := map[string]interface{}{}
+	if location != nil {
+		details["country"] = location.Country
+	}
+	recordAudit(ctx, m.auditLogger, actor, entity.AuditEventNewDeviceLogin, user.ID, details)
+
+	if !notify {
+		return
+	}
+	m.notify(ctx, user, "New sign-in to your account",
+		"Your account was just signed in to from a device we have not seen before.", actor, location)
+}
+
+// reportImpossibleTravel records and notifies a sign in too far from the previous one
+func (m *DeviceMonitor) reportImpossibleTravel(ctx context.Context, user *entity.User, actor dto.Actor, lastSeen *entity.KnownDevice, location *entity.GeoLocation) {
+	recordAudit(ctx, m.auditLogger, actor, entity.AuditEventImpossibleTravel, user.ID, map[string]interface{}{
+		"previous_country": lastSeen.Location.Country,
+		"country":          location.Country,
+		"distance_km":      int(lastSeen.Location.DistanceKm(*location)),
+		"elapsed_minutes":  int(time.Since(lastSeen.LastSeenAt).Minutes()),
+	})
+
+	m.notify(ctx, user, "Suspicious sign-in to your account",
+		fmt.Sprintf("Your account was just signed in to from a location %d km away from your previous sign-in %s ago, "+
+			"which is further than anyone could travel in that time.",
+			int(lastSeen.Location.DistanceKm(*location)), time.Since(lastSeen.LastSeenAt).Round(time.Minute)),
+		actor, location)
+}
+
+// notify emails the user about a sign in
+func (m *DeviceMonitor) notify(ctx context.Context, user *entity.User, subject, summary string, actor dto.Actor, location *entity.GeoLocation) {
+	where := "unknown location"
+	if location != nil && location.Country != "" {
+		where = location.Country
+	}
+
+	err := m.emailSender.Send(ctx, service.EmailMessage{
+		To:      user.Email,
+		Subject: subject,
+		Body: summary + "\n\n" +
+			"Time: " + time.Now().UTC().Format(time.RFC1123) + "\n" +
+			"IP address: " + actor.IPAddress + " (" + where + ")\n" +
+			"Device: " + actor.UserAgent + "\n\n" +
+			"If this was you, you can ignore this email. Otherwise change your password immediately " +
+			"and sign out the device from your profile.",
+	})
+	if err != nil {
+		log.Printf("Failed to send sign-in notification to user %s: %v", user.ID, err)
+	}
+}
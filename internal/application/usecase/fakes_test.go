@@ -0,0 +1,642 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// fakeUserRepo is an in-memory UserRepository
+type fakeUserRepo struct {
+	mu    sync.Mutex
+	users map[uuid.UUID]*entity.User
+}
+
+func newFakeUserRepo(users ...*entity.User) *fakeUserRepo {
+	repo := &fakeUserRepo{users: make(map[uuid.UUID]*entity.User)}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.users {
+		if existing.Email == user.Email {
+			return apperrors.ErrUserAlreadyExists
+		}
+	}
+	r.users[user.ID] = user
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.users, user.ID)
+	})
+	return nil
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, apperrors.ErrUserNotFound
+}
+
+func (r *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, apperrors.ErrUserNotFound
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeUserRepo) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	_, err := r.FindByEmail(ctx, email)
+	return err == nil, nil
+}
+
+func (r *fakeUserRepo) Search(ctx context.Context, query repository.UserQuery) (*repository.UserPage, error) {
+	return &repository.UserPage{}, nil
+}
+
+// fakeTx collects the undo functions of the writes made in a transaction
+type fakeTx struct {
+	mu   sync.Mutex
+	undo []func()
+}
+
+type fakeTxKey struct{}
+
+// fakeTxManager runs units of work in fake transactions: writes made through fakes
+// register undo functions, which run in reverse order when the work fails
+type fakeTxManager struct {
+	mu        sync.Mutex
+	commits   int
+	rollbacks int
+}
+
+func (m *fakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inFakeTx(ctx) {
+		return fn(ctx)
+	}
+
+	tx := &fakeTx{}
+	if err := fn(context.WithValue(ctx, fakeTxKey{}, tx)); err != nil {
+		for i := len(tx.undo) - 1; i >= 0; i-- {
+			tx.undo[i]()
+		}
+		m.mu.Lock()
+		m.rollbacks++
+		m.mu.Unlock()
+		return err
+	}
+
+	m.mu.Lock()
+	m.commits++
+	m.mu.Unlock()
+	return nil
+}
+
+// inFakeTx reports whether ctx carries a fake transaction
+func inFakeTx(ctx context.Context) bool {
+	_, ok := ctx.Value(fakeTxKey{}).(*fakeTx)
+	return ok
+}
+
+// onRollback registers undo to run if the transaction of ctx is rolled back
+func onRollback(ctx context.Context, undo func()) {
+	if tx, ok := ctx.Value(fakeTxKey{}).(*fakeTx); ok {
+		tx.mu.Lock()
+		tx.undo = append(tx.undo, undo)
+		tx.mu.Unlock()
+	}
+}
+
+// fakeVerificationRepo is an in-memory VerificationTokenRepository
+type fakeVerificationRepo struct {
+	mu     sync.Mutex
+	tokens []*entity.VerificationToken
+}
+
+func (r *fakeVerificationRepo) Create(ctx context.Context, token *entity.VerificationToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeVerificationRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.VerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, apperrors.ErrInvalidToken
+}
+
+func (r *fakeVerificationRepo) Consume(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+			onRollback(ctx, func() {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				token.UsedAt = nil
+			})
+			return nil
+		}
+	}
+	return apperrors.ErrInvalidToken
+}
+
+func (r *fakeVerificationRepo) InvalidateByUserID(ctx context.Context, userID uuid.UUID, purpose entity.TokenPurpose) error {
+	return nil
+}
+
+func (r *fakeVerificationRepo) DeleteExpired(ctx context.Context) error {
+	return nil
+}
+
+// fakeOpaqueTokens generates sequential tokens and hashes by prefixing
+type fakeOpaqueTokens struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (t *fakeOpaqueTokens) Generate() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.n++
+	return "token-" + strings.Repeat("x", t.n), nil
+}
+
+func (t *fakeOpaqueTokens) Hash(token string) string {
+	return "hash:" + token
+}
+
+// fakeEmailSender records sent messages on a channel
+type fakeEmailSender struct {
+	sent chan service.EmailMessage
+}
+
+func newFakeEmailSender() *fakeEmailSender {
+	return &fakeEmailSender{sent: make(chan service.EmailMessage, 16)}
+}
+
+func (s *fakeEmailSender) Send(ctx context.Context, msg service.EmailMessage) error {
+	s.sent <- msg
+	return nil
+}
+
+// fakePasswordHasher "hashes" by prefixing and counts the hashing work done
+type fakePasswordHasher struct {
+	mu       sync.Mutex
+	hashes   int
+	compares int
+}
+
+func (h *fakePasswordHasher) Hash(password string) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hashes++
+	return "hashed:" + password, nil
+}
+
+func (h *fakePasswordHasher) Compare(password, hash string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.compares++
+	if hash != "hashed:"+password {
+		return apperrors.ErrInvalidCredentials
+	}
+	return nil
+}
+
+func (h *fakePasswordHasher) NeedsRehash(hash string) bool {
+	return false
+}
+
+// work returns the number of Hash and Compare calls
+func (h *fakePasswordHasher) work() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hashes + h.compares
+}
+
+// fakePasswordPolicy accepts every password; Remember fails with rememberErr
+type fakePasswordPolicy struct {
+	rememberErr error
+	// rememberedInTx records whether Remember ran inside a transaction
+	rememberedInTx bool
+}
+
+func (p *fakePasswordPolicy) Validate(ctx context.Context, password string, user *entity.User) error {
+	return nil
+}
+
+func (p *fakePasswordPolicy) Remember(ctx context.Context, user *entity.User) error {
+	p.rememberedInTx = inFakeTx(ctx)
+	return p.rememberErr
+}
+
+// fakeAuditLogger records audit events
+type fakeAuditLogger struct {
+	mu     sync.Mutex
+	events []*entity.AuditEvent
+}
+
+func (l *fakeAuditLogger) Record(ctx context.Context, event *entity.AuditEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	return nil
+}
+
+// types returns the types of the recorded events, in order
+func (l *fakeAuditLogger) types() []entity.AuditEventType {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	types := make([]entity.AuditEventType, len(l.events))
+	for i, event := range l.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// fakeRefreshTokenRepo is an in-memory RefreshTokenRepository. It stores copies, so callers
+// only see changes they save, and it keeps the saved domain events.
+type fakeRefreshTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[uuid.UUID]entity.RefreshToken
+	events []entity.DomainEvent
+}
+
+func newFakeRefreshTokenRepo() *fakeRefreshTokenRepo {
+	return &fakeRefreshTokenRepo{tokens: make(map[uuid.UUID]entity.RefreshToken)}
+}
+
+// save stores token and its events, undoing both if the transaction of ctx rolls back
+func (r *fakeRefreshTokenRepo) save(ctx context.Context, token entity.RefreshToken, events []entity.DomainEvent) {
+	previous, existed := r.tokens[token.ID]
+	eventCount := len(r.events)
+	token.PullEvents()
+	r.tokens[token.ID] = token
+	r.events = append(r.events, events...)
+
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if existed {
+			r.tokens[token.ID] = previous
+		} else {
+			delete(r.tokens, token.ID)
+		}
+		r.events = r.events[:eventCount]
+	})
+}
+
+func (r *fakeRefreshTokenRepo) Create(ctx context.Context, token *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.save(ctx, *token, token.PullEvents())
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, apperrors.ErrInvalidToken
+}
+
+func (r *fakeRefreshTokenRepo) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tokens []*entity.RefreshToken
+	for _, token := range r.tokens {
+		if token.UserID == userID {
+			token := token
+			tokens = append(tokens, &token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeRefreshTokenRepo) Update(ctx context.Context, token *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.save(ctx, *token, token.PullEvents())
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) Rotate(ctx context.Context, token *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens[token.ID].IsRevoked {
+		return apperrors.ErrTokenRevoked
+	}
+	r.save(ctx, *token, token.PullEvents())
+	return nil
+}
+
+// revokeWhere revokes the active tokens matching the filter
+func (r *fakeRefreshTokenRepo) revokeWhere(ctx context.Context, match func(token entity.RefreshToken) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if !token.IsRevoked && match(token) {
+			token.Revoke()
+			r.save(ctx, token, token.PullEvents())
+		}
+	}
+}
+
+func (r *fakeRefreshTokenRepo) RevokeByTokenFamily(ctx context.Context, tokenFamily uuid.UUID) error {
+	r.revokeWhere(ctx, func(token entity.RefreshToken) bool { return token.TokenFamily == tokenFamily })
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.revokeWhere(ctx, func(token entity.RefreshToken) bool { return token.UserID == userID })
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeByUserIDExceptFamily(ctx context.Context, userID uuid.UUID, keepFamily uuid.UUID) error {
+	r.revokeWhere(ctx, func(token entity.RefreshToken) bool {
+		return token.UserID == userID && token.TokenFamily != keepFamily
+	})
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) DeleteExpired(ctx context.Context) error {
+	return nil
+}
+
+// eventTypes returns the types of the saved domain events
+func (r *fakeRefreshTokenRepo) eventTypes() []entity.DomainEventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]entity.DomainEventType, len(r.events))
+	for i, event := range r.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// fakeSessionRepo is an in-memory SessionRepository
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]entity.Session
+	// findErr, if set, fails FindByID
+	findErr error
+}
+
+func newFakeSessionRepo(sessions ...*entity.Session) *fakeSessionRepo {
+	repo := &fakeSessionRepo{sessions: make(map[uuid.UUID]entity.Session)}
+	for _, session := range sessions {
+		repo.sessions[session.ID] = *session
+	}
+	return repo
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session *entity.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeSessionRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.findErr != nil {
+		return nil, r.findErr
+	}
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, apperrors.ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID treats every stored session as active
+func (r *fakeSessionRepo) FindActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sessions []*entity.Session
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			session := session
+			sessions = append(sessions, &session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *fakeSessionRepo) Update(ctx context.Context, session *entity.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeSessionRepo) LockUserSessions(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+// fakeTokenService derives successors by appending ".next" and issues readable access tokens
+type fakeTokenService struct{}
+
+func (fakeTokenService) GenerateAccessToken(claims service.TokenClaims) (string, error) {
+	return "access:" + claims.SessionID.String(), nil
+}
+
+func (fakeTokenService) GenerateAccessTokenWithExpiry(claims service.TokenClaims, expiry time.Duration) (string, error) {
+	return "access:" + claims.SessionID.String(), nil
+}
+
+func (fakeTokenService) GenerateRefreshToken() (string, error) {
+	return "refresh-" + uuid.NewString(), nil
+}
+
+func (fakeTokenService) DeriveRefreshToken(parent string) string {
+	return parent + ".next"
+}
+
+func (fakeTokenService) ValidateAccessToken(token string) (*service.TokenClaims, error) {
+	return nil, apperrors.ErrInvalidToken
+}
+
+func (fakeTokenService) GetAccessTokenExpiry() time.Duration {
+	return 15 * time.Minute
+}
+
+func (fakeTokenService) GetRefreshTokenExpiry() time.Duration {
+	return 7 * 24 * time.Hour
+}
+
+// fakeRoleRepo is an in-memory RoleRepository; it hands out copies so that use cases
+// only change stored roles through Update
+type fakeRoleRepo struct {
+	mu    sync.Mutex
+	roles map[entity.Role]entity.RoleDefinition
+}
+
+func newFakeRoleRepo(roles ...*entity.RoleDefinition) *fakeRoleRepo {
+	repo := &fakeRoleRepo{roles: make(map[entity.Role]entity.RoleDefinition)}
+	for _, role := range roles {
+		repo.roles[role.Name] = *role
+	}
+	return repo
+}
+
+func (r *fakeRoleRepo) Create(ctx context.Context, role *entity.RoleDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.roles[role.Name]; ok {
+		return apperrors.ErrRoleAlreadyExists
+	}
+	r.roles[role.Name] = *role
+	return nil
+}
+
+func (r *fakeRoleRepo) FindByName(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, apperrors.ErrRoleNotFound
+	}
+	return &role, nil
+}
+
+func (r *fakeRoleRepo) FindAll(ctx context.Context) ([]*entity.RoleDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	roles := make([]*entity.RoleDefinition, 0, len(r.roles))
+	for _, role := range r.roles {
+		role := role
+		roles = append(roles, &role)
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepo) Update(ctx context.Context, role *entity.RoleDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.roles[role.Name]; !ok {
+		return apperrors.ErrRoleNotFound
+	}
+	r.roles[role.Name] = *role
+	return nil
+}
+
+func (r *fakeRoleRepo) Delete(ctx context.Context, name entity.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.roles[name]; !ok {
+		return apperrors.ErrRoleNotFound
+	}
+	delete(r.roles, name)
+	return nil
+}
+
+// fakeBFFSessionRepo is an in-memory BFFSessionRepository
+type fakeBFFSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]entity.BFFSession
+}
+
+func newFakeBFFSessionRepo(sessions ...*entity.BFFSession) *fakeBFFSessionRepo {
+	repo := &fakeBFFSessionRepo{sessions: make(map[uuid.UUID]entity.BFFSession)}
+	for _, session := range sessions {
+		repo.sessions[session.ID] = *session
+	}
+	return repo
+}
+
+// save stores a session, or deletes it if session is nil, undoing the change on rollback
+func (r *fakeBFFSessionRepo) save(ctx context.Context, id uuid.UUID, session *entity.BFFSession) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous, existed := r.sessions[id]
+	if session == nil {
+		delete(r.sessions, id)
+	} else {
+		r.sessions[id] = *session
+	}
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if existed {
+			r.sessions[id] = previous
+		} else {
+			delete(r.sessions, id)
+		}
+	})
+}
+
+func (r *fakeBFFSessionRepo) Create(ctx context.Context, session *entity.BFFSession) error {
+	r.save(ctx, session.ID, session)
+	return nil
+}
+
+func (r *fakeBFFSessionRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.BFFSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, session := range r.sessions {
+		if session.TokenHash == tokenHash && !session.IsExpired() {
+			return &session, nil
+		}
+	}
+	return nil, apperrors.ErrSessionNotFound
+}
+
+func (r *fakeBFFSessionRepo) Update(ctx context.Context, session *entity.BFFSession) error {
+	r.save(ctx, session.ID, session)
+	return nil
+}
+
+func (r *fakeBFFSessionRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.save(ctx, id, nil)
+	return nil
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// ForcePasswordResetUseCase invalidates a user's password, signs them out everywhere
+// and emails them a reset link (admin only)
+type ForcePasswordResetUseCase struct {
+	userRepo              repository.UserRepository
+	roleRepo              repository.RoleRepository
+	refreshTokenRepo      repository.RefreshTokenRepository
+	forgotPasswordUseCase *ForgotPasswordUseCase
+	auditLogger           service.AuditLogger
+}
+
+// NewForcePasswordResetUseCase creates a new force password reset use case
+func NewForcePasswordResetUseCase(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	forgotPasswordUseCase *ForgotPasswordUseCase,
+	auditLogger service.AuditLogger,
+) *ForcePasswordResetUseCase {
+	return &ForcePasswordResetUseCase{
+		userRepo:              userRepo,
+		roleRepo:              roleRepo,
+		refreshTokenRepo:      refreshTokenRepo,
+		forgotPasswordUseCase: forgotPasswordUseCase,
+		auditLogger:           auditLogger,
+	}
+}
+
+// Execute executes the force password reset use case
+func (uc *ForcePasswordResetUseCase) Execute(ctx context.Context, actor dto.Actor, userID uuid.UUID) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeTarget(ctx, uc.roleRepo, actor, user); err != nil {
+		return err
+	}
+
+	// The old password stops working immediately
+	user.ChangePassword("")
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := uc.refreshTokenRepo.RevokeByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventPasswordResetForced, user.ID, nil)
+
+	return uc.forgotPasswordUseCase.SendResetLink(ctx, user)
+}
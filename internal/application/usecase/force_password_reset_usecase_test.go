@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+func TestForcePasswordResetUseCase_Execute(t *testing.T) {
+	user := entity.NewUser("target@example.com", "hashed:pw")
+	userRepo := newFakeUserRepo(user)
+	tokens := newFakeRefreshTokenRepo()
+	if err := tokens.Create(context.Background(), entity.NewRefreshToken(user.ID, "hash:session", time.Now().Add(time.Hour), uuid.New())); err != nil {
+		t.Fatal(err)
+	}
+	emailSender := newFakeEmailSender()
+	forgot := NewForgotPasswordUseCase(userRepo, &fakeVerificationRepo{}, &fakeOpaqueTokens{}, emailSender, time.Hour, "https://example.com/reset")
+	audit := &fakeAuditLogger{}
+	uc := NewForcePasswordResetUseCase(userRepo, grantRoles(), tokens, forgot, audit)
+
+	if err := uc.Execute(context.Background(), roleManager(), user.ID); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if user.HasPassword() {
+		t.Error("the old password still works")
+	}
+	sessions, _ := tokens.FindByUserID(context.Background(), user.ID)
+	if !sessions[0].IsRevoked {
+		t.Error("sessions were not revoked")
+	}
+	if got := audit.types(); len(got) != 1 || got[0] != entity.AuditEventPasswordResetForced {
+		t.Errorf("audit events = %v, want %v", got, entity.AuditEventPasswordResetForced)
+	}
+
+	select {
+	case msg := <-emailSender.sent:
+		if msg.To != user.Email || !strings.Contains(msg.Body, "https://example.com/reset?token=") {
+			t.Errorf("email = %+v, want a reset link for %s", msg, user.Email)
+		}
+	default:
+		t.Error("no reset link was sent")
+	}
+}
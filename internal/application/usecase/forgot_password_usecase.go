@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	"auth-go/internal/domain/valueobject"
+	apperrors "auth-go/pkg/errors"
+)
+
+// ForgotPasswordUseCase issues password reset links by email
+type ForgotPasswordUseCase struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.VerificationTokenRepository
+	opaqueTokens     service.OpaqueTokenService
+	emailSender      service.EmailSender
+	resetTokenExpiry time.Duration
+	passwordResetURL string
+}
+
+// NewForgotPasswordUseCase creates a new forgot password use case.
+// passwordResetURL is the page the emailed link points to; the token is appended as a query parameter.
+func NewForgotPasswordUseCase(
+	userRepo repository.UserRepository,
+	verificationRepo repository.VerificationTokenRepository,
+	opaqueTokens service.OpaqueTokenService,
+	emailSender service.EmailSender,
+	resetTokenExpiry time.Duration,
+	passwordResetURL string,
+) *ForgotPasswordUseCase {
+	return &ForgotPasswordUseCase{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		opaqueTokens:     opaqueTokens,
+		emailSender:      emailSender,
+		resetTokenExpiry: resetTokenExpiry,
+		passwordResetURL: passwordResetURL,
+	}
+}
+
+// Execute executes the forgot password use case.
+// Unknown and inactive accounts are silently ignored so the caller cannot enumerate accounts.
+func (uc *ForgotPasswordUseCase) Execute(ctx context.Context, req dto.ForgotPasswordRequest) error {
+	// Validate email
+	email, err := valueobject.NewEmail(req.Email)
+	if err != nil {
+		return apperrors.ErrInvalidEmail
+	}
+
+	// Find user by email
+	user, err := uc.userRepo.FindByEmail(ctx, email.Value())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !user.IsActive {
+		return nil
+	}
+
+	return uc.SendResetLink(ctx, user)
+}
+
+// SendResetLink issues a new password reset token for the user and emails the link
+func (uc *ForgotPasswordUseCase) SendResetLink(ctx context.Context, user *entity.User) error {
+	// Only the most recently issued link stays valid
+	if err := uc.verificationRepo.InvalidateByUserID(ctx, user.ID, entity.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	// Generate reset token
+	tokenStr, err := uc.opaqueTokens.Generate()
+	if err != nil {
+		return err
+	}
+
+	// Save only the hash of the token
+	expiresAt := time.Now().Add(uc.resetTokenExpiry)
+	token := entity.NewVerificationToken(user.ID, entity.TokenPurposePasswordReset, uc.opaqueTokens.Hash(tokenStr), expiresAt)
+	if err := uc.verificationRepo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	link := uc.passwordResetURL + "?token=" + url.QueryEscape(tokenStr)
+
+	return uc.emailSender.Send(ctx, service.EmailMessage{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"We received a request to reset the password for your account.\n\n"+
+				"Use the link below to choose a new password. The link expires in %s and can only be used once.\n\n"+
+				"%s\n\n"+
+				"If you did not request a password reset, you can ignore this email.",
+			uc.resetTokenExpiry, link,
+		),
+	})
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+)
+
+func TestForgotPasswordUseCase_Execute(t *testing.T) {
+	active := entity.NewUser("active@example.com", "hash")
+	inactive := entity.NewUser("inactive@example.com", "hash")
+	inactive.Deactivate()
+
+	tests := []struct {
+		name     string
+		email    string
+		wantMail bool
+	}{
+		{name: "existing account", email: "active@example.com", wantMail: true},
+		{name: "unknown account", email: "unknown@example.com", wantMail: false},
+		{name: "inactive account", email: "inactive@example.com", wantMail: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verificationRepo := &fakeVerificationRepo{}
+			emailSender := newFakeEmailSender()
+			uc := NewForgotPasswordUseCase(newFakeUserRepo(active, inactive), verificationRepo, &fakeOpaqueTokens{}, emailSender, time.Hour, "https://example.com/reset")
+
+			if err := uc.Execute(context.Background(), dto.ForgotPasswordRequest{Email: tt.email}); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if !tt.wantMail {
+				if len(emailSender.sent) != 0 || len(verificationRepo.tokens) != 0 {
+					t.Fatalf("sent %d emails and issued %d tokens, want none", len(emailSender.sent), len(verificationRepo.tokens))
+				}
+				return
+			}
+
+			if len(emailSender.sent) != 1 || len(verificationRepo.tokens) != 1 {
+				t.Fatalf("sent %d emails and issued %d tokens, want one of each", len(emailSender.sent), len(verificationRepo.tokens))
+			}
+
+			msg := <-emailSender.sent
+			const prefix = "https://example.com/reset?token="
+			i := strings.Index(msg.Body, prefix)
+			if msg.To != tt.email || i < 0 {
+				t.Fatalf("email = %+v, want a reset link for %s", msg, tt.email)
+			}
+
+			// Only the hash of the emailed token is stored
+			tokenStr := strings.Fields(msg.Body[i+len(prefix):])[0]
+			token := verificationRepo.tokens[0]
+			if token.TokenHash != "hash:"+tokenStr || token.Purpose != entity.TokenPurposePasswordReset {
+				t.Errorf("stored token = %+v, want the password reset hash of %q", token, tokenStr)
+			}
+		})
+	}
+}
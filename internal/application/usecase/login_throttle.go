@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+)
+
+// LockoutPolicy configures failed login throttling
+type LockoutPolicy struct {
+	// MaxAttempts is the number of consecutive failures after which an account is locked
+	MaxAttempts int
+	// LockDuration is how long an account (or IP address) stays locked
+	LockDuration time.Duration
+	// BaseDelay is the back-off after the first failure; it doubles with every further failure
+	BaseDelay time.Duration
+	// MaxDelay caps the progressive back-off
+	MaxDelay time.Duration
+	// IPMaxAttempts is the number of failures from one IP address (across accounts) before it is locked
+	IPMaxAttempts int
+	// ResetAfter forgets failures older than this
+	ResetAfter time.Duration
+}
+
+// LoginThrottle tracks failed logins per account and per IP address and enforces
+// exponential back-off and temporary lockouts
+type LoginThrottle struct {
+	attemptRepo repository.LoginAttemptRepository
+	emailSender service.EmailSender
+	policy      LockoutPolicy
+}
+
+// NewLoginThrottle creates a new login throttle
+func NewLoginThrottle(
+	attemptRepo repository.LoginAttemptRepository,
+	emailSender service.EmailSender,
+	policy LockoutPolicy,
+) *LoginThrottle {
+	return &LoginThrottle{
+		attemptRepo: attemptRepo,
+		emailSender: emailSender,
+		policy:      policy,
+	}
+}
+
+// Check returns an *errors.AccountLockedError if the account or the IP address is currently blocked
+func (t *LoginThrottle) Check(ctx context.Context, email, ipAddress string) error {
+	var retryAfter time.Duration
+
+	for _, key := range t.keys(email, ipAddress) {
+		attempt, err := t.attemptRepo.FindByKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		if wait := attempt.RetryAfter(); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &apperrors.AccountLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure records a failed login and applies back-off or a lockout.
+// user is the account owner, or nil if no account exists for the email.
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email, ipAddress string, user *entity.User) {
+	accountKey := accountThrottleKey(email)
+	attempt, err := t.attemptRepo.RecordFailure(ctx, accountKey, t.policy.ResetAfter)
+	if err != nil {
+		log.Printf("Failed to record login failure for %s: %v", accountKey, err)
+	} else if delay := t.accountDelay(attempt.Failures); delay > 0 {
+		if err := t.attemptRepo.Lock(ctx, accountKey, time.Now().Add(delay)); err != nil {
+			log.Printf("Failed to lock %s: %v", accountKey, err)
+		}
+
+		// Notify the owner once, when the account becomes locked
+		if attempt.Failures == t.policy.MaxAttempts && user != nil {
+			t.notifyLocked(ctx, user)
+		}
+	}
+
+	if ipAddress == "" || t.policy.IPMaxAttempts <= 0 {
+		return
+	}
+
+	ipKey := ipThrottleKey(ipAddress)
+	attempt, err = t.attemptRepo.RecordFailure(ctx, ipKey, t.policy.ResetAfter)
+	if err != nil {
+		log.Printf("Failed to record login failure for %s: %v", ipKey, err)
+		return
+	}
+	if attempt.Failures >= t.policy.IPMaxAttempts {
+		if err := t.attemptRepo.Lock(ctx, ipKey, time.Now().Add(t.policy.LockDuration)); err != nil {
+			log.Printf("Failed to lock %s: %v", ipKey, err)
+		}
+	}
+}
+
+// Reset clears the failures of an account, after a successful login or an admin unlock
+func (t *LoginThrottle) Reset(ctx context.Context, email string) error {
+	return t.attemptRepo.Reset(ctx, accountThrottleKey(email))
+}
+
+// accountDelay returns how long an account is blocked after the given number of consecutive failures
+func (t *LoginThrottle) accountDelay(failures int) time.Duration {
+	if t.policy.MaxAttempts > 0 && failures >= t.policy.MaxAttempts {
+		return t.policy.LockDuration
+	}
+	if t.policy.BaseDelay <= 0 || failures <= 0 {
+		return 0
+	}
+
+	// A zero MaxDelay leaves the back-off uncapped; doubling stops before it overflows
+	delay := t.policy.BaseDelay
+	for i := 1; i < failures; i++ {
+		if (t.policy.MaxDelay > 0 && delay >= t.policy.MaxDelay) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if t.policy.MaxDelay > 0 && delay > t.policy.MaxDelay {
+		delay = t.policy.MaxDelay
+	}
+	return delay
+}
+
+// notifyLocked tells the account owner about the lockout
+func (t *LoginThrottle) notifyLocked(ctx context.Context, user *entity.User) {
+	err := t.emailSender.Send(ctx, service.EmailMessage{
+		To:      user.Email,
+		Subject: "Your account has been temporarily locked",
+		Body: fmt.Sprintf(
+			"We blocked sign-ins to your account for %s after %d failed login attempts.\n\n"+
+				"If this was you, wait and try again or reset your password. "+
+				"If it was not you, someone may be trying to guess your password; consider changing it.",
+			t.policy.LockDuration, t.policy.MaxAttempts,
+		),
+	})
+	if err != nil {
+		log.Printf("Failed to send lockout notification to user %s: %v", user.ID, err)
+	}
+}
+
+// keys returns the throttling keys of a login attempt
+func (t *LoginThrottle) keys(email, ipAddress string) []string {
+	keys := []string{accountThrottleKey(email)}
+	if ipAddress != "" && t.policy.IPMaxAttempts > 0 {
+		keys = append(keys, ipThrottleKey(ipAddress))
+	}
+	return keys
+}
+
+// accountThrottleKey keys accounts by normalized email, so unknown emails are throttled
+// exactly like existing ones and lockouts do not reveal which accounts exist
+func accountThrottleKey(email string) string {
+	return "account:" + normalizeEmail(email)
+}
+
+func ipThrottleKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
+
+// normalizeEmail normalizes an email the way valueobject.NewEmail does
+func normalizeEmail(email string) string {
+	return strings.TrimSpace(strings.ToLower(email))
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+)
+
+// fakeLoginAttemptRepo is an in-memory LoginAttemptRepository
+type fakeLoginAttemptRepo struct {
+	mu       sync.Mutex
+	attempts map[string]*entity.LoginAttempt
+}
+
+func newFakeLoginAttemptRepo() *fakeLoginAttemptRepo {
+	return &fakeLoginAttemptRepo{attempts: make(map[string]*entity.LoginAttempt)}
+}
+
+func (r *fakeLoginAttemptRepo) attempt(key string) *entity.LoginAttempt {
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt = &entity.LoginAttempt{Key: key}
+		r.attempts[key] = attempt
+	}
+	return attempt
+}
+
+func (r *fakeLoginAttemptRepo) FindByKey(ctx context.Context, key string) (*entity.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt := *r.attempt(key)
+	return &attempt, nil
+}
+
+func (r *fakeLoginAttemptRepo) RecordFailure(ctx context.Context, key string, resetAfter time.Duration) (*entity.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt := r.attempt(key)
+	now := time.Now()
+	attempt.Failures++
+	attempt.LastFailureAt = &now
+	copied := *attempt
+	return &copied, nil
+}
+
+func (r *fakeLoginAttemptRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempt(key).LockedUntil = &until
+	return nil
+}
+
+func (r *fakeLoginAttemptRepo) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, key)
+	return nil
+}
+
+func TestLoginThrottle_AccountDelay(t *testing.T) {
+	policy := LockoutPolicy{
+		MaxAttempts:  5,
+		LockDuration: 15 * time.Minute,
+		BaseDelay:    time.Second,
+		MaxDelay:     5 * time.Second,
+	}
+
+	tests := []struct {
+		name     string
+		policy   LockoutPolicy
+		failures int
+		want     time.Duration
+	}{
+		{name: "no failures", policy: policy, failures: 0, want: 0},
+		{name: "first failure", policy: policy, failures: 1, want: time.Second},
+		{name: "doubles", policy: policy, failures: 2, want: 2 * time.Second},
+		{name: "doubles again", policy: policy, failures: 3, want: 4 * time.Second},
+		{name: "capped", policy: policy, failures: 4, want: 5 * time.Second},
+		{name: "locked at max attempts", policy: policy, failures: 5, want: 15 * time.Minute},
+		{name: "locked beyond max attempts", policy: policy, failures: 9, want: 15 * time.Minute},
+		{name: "back-off disabled", policy: LockoutPolicy{MaxAttempts: 3, LockDuration: time.Minute}, failures: 2, want: 0},
+		{name: "uncapped back-off", policy: LockoutPolicy{BaseDelay: time.Second}, failures: 4, want: 8 * time.Second},
+		{name: "uncapped back-off does not overflow", policy: LockoutPolicy{BaseDelay: time.Second}, failures: 1000, want: time.Second << 33},
+		{name: "lockout disabled", policy: LockoutPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, failures: 100, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle := NewLoginThrottle(newFakeLoginAttemptRepo(), newFakeEmailSender(), tt.policy)
+			if got := throttle.accountDelay(tt.failures); got != tt.want {
+				t.Errorf("accountDelay(%d) = %v, want %v", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginThrottle_Lockout(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeLoginAttemptRepo()
+	emailSender := newFakeEmailSender()
+	throttle := NewLoginThrottle(repo, emailSender, LockoutPolicy{
+		MaxAttempts:   3,
+		LockDuration:  time.Hour,
+		IPMaxAttempts: 10,
+	})
+	user := entity.NewUser("user@example.com", "hash")
+
+	for i := 0; i < 3; i++ {
+		if err := throttle.Check(ctx, " User@Example.com ", "203.0.113.1"); err != nil {
+			t.Fatalf("Check() before failure %d error = %v", i+1, err)
+		}
+		throttle.RecordFailure(ctx, "user@example.com", "203.0.113.1", user)
+	}
+
+	var locked *apperrors.AccountLockedError
+	err := throttle.Check(ctx, "USER@example.com", "198.51.100.7")
+	if !errors.As(err, &locked) || !errors.Is(err, apperrors.ErrAccountLocked) {
+		t.Fatalf("Check() after lockout error = %v, want an AccountLockedError", err)
+	}
+	if locked.RetryAfter <= 59*time.Minute || locked.RetryAfter > time.Hour {
+		t.Errorf("RetryAfter = %v, want about an hour", locked.RetryAfter)
+	}
+
+	select {
+	case msg := <-emailSender.sent:
+		if msg.To != user.Email {
+			t.Errorf("lockout notice sent to %s, want %s", msg.To, user.Email)
+		}
+	default:
+		t.Error("no lockout notice was sent")
+	}
+
+	// The owner is notified once, when the account becomes locked
+	throttle.RecordFailure(ctx, "user@example.com", "203.0.113.1", user)
+	if len(emailSender.sent) != 0 {
+		t.Error("lockout notice was sent again")
+	}
+
+	if err := throttle.Reset(ctx, "user@example.com"); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if err := throttle.Check(ctx, "user@example.com", "198.51.100.7"); err != nil {
+		t.Errorf("Check() after reset error = %v", err)
+	}
+}
+
+func TestLoginThrottle_IPLockout(t *testing.T) {
+	ctx := context.Background()
+	throttle := NewLoginThrottle(newFakeLoginAttemptRepo(), newFakeEmailSender(), LockoutPolicy{
+		MaxAttempts:   100,
+		LockDuration:  time.Hour,
+		IPMaxAttempts: 3,
+	})
+
+	// Spraying passwords across accounts from one address locks the address
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		throttle.RecordFailure(ctx, email, "203.0.113.1", nil)
+	}
+
+	if err := throttle.Check(ctx, "d@example.com", "203.0.113.1"); !errors.Is(err, apperrors.ErrAccountLocked) {
+		t.Errorf("Check() from the locked address error = %v, want ErrAccountLocked", err)
+	}
+	if err := throttle.Check(ctx, "d@example.com", "198.51.100.7"); err != nil {
+		t.Errorf("Check() from another address error = %v", err)
+	}
+}
@@ -3,7 +3,7 @@ package usecase
 import (
 	"context"
 	"log"
-	"time"
+	"sort"
 
 	"auth-go/internal/application/dto"
 	"auth-go/internal/domain/entity"
@@ -18,56 +18,125 @@ import (
 type LoginUseCase struct {
 	userRepo         repository.UserRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.SessionRepository
+	txManager        repository.TxManager
 	passwordHasher   service.PasswordHasher
 	tokenService     service.TokenService
+	// tokenPermissions, if set, embeds the user's permissions in access tokens
+	tokenPermissions service.PermissionResolver
+	opaqueTokens     service.OpaqueTokenService
+	sessionLifetimes entity.SessionLifetimePolicy
+	sessionLimits    entity.SessionLimitPolicy
+	loginThrottle    *LoginThrottle
+	deviceMonitor    *DeviceMonitor
+	auditLogger      service.AuditLogger
+	// dummyHash is compared against when the user does not exist, so unknown
+	// emails take as long as wrong passwords
+	dummyHash string
 }
 
 // NewLoginUseCase creates a new login use case
 func NewLoginUseCase(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.SessionRepository,
+	txManager repository.TxManager,
 	passwordHasher service.PasswordHasher,
 	tokenService service.TokenService,
+	tokenPermissions service.PermissionResolver,
+	opaqueTokens service.OpaqueTokenService,
+	sessionLifetimes entity.SessionLifetimePolicy,
+	sessionLimits entity.SessionLimitPolicy,
+	loginThrottle *LoginThrottle,
+	deviceMonitor *DeviceMonitor,
+	auditLogger service.AuditLogger,
 ) *LoginUseCase {
+	dummyHash, err := passwordHasher.Hash(uuid.NewString())
+	if err != nil {
+		log.Printf("Failed to generate dummy password hash: %v", err)
+	}
+
 	return &LoginUseCase{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		txManager:        txManager,
 		passwordHasher:   passwordHasher,
 		tokenService:     tokenService,
+		tokenPermissions: tokenPermissions,
+		opaqueTokens:     opaqueTokens,
+		sessionLifetimes: sessionLifetimes,
+		sessionLimits:    sessionLimits,
+		loginThrottle:    loginThrottle,
+		deviceMonitor:    deviceMonitor,
+		auditLogger:      auditLogger,
+		dummyHash:        dummyHash,
 	}
 }
 
 // Execute executes the login use case
 func (uc *LoginUseCase) Execute(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
+	email := normalizeEmail(req.Email)
+	actor := dto.Actor{IPAddress: req.IPAddress, UserAgent: req.UserAgent}
+
+	// Refuse early while the account or the client IP is backing off
+	if err := uc.loginThrottle.Check(ctx, email, req.IPAddress); err != nil {
+		uc.auditFailure(ctx, actor, uuid.Nil, email, "locked")
+		return nil, err
+	}
+
 	// Find user by email
-	user, err := uc.userRepo.FindByEmail(ctx, req.Email)
+	user, err := uc.userRepo.FindByEmail(ctx, email)
 	if err != nil {
+		// Spend the same hashing work as for an existing user before failing
+		_ = uc.passwordHasher.Compare(req.Password, uc.dummyHash)
+		uc.loginThrottle.RecordFailure(ctx, email, req.IPAddress, nil)
+		uc.auditFailure(ctx, actor, uuid.Nil, email, "unknown_user")
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	// Verify password; invited users without a password cost the same as wrong passwords
+	passwordHash := user.PasswordHash
+	if !user.HasPassword() {
+		passwordHash = uc.dummyHash
+	}
+	if err := uc.passwordHasher.Compare(req.Password, passwordHash); err != nil || !user.HasPassword() {
+		uc.loginThrottle.RecordFailure(ctx, email, req.IPAddress, user)
+		uc.auditFailure(ctx, actor, user.ID, email, "invalid_password")
 		return nil, apperrors.ErrInvalidCredentials
 	}
 
-	// Check if user is active
+	// Only reveal the account state to someone who knows the password
 	if !user.IsActive {
+		uc.auditFailure(ctx, actor, user.ID, email, "inactive")
 		return nil, apperrors.ErrUserInactive
 	}
 
-	// Verify password
-	if err := uc.passwordHasher.Compare(req.Password, user.PasswordHash); err != nil {
-		return nil, apperrors.ErrInvalidCredentials
+	// Successful login clears the account's failure count
+	if err := uc.loginThrottle.Reset(ctx, email); err != nil {
+		log.Printf("Failed to reset login failures for user %s: %v", user.ID, err)
 	}
 
-	// Update last login
-	user.UpdateLastLogin()
-	if err := uc.userRepo.Update(ctx, user); err != nil {
-		// Log error but don't fail the login
-		log.Printf("Failed to update last login for user %s: %v", user.ID, err)
+	// Transparently upgrade hashes made with an outdated algorithm or parameters
+	if uc.passwordHasher.NeedsRehash(user.PasswordHash) {
+		if passwordHash, err := uc.passwordHasher.Hash(req.Password); err != nil {
+			log.Printf("Failed to rehash password for user %s: %v", user.ID, err)
+		} else {
+			user.ChangePassword(passwordHash)
+		}
 	}
 
+	// Each login starts a new session, whose ID is the token family
+	session := entity.NewSession(user.ID, req.ClientID, req.DeviceName, req.UserAgent, req.IPAddress)
+	session.Authenticate(entity.ACRBasic, []string{entity.AMRPassword})
+	tokenFamily := session.ID
+
 	// Generate access token
-	accessToken, err := uc.tokenService.GenerateAccessToken(service.TokenClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Roles:  user.Roles,
-	})
+	claims, err := sessionTokenClaims(ctx, uc.tokenPermissions, user, session)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := uc.tokenService.GenerateAccessToken(claims)
 	if err != nil {
 		return nil, err
 	}
@@ -78,15 +147,52 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req dto.LoginRequest) (*dto
 		return nil, err
 	}
 
-	// Create refresh token entity with new token family
-	tokenFamily := uuid.New()
-	expiresAt := time.Now().Add(uc.tokenService.GetRefreshTokenExpiry())
-	refreshToken := entity.NewRefreshToken(user.ID, refreshTokenStr, expiresAt, tokenFamily)
+	// Create refresh token entity with new token family; only its hash is stored
+	expiresAt := session.RefreshTokenExpiry(uc.sessionLifetimes.For(session.ClientID), uc.tokenService.GetRefreshTokenExpiry())
+	refreshToken := entity.NewRefreshToken(user.ID, uc.opaqueTokens.Hash(refreshTokenStr), expiresAt, tokenFamily)
 
-	// Save refresh token
-	if err := uc.refreshTokenRepo.Create(ctx, refreshToken); err != nil {
+	// Save session and refresh token, within the user's session limit
+	var evicted []*entity.Session
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if evicted, err = uc.enforceSessionLimit(ctx, user); err != nil {
+			return err
+		}
+		if err := uc.sessionRepo.Create(ctx, session); err != nil {
+			return err
+		}
+		return uc.refreshTokenRepo.Create(ctx, refreshToken)
+	})
+	actor.UserID = user.ID
+	if err == apperrors.ErrSessionLimit {
+		recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventSessionLimitReached, user.ID, map[string]interface{}{
+			"limit": uc.sessionLimits.LimitFor(user),
+		})
 		return nil, err
 	}
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range evicted {
+		recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventSessionEvicted, user.ID, map[string]interface{}{
+			"session_id": s.ID.String(),
+			"limit":      uc.sessionLimits.LimitFor(user),
+		})
+	}
+
+	// Update last login (also persists an upgraded password hash)
+	user.UpdateLastLogin()
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		// Log error but don't fail the login
+		log.Printf("Failed to update last login for user %s: %v", user.ID, err)
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventLoginSucceeded, user.ID, map[string]interface{}{
+		"session_id": tokenFamily.String(),
+	})
+
+	// Report sign ins from new devices or impossible locations
+	uc.deviceMonitor.Observe(ctx, user, actor, req.DeviceID)
 
 	return &dto.AuthResponse{
 		AccessToken:  accessToken,
@@ -95,3 +201,74 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req dto.LoginRequest) (*dto
 		ExpiresIn:    int64(uc.tokenService.GetAccessTokenExpiry().Seconds()),
 	}, nil
 }
+
+// auditFailure records a failed login; subjectID is uuid.Nil when no account matches the email
+func (uc *LoginUseCase) auditFailure(ctx context.Context, actor dto.Actor, subjectID uuid.UUID, email, reason string) {
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventLoginFailed, subjectID, map[string]interface{}{
+		"email":  email,
+		"reason": reason,
+	})
+}
+
+// sessionTokenClaims returns the access token claims of a session of the user, carrying
+// how and when the user authenticated for it. With a permission resolver the user's
+// permissions are embedded; otherwise they are resolved server-side on each request.
+func sessionTokenClaims(ctx context.Context, permissions service.PermissionResolver, user *entity.User, session *entity.Session) (service.TokenClaims, error) {
+	claims := service.TokenClaims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Roles:     user.Roles,
+		SessionID: session.ID,
+		AuthTime:  session.AuthTime,
+		ACR:       session.ACR,
+		AMR:       session.AMR,
+	}
+
+	if permissions != nil {
+		granted, err := permissions.Resolve(ctx, user.Roles)
+		if err != nil {
+			return service.TokenClaims{}, err
+		}
+		claims.Permissions = granted
+	}
+
+	return claims, nil
+}
+
+// enforceSessionLimit makes room for a new session of the user, either by evicting the oldest
+// active sessions or by failing with ErrSessionLimit. It must run in the transaction creating the session.
+func (uc *LoginUseCase) enforceSessionLimit(ctx context.Context, user *entity.User) ([]*entity.Session, error) {
+	limit := uc.sessionLimits.LimitFor(user)
+	if limit == 0 {
+		return nil, nil
+	}
+
+	// Concurrent logins of the same user must not both see room for one more session
+	if err := uc.sessionRepo.LockUserSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	active, err := uc.sessionRepo.FindActiveByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) < limit {
+		return nil, nil
+	}
+
+	if !uc.sessionLimits.EvictOldest {
+		return nil, apperrors.ErrSessionLimit
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].CreatedAt.Before(active[j].CreatedAt)
+	})
+	evicted := active[:len(active)-limit+1]
+	for _, session := range evicted {
+		if err := uc.refreshTokenRepo.RevokeByTokenFamily(ctx, session.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return evicted, nil
+}
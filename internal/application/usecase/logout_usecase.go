@@ -3,7 +3,10 @@ package usecase
 import (
 	"context"
 
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
 	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
 
 	"github.com/google/uuid"
 )
@@ -11,16 +14,37 @@ import (
 // LogoutUseCase handles user logout by revoking refresh tokens
 type LogoutUseCase struct {
 	refreshTokenRepo repository.RefreshTokenRepository
+	auditLogger      service.AuditLogger
 }
 
 // NewLogoutUseCase creates a new logout use case
-func NewLogoutUseCase(refreshTokenRepo repository.RefreshTokenRepository) *LogoutUseCase {
+func NewLogoutUseCase(refreshTokenRepo repository.RefreshTokenRepository, auditLogger service.AuditLogger) *LogoutUseCase {
 	return &LogoutUseCase{
 		refreshTokenRepo: refreshTokenRepo,
+		auditLogger:      auditLogger,
 	}
 }
 
-// Execute executes the logout use case (revokes all user tokens)
-func (uc *LogoutUseCase) Execute(ctx context.Context, userID uuid.UUID) error {
-	return uc.refreshTokenRepo.RevokeByUserID(ctx, userID)
+// Execute executes the logout use case. It ends the current session of the acting user,
+// or every session if all is set or the access token names no session.
+func (uc *LogoutUseCase) Execute(ctx context.Context, actor dto.Actor, sessionID uuid.UUID, all bool) error {
+	all = all || sessionID == uuid.Nil
+
+	var err error
+	if all {
+		err = uc.refreshTokenRepo.RevokeByUserID(ctx, actor.UserID)
+	} else {
+		err = uc.refreshTokenRepo.RevokeByTokenFamily(ctx, sessionID)
+	}
+	if err != nil {
+		return err
+	}
+
+	details := map[string]interface{}{"all": all}
+	if !all {
+		details["session_id"] = sessionID.String()
+	}
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventLogout, actor.UserID, details)
+
+	return nil
 }
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// newSignedInDevices stores a refresh token for each of the sessions, which belong to their own users
+func newSignedInDevices(t *testing.T, sessions ...*entity.Session) *fakeRefreshTokenRepo {
+	t.Helper()
+
+	tokens := newFakeRefreshTokenRepo()
+	for _, session := range sessions {
+		token := entity.NewRefreshToken(session.UserID, "hash:"+uuid.NewString(), time.Now().Add(time.Hour), session.ID)
+		if err := tokens.Create(context.Background(), token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tokens
+}
+
+// revokedSessions returns whether the tokens of each session are revoked
+func revokedSessions(tokens *fakeRefreshTokenRepo, sessions ...*entity.Session) []bool {
+	revoked := make([]bool, len(sessions))
+	for i, session := range sessions {
+		issued, _ := tokens.FindByUserID(context.Background(), session.UserID)
+		for _, token := range issued {
+			if token.TokenFamily == session.ID {
+				revoked[i] = token.IsRevoked
+			}
+		}
+	}
+	return revoked
+}
+
+func TestLogoutUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     int
+		all         bool
+		wantRevoked []bool
+	}{
+		{name: "current session only", current: 0, wantRevoked: []bool{true, false, false}},
+		{name: "every session", current: 0, all: true, wantRevoked: []bool{true, true, false}},
+		{name: "access token without a session", current: -1, wantRevoked: []bool{true, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("ada@example.com", "hashed:pw")
+			other := entity.NewUser("other@example.com", "hashed:pw")
+			phone := entity.NewSession(user.ID, "web", "Phone", "test", "203.0.113.7")
+			laptop := entity.NewSession(user.ID, "web", "Laptop", "test", "203.0.113.8")
+			elsewhere := entity.NewSession(other.ID, "web", "", "test", "203.0.113.9")
+			tokens := newSignedInDevices(t, phone, laptop, elsewhere)
+			audit := &fakeAuditLogger{}
+
+			sessionID := uuid.Nil
+			if tt.current >= 0 {
+				sessionID = []*entity.Session{phone, laptop}[tt.current].ID
+			}
+
+			err := NewLogoutUseCase(tokens, audit).Execute(context.Background(), dto.Actor{UserID: user.ID}, sessionID, tt.all)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			got := revokedSessions(tokens, phone, laptop, elsewhere)
+			for i := range got {
+				if got[i] != tt.wantRevoked[i] {
+					t.Errorf("revoked = %v, want %v", got, tt.wantRevoked)
+					break
+				}
+			}
+			if types := audit.types(); len(types) != 1 || types[0] != entity.AuditEventLogout {
+				t.Errorf("audit events = %v, want %v", types, entity.AuditEventLogout)
+			}
+		})
+	}
+}
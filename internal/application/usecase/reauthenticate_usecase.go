@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// ReauthenticateUseCase confirms the password of a signed-in user and issues a short-lived
+// elevated access token for high-risk operations (step-up authentication)
+type ReauthenticateUseCase struct {
+	userRepo       repository.UserRepository
+	sessionRepo    repository.SessionRepository
+	passwordHasher service.PasswordHasher
+	tokenService   service.TokenService
+	// tokenPermissions, if set, embeds the user's permissions in access tokens
+	tokenPermissions service.PermissionResolver
+	loginThrottle    *LoginThrottle
+	auditLogger      service.AuditLogger
+	// tokenExpiry is the lifetime of the elevated access token
+	tokenExpiry time.Duration
+}
+
+// NewReauthenticateUseCase creates a new re-authentication use case
+func NewReauthenticateUseCase(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	passwordHasher service.PasswordHasher,
+	tokenService service.TokenService,
+	tokenPermissions service.PermissionResolver,
+	loginThrottle *LoginThrottle,
+	auditLogger service.AuditLogger,
+	tokenExpiry time.Duration,
+) *ReauthenticateUseCase {
+	return &ReauthenticateUseCase{
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		passwordHasher:   passwordHasher,
+		tokenService:     tokenService,
+		tokenPermissions: tokenPermissions,
+		loginThrottle:    loginThrottle,
+		auditLogger:      auditLogger,
+		tokenExpiry:      tokenExpiry,
+	}
+}
+
+// Execute verifies the password of the actor, signed in with session sessionID, and returns
+// an elevated access token for the same session. Wrong passwords count towards the login lockout.
+func (uc *ReauthenticateUseCase) Execute(ctx context.Context, actor dto.Actor, sessionID uuid.UUID, req dto.ReauthenticateRequest) (*dto.StepUpResponse, error) {
+	user, err := uc.userRepo.FindByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, apperrors.ErrUserInactive
+	}
+
+	// The access token may outlive a signed out session; only active sessions can be elevated
+	session, err := uc.findActiveSession(ctx, user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.loginThrottle.Check(ctx, user.Email, actor.IPAddress); err != nil {
+		return nil, err
+	}
+
+	if err := uc.passwordHasher.Compare(req.Password, user.PasswordHash); err != nil || !user.HasPassword() {
+		uc.loginThrottle.RecordFailure(ctx, user.Email, actor.IPAddress, user)
+		recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventReauthFailed, user.ID, map[string]interface{}{
+			"session_id": session.ID.String(),
+		})
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	if err := uc.loginThrottle.Reset(ctx, user.Email); err != nil {
+		log.Printf("Failed to reset login failures for user %s: %v", user.ID, err)
+	}
+
+	claims, err := sessionTokenClaims(ctx, uc.tokenPermissions, user, session)
+	if err != nil {
+		return nil, err
+	}
+	claims.AuthTime = time.Now()
+	claims.ACR = entity.ACRElevated
+	claims.AMR = []string{entity.AMRPassword}
+
+	accessToken, err := uc.tokenService.GenerateAccessTokenWithExpiry(claims, uc.tokenExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventReauthenticated, user.ID, map[string]interface{}{
+		"session_id": session.ID.String(),
+		"acr":        claims.ACR,
+	})
+
+	return &dto.StepUpResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(uc.tokenExpiry.Seconds()),
+	}, nil
+}
+
+// findActiveSession returns the session of the user with the given ID if it still holds a valid refresh token
+func (uc *ReauthenticateUseCase) findActiveSession(ctx context.Context, userID, sessionID uuid.UUID) (*entity.Session, error) {
+	sessions, err := uc.sessionRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			return session, nil
+		}
+	}
+	return nil, apperrors.ErrSessionNotFound
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// recordingTokenService remembers the claims of the last access token it issued
+type recordingTokenService struct {
+	fakeTokenService
+	claims service.TokenClaims
+	expiry time.Duration
+}
+
+func (s *recordingTokenService) GenerateAccessTokenWithExpiry(claims service.TokenClaims, expiry time.Duration) (string, error) {
+	s.claims, s.expiry = claims, expiry
+	return s.fakeTokenService.GenerateAccessTokenWithExpiry(claims, expiry)
+}
+
+func TestReauthenticateUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		password     string
+		inactive     bool
+		otherSession bool
+		wantErr      error
+		wantFailures int
+		wantAudit    entity.AuditEventType
+	}{
+		{name: "correct password", password: "secret", wantAudit: entity.AuditEventReauthenticated},
+		{name: "wrong password", password: "guess", wantErr: apperrors.ErrInvalidCredentials, wantFailures: 1, wantAudit: entity.AuditEventReauthFailed},
+		{name: "inactive user", password: "secret", inactive: true, wantErr: apperrors.ErrUserInactive},
+		{name: "session not active", password: "secret", otherSession: true, wantErr: apperrors.ErrSessionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("ada@example.com", "hashed:secret")
+			if tt.inactive {
+				user.Deactivate()
+			}
+			session := entity.NewSession(user.ID, "web", "", "test", "203.0.113.7")
+			sessionID := session.ID
+			if tt.otherSession {
+				sessionID = uuid.New()
+			}
+			throttle, attempts := newTestThrottle()
+			tokens := &recordingTokenService{}
+			audit := &fakeAuditLogger{}
+			uc := NewReauthenticateUseCase(newFakeUserRepo(user), newFakeSessionRepo(session), &fakePasswordHasher{}, tokens, nil, throttle, audit, 5*time.Minute)
+
+			before := time.Now()
+			response, err := uc.Execute(context.Background(), dto.Actor{UserID: user.ID, IPAddress: "203.0.113.7"}, sessionID,
+				dto.ReauthenticateRequest{Password: tt.password})
+			if err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if failures := attempts.attempt(accountThrottleKey(user.Email)).Failures; failures != tt.wantFailures {
+				t.Errorf("recorded failures = %d, want %d", failures, tt.wantFailures)
+			}
+			if types := audit.types(); tt.wantAudit != "" && (len(types) != 1 || types[0] != tt.wantAudit) {
+				t.Errorf("audit events = %v, want %v", types, tt.wantAudit)
+			}
+			if err != nil {
+				return
+			}
+
+			if response.ExpiresIn != 300 || tokens.expiry != 5*time.Minute {
+				t.Errorf("token lifetime = %v (expires_in %d), want 5m", tokens.expiry, response.ExpiresIn)
+			}
+			claims := tokens.claims
+			if claims.ACR != entity.ACRElevated || claims.AuthTime.Before(before) || claims.SessionID != session.ID {
+				t.Errorf("claims = %+v, want an elevated token for session %s authenticated now", claims, session.ID)
+			}
+		})
+	}
+}
+
+func TestReauthenticateUseCase_LockedAfterWrongPasswords(t *testing.T) {
+	user := entity.NewUser("ada@example.com", "hashed:secret")
+	session := entity.NewSession(user.ID, "web", "", "test", "203.0.113.7")
+	throttle, _ := newTestThrottle()
+	uc := NewReauthenticateUseCase(newFakeUserRepo(user), newFakeSessionRepo(session), &fakePasswordHasher{}, fakeTokenService{}, nil, throttle, &fakeAuditLogger{}, 5*time.Minute)
+	actor := dto.Actor{UserID: user.ID, IPAddress: "203.0.113.7"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := uc.Execute(context.Background(), actor, session.ID, dto.ReauthenticateRequest{Password: "guess"}); err != apperrors.ErrInvalidCredentials {
+			t.Fatalf("guess %d: Execute() error = %v, want %v", i+1, err, apperrors.ErrInvalidCredentials)
+		}
+	}
+
+	// Even the right password is refused while the account is locked
+	_, err := uc.Execute(context.Background(), actor, session.ID, dto.ReauthenticateRequest{Password: "secret"})
+	var lockedErr *apperrors.AccountLockedError
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("Execute() error = %v, want an AccountLockedError", err)
+	}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// RedeliverWebhookUseCase queues a delivery for another attempt, typically one in the dead letter state (admin only)
+type RedeliverWebhookUseCase struct {
+	deliveryRepo repository.WebhookDeliveryRepository
+	auditLogger  service.AuditLogger
+}
+
+// NewRedeliverWebhookUseCase creates a new redeliver webhook use case
+func NewRedeliverWebhookUseCase(deliveryRepo repository.WebhookDeliveryRepository, auditLogger service.AuditLogger) *RedeliverWebhookUseCase {
+	return &RedeliverWebhookUseCase{
+		deliveryRepo: deliveryRepo,
+		auditLogger:  auditLogger,
+	}
+}
+
+// Execute executes the redeliver webhook use case
+func (uc *RedeliverWebhookUseCase) Execute(ctx context.Context, actor dto.Actor, webhookID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	delivery, err := uc.deliveryRepo.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.EndpointID != webhookID {
+		return nil, apperrors.ErrWebhookDeliveryNotFound
+	}
+
+	// Pending deliveries are already queued
+	if delivery.Status == entity.WebhookDeliveryPending {
+		return delivery, nil
+	}
+
+	delivery.Requeue()
+	if err := uc.deliveryRepo.Update(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventWebhookRedelivered, uuid.Nil, map[string]interface{}{
+		"webhook_id":  webhookID.String(),
+		"delivery_id": delivery.ID.String(),
+	})
+
+	return delivery, nil
+}
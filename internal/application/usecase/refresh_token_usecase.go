@@ -15,48 +15,195 @@ import (
 type RefreshTokenUseCase struct {
 	userRepo         repository.UserRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.SessionRepository
+	txManager        repository.TxManager
 	tokenService     service.TokenService
+	tokenPermissions service.PermissionResolver
+	opaqueTokens     service.OpaqueTokenService
+	sessionLifetimes entity.SessionLifetimePolicy
+	auditLogger      service.AuditLogger
+	reuseGrace       time.Duration
 }
 
 // NewRefreshTokenUseCase creates a new refresh token use case
 func NewRefreshTokenUseCase(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.SessionRepository,
+	txManager repository.TxManager,
 	tokenService service.TokenService,
+	tokenPermissions service.PermissionResolver,
+	opaqueTokens service.OpaqueTokenService,
+	sessionLifetimes entity.SessionLifetimePolicy,
+	auditLogger service.AuditLogger,
+	reuseGrace time.Duration,
 ) *RefreshTokenUseCase {
 	return &RefreshTokenUseCase{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		txManager:        txManager,
 		tokenService:     tokenService,
+		tokenPermissions: tokenPermissions,
+		opaqueTokens:     opaqueTokens,
+		sessionLifetimes: sessionLifetimes,
+		auditLogger:      auditLogger,
+		reuseGrace:       reuseGrace,
 	}
 }
 
-// Execute executes the refresh token use case with token rotation
+// Execute executes the refresh token use case with token rotation.
+// Revoking the presented token and saving its successor happen in one transaction.
 func (uc *RefreshTokenUseCase) Execute(ctx context.Context, req dto.RefreshTokenRequest) (*dto.AuthResponse, error) {
-	// Find refresh token
-	refreshToken, err := uc.refreshTokenRepo.FindByToken(ctx, req.RefreshToken)
+	var (
+		response *dto.AuthResponse
+		reused   *entity.RefreshToken
+		expired  bool
+	)
+
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Find refresh token by its hash
+		tokenHash := uc.opaqueTokens.Hash(req.RefreshToken)
+		refreshToken, err := uc.refreshTokenRepo.FindByTokenHash(ctx, tokenHash)
+		if err != nil {
+			return apperrors.ErrInvalidToken
+		}
+
+		if !refreshToken.IsRevoked {
+			// Check if token is expired
+			if refreshToken.IsExpired() {
+				return apperrors.ErrExpiredToken
+			}
+
+			// Revoke current token (token rotation); only one concurrent request wins
+			refreshToken.Revoke()
+			err := uc.refreshTokenRepo.Rotate(ctx, refreshToken)
+			if err == nil {
+				response, err = uc.issueSuccessor(ctx, refreshToken, req)
+				if err == apperrors.ErrSessionExpired {
+					// End the whole session, keeping the revocation
+					expired = true
+					return uc.refreshTokenRepo.RevokeByTokenFamily(ctx, refreshToken.TokenFamily)
+				}
+				return err
+			}
+			if err != apperrors.ErrTokenRevoked {
+				return err
+			}
+
+			// A concurrent request rotated it first; treat this one as its retry
+			refreshToken, err = uc.refreshTokenRepo.FindByTokenHash(ctx, tokenHash)
+			if err != nil {
+				return err
+			}
+		}
+
+		if refreshToken.RevokedWithin(uc.reuseGrace) {
+			successorStr := uc.tokenService.DeriveRefreshToken(req.RefreshToken)
+			successor, err := uc.findSuccessor(ctx, successorStr)
+			if err != nil {
+				return err
+			}
+			if successor != nil {
+				// A client retrying a rotation within the grace window gets the same successor back
+				if successor.IsValid() {
+					session, err := uc.sessionRepo.FindByID(ctx, successor.TokenFamily)
+					if err != nil {
+						return err
+					}
+					response, err = uc.respond(ctx, session, successor, successorStr)
+					return err
+				}
+
+				// The retry lost a race with the end of the session rather than with a thief,
+				// unless the successor was itself rotated
+				next, err := uc.findSuccessor(ctx, uc.tokenService.DeriveRefreshToken(successorStr))
+				if err != nil {
+					return err
+				}
+				if next == nil {
+					return apperrors.ErrTokenRevoked
+				}
+			}
+		}
+
+		// Otherwise the token is being reused (potential token reuse attack):
+		// revoke all tokens in this family as a security measure
+		if err := uc.refreshTokenRepo.RevokeByTokenFamily(ctx, refreshToken.TokenFamily); err != nil {
+			return err
+		}
+		refreshToken.ReportReuse()
+		if err := uc.refreshTokenRepo.Update(ctx, refreshToken); err != nil {
+			return err
+		}
+		reused = refreshToken
+		return nil
+	})
 	if err != nil {
-		return nil, apperrors.ErrInvalidToken
+		return nil, err
+	}
+
+	if expired {
+		return nil, apperrors.ErrSessionExpired
 	}
 
-	// Check if token is revoked (potential token reuse attack)
-	if refreshToken.IsRevoked {
-		// Revoke all tokens in this family as a security measure
-		_ = uc.refreshTokenRepo.RevokeByTokenFamily(ctx, refreshToken.TokenFamily)
+	if reused != nil {
+		actor := dto.Actor{IPAddress: req.IPAddress, UserAgent: req.UserAgent}
+		recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventRefreshTokenReuse, reused.UserID, map[string]interface{}{
+			"session_id": reused.TokenFamily.String(),
+		})
 		return nil, apperrors.ErrTokenReuse
 	}
 
-	// Check if token is expired
-	if refreshToken.IsExpired() {
-		return nil, apperrors.ErrExpiredToken
+	return response, nil
+}
+
+// issueSuccessor saves the successor of a just rotated token and returns it with a new access token
+func (uc *RefreshTokenUseCase) issueSuccessor(ctx context.Context, parent *entity.RefreshToken, req dto.RefreshTokenRequest) (*dto.AuthResponse, error) {
+	session, err := uc.sessionRepo.FindByID(ctx, parent.TokenFamily)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions past their maximum lifetime or idle timeout must sign in again
+	lifetime := uc.sessionLifetimes.For(session.ClientID)
+	if session.IsExpired(lifetime) {
+		return nil, apperrors.ErrSessionExpired
+	}
+
+	// Record the use of the session
+	session.Touch(req.UserAgent, req.IPAddress)
+	if err := uc.sessionRepo.Update(ctx, session); err != nil {
+		return nil, err
 	}
 
-	// Revoke current token (token rotation)
-	refreshToken.Revoke()
-	if err := uc.refreshTokenRepo.Update(ctx, refreshToken); err != nil {
+	// The successor is derived from its parent, so retries can find it again
+	// (same token family, different token)
+	successorStr := uc.tokenService.DeriveRefreshToken(req.RefreshToken)
+	expiresAt := session.RefreshTokenExpiry(lifetime, uc.tokenService.GetRefreshTokenExpiry())
+	successor := entity.NewRefreshToken(parent.UserID, uc.opaqueTokens.Hash(successorStr), expiresAt, parent.TokenFamily)
+	successor.ParentID = &parent.ID // Track parent for rotation chain
+
+	if err := uc.refreshTokenRepo.Create(ctx, successor); err != nil {
 		return nil, err
 	}
 
+	return uc.respond(ctx, session, successor, successorStr)
+}
+
+// findSuccessor looks up the successor issued as successorStr, returning nil if the parent
+// was never rotated
+func (uc *RefreshTokenUseCase) findSuccessor(ctx context.Context, successorStr string) (*entity.RefreshToken, error) {
+	successor, err := uc.refreshTokenRepo.FindByTokenHash(ctx, uc.opaqueTokens.Hash(successorStr))
+	if err == apperrors.ErrInvalidToken {
+		return nil, nil
+	}
+	return successor, err
+}
+
+// respond issues an access token for the session of a refresh token whose raw value is refreshTokenStr.
+// The token keeps the authentication time and context of the session's login.
+func (uc *RefreshTokenUseCase) respond(ctx context.Context, session *entity.Session, refreshToken *entity.RefreshToken, refreshTokenStr string) (*dto.AuthResponse, error) {
 	// Get user
 	user, err := uc.userRepo.FindByID(ctx, refreshToken.UserID)
 	if err != nil {
@@ -69,34 +216,18 @@ func (uc *RefreshTokenUseCase) Execute(ctx context.Context, req dto.RefreshToken
 	}
 
 	// Generate new access token
-	accessToken, err := uc.tokenService.GenerateAccessToken(service.TokenClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Roles:  user.Roles,
-	})
+	claims, err := sessionTokenClaims(ctx, uc.tokenPermissions, user, session)
 	if err != nil {
 		return nil, err
 	}
-
-	// Generate new refresh token
-	newRefreshTokenStr, err := uc.tokenService.GenerateRefreshToken()
+	accessToken, err := uc.tokenService.GenerateAccessToken(claims)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create new refresh token entity (same token family, different token)
-	expiresAt := time.Now().Add(uc.tokenService.GetRefreshTokenExpiry())
-	newRefreshToken := entity.NewRefreshToken(user.ID, newRefreshTokenStr, expiresAt, refreshToken.TokenFamily)
-	newRefreshToken.ParentToken = &refreshToken.Token // Track parent for rotation chain
-
-	// Save new refresh token
-	if err := uc.refreshTokenRepo.Create(ctx, newRefreshToken); err != nil {
-		return nil, err
-	}
-
 	return &dto.AuthResponse{
 		AccessToken:  accessToken,
-		RefreshToken: newRefreshTokenStr,
+		RefreshToken: refreshTokenStr,
 		TokenType:    "Bearer",
 		ExpiresIn:    int64(uc.tokenService.GetAccessTokenExpiry().Seconds()),
 	}, nil
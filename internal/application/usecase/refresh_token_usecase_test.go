@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+)
+
+// refreshFixture is a signed-in user whose session holds the refresh token "login-token"
+type refreshFixture struct {
+	user        *entity.User
+	session     *entity.Session
+	sessions    *fakeSessionRepo
+	tokens      *fakeRefreshTokenRepo
+	auditLogger *fakeAuditLogger
+	refresh     *RefreshTokenUseCase
+	logout      *LogoutUseCase
+}
+
+func newRefreshFixture(t *testing.T, reuseGrace time.Duration) *refreshFixture {
+	t.Helper()
+
+	user := entity.NewUser("ada@example.com", "hashed:secret")
+	session := entity.NewSession(user.ID, "web", "", "test", "203.0.113.7")
+	tokens := newFakeRefreshTokenRepo()
+	opaqueTokens := &fakeOpaqueTokens{}
+	login := entity.NewRefreshToken(user.ID, opaqueTokens.Hash("login-token"), time.Now().Add(time.Hour), session.ID)
+	if err := tokens.Create(context.Background(), login); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := newFakeSessionRepo(session)
+	auditLogger := &fakeAuditLogger{}
+	return &refreshFixture{
+		user:        user,
+		session:     session,
+		sessions:    sessions,
+		tokens:      tokens,
+		auditLogger: auditLogger,
+		refresh: NewRefreshTokenUseCase(newFakeUserRepo(user), tokens, sessions, &fakeTxManager{},
+			fakeTokenService{}, nil, opaqueTokens, entity.SessionLifetimePolicy{}, auditLogger, reuseGrace),
+		logout: NewLogoutUseCase(tokens, auditLogger),
+	}
+}
+
+func (f *refreshFixture) refreshWith(token string) (*dto.AuthResponse, error) {
+	return f.refresh.Execute(context.Background(), dto.RefreshTokenRequest{RefreshToken: token, IPAddress: "203.0.113.7", UserAgent: "test"})
+}
+
+func (f *refreshFixture) reuseReported() bool {
+	for _, eventType := range f.tokens.eventTypes() {
+		if eventType == entity.EventRefreshTokenReuseDetected {
+			return true
+		}
+	}
+	for _, eventType := range f.auditLogger.types() {
+		if eventType == entity.AuditEventRefreshTokenReuse {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRefreshTokenUseCase_Execute(t *testing.T) {
+	// Steps are refresh token values to present, or "logout" to sign out;
+	// every step but the last must succeed
+	tests := []struct {
+		name        string
+		reuseGrace  time.Duration
+		steps       []string
+		wantToken   string
+		wantErr     error
+		wantReuse   bool
+		wantRevoked bool
+	}{
+		{
+			name:      "rotation",
+			steps:     []string{"login-token"},
+			wantToken: "login-token.next",
+		},
+		{
+			name:      "chained rotation",
+			steps:     []string{"login-token", "login-token.next"},
+			wantToken: "login-token.next.next",
+		},
+		{
+			name:       "retry within the grace window gets the same successor",
+			reuseGrace: time.Minute,
+			steps:      []string{"login-token", "login-token"},
+			wantToken:  "login-token.next",
+		},
+		{
+			name:        "reuse after the grace window",
+			steps:       []string{"login-token", "login-token"},
+			wantErr:     apperrors.ErrTokenReuse,
+			wantReuse:   true,
+			wantRevoked: true,
+		},
+		{
+			name:        "reuse within the grace window of a rotated successor",
+			reuseGrace:  time.Minute,
+			steps:       []string{"login-token", "login-token.next", "login-token"},
+			wantErr:     apperrors.ErrTokenReuse,
+			wantReuse:   true,
+			wantRevoked: true,
+		},
+		{
+			name:        "token presented after logout",
+			reuseGrace:  time.Minute,
+			steps:       []string{"logout", "login-token"},
+			wantErr:     apperrors.ErrTokenReuse,
+			wantReuse:   true,
+			wantRevoked: true,
+		},
+		{
+			name:        "retry within the grace window after logout",
+			reuseGrace:  time.Minute,
+			steps:       []string{"login-token", "logout", "login-token"},
+			wantErr:     apperrors.ErrTokenRevoked,
+			wantRevoked: true,
+		},
+		{
+			// The old token was rotated, so whoever still presents it is not its owner
+			name:        "rotated token reused after the grace window and logout",
+			steps:       []string{"login-token", "logout", "login-token"},
+			wantErr:     apperrors.ErrTokenReuse,
+			wantReuse:   true,
+			wantRevoked: true,
+		},
+		{
+			name:    "unknown token",
+			steps:   []string{"forged-token"},
+			wantErr: apperrors.ErrInvalidToken,
+		},
+		{
+			// Tokens are looked up by digest, so a leaked row cannot be replayed
+			name:    "stored digest presented as a token",
+			steps:   []string{"hash:login-token"},
+			wantErr: apperrors.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRefreshFixture(t, tt.reuseGrace)
+
+			var response *dto.AuthResponse
+			var err error
+			for i, step := range tt.steps {
+				if step == "logout" {
+					err = f.logout.Execute(context.Background(), dto.Actor{UserID: f.user.ID}, f.session.ID, false)
+				} else {
+					response, err = f.refreshWith(step)
+				}
+				if i < len(tt.steps)-1 && err != nil {
+					t.Fatalf("step %d (%s) error = %v", i, step, err)
+				}
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantToken != "" && response.RefreshToken != tt.wantToken {
+				t.Errorf("RefreshToken = %q, want %q", response.RefreshToken, tt.wantToken)
+			}
+			if reused := f.reuseReported(); reused != tt.wantReuse {
+				t.Errorf("reuse reported = %v, want %v", reused, tt.wantReuse)
+			}
+
+			active, _ := f.tokens.FindByUserID(context.Background(), f.user.ID)
+			revoked := len(active) > 0
+			for _, token := range active {
+				revoked = revoked && token.IsRevoked
+			}
+			if revoked != tt.wantRevoked {
+				t.Errorf("session revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenUseCase_ConcurrentRotation(t *testing.T) {
+	f := newRefreshFixture(t, time.Minute)
+
+	// A client firing several requests with the same token must not trip reuse detection
+	const requests = 8
+	var wg sync.WaitGroup
+	tokens := make([]string, requests)
+	errs := make([]error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := f.refreshWith("login-token")
+			errs[i] = err
+			if err == nil {
+				tokens[i] = response.RefreshToken
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < requests; i++ {
+		if errs[i] != nil {
+			t.Fatalf("request %d error = %v", i, errs[i])
+		}
+		if tokens[i] != "login-token.next" {
+			t.Errorf("request %d got %q, want the single successor", i, tokens[i])
+		}
+	}
+	if f.reuseReported() {
+		t.Error("concurrent rotation was reported as reuse")
+	}
+
+	issued, _ := f.tokens.FindByUserID(context.Background(), f.user.ID)
+	if len(issued) != 2 {
+		t.Errorf("%d refresh tokens stored, want the login token and one successor", len(issued))
+	}
+}
+
+func TestRefreshTokenUseCase_FailedRotationRollsBack(t *testing.T) {
+	f := newRefreshFixture(t, time.Minute)
+
+	// Issuing the successor fails after the presented token was rotated
+	f.user.Deactivate()
+	if _, err := f.refreshWith("login-token"); err != apperrors.ErrUserInactive {
+		t.Fatalf("Execute() error = %v, want %v", err, apperrors.ErrUserInactive)
+	}
+
+	issued, _ := f.tokens.FindByUserID(context.Background(), f.user.ID)
+	if len(issued) != 1 || issued[0].IsRevoked {
+		t.Fatalf("stored tokens = %+v, want the login token alone and still valid", issued)
+	}
+
+	// The rolled back rotation leaves nothing behind that would look like reuse
+	f.user.Activate()
+	response, err := f.refreshWith("login-token")
+	if err != nil {
+		t.Fatalf("retry error = %v", err)
+	}
+	if response.RefreshToken != "login-token.next" {
+		t.Errorf("RefreshToken = %q, want %q", response.RefreshToken, "login-token.next")
+	}
+	if f.reuseReported() {
+		t.Error("the retry was reported as reuse")
+	}
+}
+
+func TestRefreshTokenUseCase_StoresDigests(t *testing.T) {
+	f := newRefreshFixture(t, 0)
+
+	if _, err := f.refreshWith("login-token"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	login, err := f.tokens.FindByTokenHash(context.Background(), "hash:login-token")
+	if err != nil {
+		t.Fatalf("login token not found by digest: %v", err)
+	}
+	successor, err := f.tokens.FindByTokenHash(context.Background(), "hash:login-token.next")
+	if err != nil {
+		t.Fatalf("successor not found by digest: %v", err)
+	}
+	if successor.ParentID == nil || *successor.ParentID != login.ID {
+		t.Errorf("successor ParentID = %v, want %v", successor.ParentID, login.ID)
+	}
+	if _, err := f.tokens.FindByTokenHash(context.Background(), "login-token.next"); err == nil {
+		t.Error("successor was stored in the clear")
+	}
+}
+
+func TestRefreshTokenUseCase_LegacyRevokedToken(t *testing.T) {
+	f := newRefreshFixture(t, time.Minute)
+
+	// A token revoked before rotation chains were linked by ID has no known successor,
+	// so presenting it is treated as reuse even within the grace window
+	legacy := entity.NewRefreshToken(f.user.ID, "hash:legacy-token", time.Now().Add(time.Hour), f.session.ID)
+	legacy.Revoke()
+	if err := f.tokens.Create(context.Background(), legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.refreshWith("legacy-token"); err != apperrors.ErrTokenReuse {
+		t.Fatalf("Execute() error = %v, want %v", err, apperrors.ErrTokenReuse)
+	}
+	if !f.reuseReported() {
+		t.Error("reuse not reported")
+	}
+	login, _ := f.tokens.FindByTokenHash(context.Background(), "hash:login-token")
+	if !login.IsRevoked {
+		t.Error("the rest of the family was not revoked")
+	}
+}
@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
 
 	"auth-go/internal/application/dto"
 	"auth-go/internal/domain/entity"
@@ -14,20 +17,48 @@ import (
 // RegisterUseCase handles user registration
 type RegisterUseCase struct {
 	userRepo       repository.UserRepository
+	txManager      repository.TxManager
 	passwordHasher service.PasswordHasher
+	passwordPolicy service.PasswordPolicy
+	emailSender    service.EmailSender
+	auditLogger    service.AuditLogger
+	// enumerationSafe hides whether an email is already registered: the caller always
+	// gets the same answer and the existing owner is notified by email instead
+	enumerationSafe   bool
+	loginURL          string
+	forgotPasswordURL string
 }
 
 // NewRegisterUseCase creates a new register use case
 func NewRegisterUseCase(
 	userRepo repository.UserRepository,
+	txManager repository.TxManager,
 	passwordHasher service.PasswordHasher,
+	passwordPolicy service.PasswordPolicy,
+	emailSender service.EmailSender,
+	auditLogger service.AuditLogger,
+	enumerationSafe bool,
+	loginURL string,
+	forgotPasswordURL string,
 ) *RegisterUseCase {
 	return &RegisterUseCase{
-		userRepo:       userRepo,
-		passwordHasher: passwordHasher,
+		userRepo:          userRepo,
+		txManager:         txManager,
+		passwordHasher:    passwordHasher,
+		passwordPolicy:    passwordPolicy,
+		emailSender:       emailSender,
+		auditLogger:       auditLogger,
+		enumerationSafe:   enumerationSafe,
+		loginURL:          loginURL,
+		forgotPasswordURL: forgotPasswordURL,
 	}
 }
 
+// IsEnumerationSafe reports whether registration hides existing accounts
+func (uc *RegisterUseCase) IsEnumerationSafe() bool {
+	return uc.enumerationSafe
+}
+
 // Execute executes the register use case
 func (uc *RegisterUseCase) Execute(ctx context.Context, req dto.RegisterRequest) error {
 	// Validate email
@@ -36,39 +67,74 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, req dto.RegisterRequest)
 		return apperrors.ErrInvalidEmail
 	}
 
+	// Create user entity
+	user := entity.NewUser(email.Value(), "")
+	user.MarkSelfRegistered()
+
+	// Validate password against the password policy
+	if err := uc.passwordPolicy.Validate(ctx, req.Password, user); err != nil {
+		return err
+	}
+
+	// Hash password before the existence check so both outcomes cost the same: the
+	// already registered branch must not skip the most expensive step
+	passwordHash, err := uc.passwordHasher.Hash(req.Password)
+	if err != nil {
+		return err
+	}
+	user.ChangePassword(passwordHash)
+
 	// Check if user already exists
 	exists, err := uc.userRepo.ExistsByEmail(ctx, email.Value())
 	if err != nil {
 		return err
 	}
 	if exists {
-		return apperrors.ErrUserAlreadyExists
+		return uc.alreadyRegistered(ctx, email.Value())
 	}
 
-	// Validate password
-	password, err := valueobject.NewPassword(req.Password)
-	if err != nil {
-		return apperrors.ErrInvalidPassword
-	}
-
-	// Hash password
-	passwordHash, err := uc.passwordHasher.Hash(password.Value())
+	// Save user and password history together, so a failure leaves no account behind
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return uc.passwordPolicy.Remember(ctx, user)
+	})
 	if err != nil {
+		if errors.Is(err, apperrors.ErrUserAlreadyExists) {
+			// Lost a race with a concurrent registration
+			return uc.alreadyRegistered(ctx, email.Value())
+		}
 		return err
 	}
 
-	// Create user entity
-	user := entity.NewUser(email.Value(), passwordHash)
+	actor := dto.Actor{UserID: user.ID, IPAddress: req.IPAddress, UserAgent: req.UserAgent}
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventUserRegistered, user.ID, map[string]interface{}{
+		"email": user.Email,
+	})
 
-	// Set roles if provided, otherwise default to user role
-	if len(req.Roles) > 0 {
-		roles := make([]entity.Role, len(req.Roles))
-		for i, roleStr := range req.Roles {
-			roles[i] = entity.ParseRole(roleStr)
-		}
-		user.Roles = roles
+	return nil
+}
+
+// alreadyRegistered handles a registration attempt for an existing email
+func (uc *RegisterUseCase) alreadyRegistered(ctx context.Context, email string) error {
+	if !uc.enumerationSafe {
+		return apperrors.ErrUserAlreadyExists
+	}
+
+	err := uc.emailSender.Send(ctx, service.EmailMessage{
+		To:      email,
+		Subject: "Someone tried to register with your email address",
+		Body: fmt.Sprintf(
+			"Someone tried to create a new account with this email address, but you already have one.\n\n"+
+				"If this was you, sign in at %s or reset your password at %s.\n\n"+
+				"If it was not you, you can ignore this email; your account has not been changed.",
+			uc.loginURL, uc.forgotPasswordURL,
+		),
+	})
+	if err != nil {
+		log.Printf("Failed to send duplicate registration notice: %v", err)
 	}
 
-	// Save user
-	return uc.userRepo.Create(ctx, user)
+	return nil
 }
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+)
+
+func TestRegisterUseCase_Execute(t *testing.T) {
+	existing := entity.NewUser("taken@example.com", "hashed:old")
+	errHistory := errors.New("password history unavailable")
+
+	tests := []struct {
+		name            string
+		enumerationSafe bool
+		email           string
+		rememberErr     error
+		wantErr         error
+		wantCreated     bool
+		wantNotice      bool
+	}{
+		{name: "new account", email: "new@example.com", wantCreated: true},
+		{name: "existing account", email: "taken@example.com", wantErr: apperrors.ErrUserAlreadyExists},
+		{name: "existing account, enumeration safe", enumerationSafe: true, email: "taken@example.com", wantNotice: true},
+		{name: "password history failure rolls back the account", email: "new@example.com", rememberErr: errHistory, wantErr: errHistory},
+		{name: "invalid email", email: "not-an-email", wantErr: apperrors.ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := newFakeUserRepo(existing)
+			hasher := &fakePasswordHasher{}
+			policy := &fakePasswordPolicy{rememberErr: tt.rememberErr}
+			emailSender := newFakeEmailSender()
+			uc := NewRegisterUseCase(userRepo, &fakeTxManager{}, hasher, policy, emailSender, &fakeAuditLogger{},
+				tt.enumerationSafe, "https://example.com/login", "https://example.com/forgot")
+
+			err := uc.Execute(context.Background(), dto.RegisterRequest{Email: tt.email, Password: "Secret#Pass123"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			user, findErr := userRepo.FindByEmail(context.Background(), tt.email)
+			if created := findErr == nil && tt.email != existing.Email; created != tt.wantCreated {
+				t.Errorf("account created = %v, want %v", created, tt.wantCreated)
+			}
+			if tt.wantCreated && !policy.rememberedInTx {
+				t.Error("password history was written outside the account's transaction")
+			}
+			if tt.wantCreated && !reflect.DeepEqual(user.Roles, []entity.Role{entity.RoleUser}) {
+				t.Errorf("roles = %v, want only the user role", user.Roles)
+			}
+			if tt.wantCreated {
+				var types []entity.DomainEventType
+				for _, event := range user.PullEvents() {
+					types = append(types, event.Type)
+				}
+				if !reflect.DeepEqual(types, []entity.DomainEventType{entity.EventUserCreated, entity.EventUserRegistered}) {
+					t.Errorf("events = %v, want user.created and user.registered", types)
+				}
+			}
+
+			select {
+			case msg := <-emailSender.sent:
+				if !tt.wantNotice {
+					t.Errorf("unexpected email %q", msg.Subject)
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tt.wantNotice {
+					t.Error("the owner of the existing account was not notified")
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterUseCase_SameHashingWork(t *testing.T) {
+	// New and existing emails must do the same password hashing, or response times reveal accounts
+	work := func(email string) int {
+		hasher := &fakePasswordHasher{}
+		uc := NewRegisterUseCase(newFakeUserRepo(entity.NewUser("taken@example.com", "hashed:old")), &fakeTxManager{},
+			hasher, &fakePasswordPolicy{}, newFakeEmailSender(), &fakeAuditLogger{}, true, "", "")
+		if err := uc.Execute(context.Background(), dto.RegisterRequest{Email: email, Password: "Secret#Pass123"}); err != nil {
+			t.Fatalf("Execute(%s) error = %v", email, err)
+		}
+		return hasher.work()
+	}
+
+	if newAccount, existingAccount := work("new@example.com"), work("taken@example.com"); newAccount != existingAccount {
+		t.Errorf("hashing work: new account %d, existing account %d", newAccount, existingAccount)
+	}
+}
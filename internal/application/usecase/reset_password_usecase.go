@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+)
+
+// ResetPasswordUseCase sets a new password using an emailed reset or invite token
+type ResetPasswordUseCase struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.VerificationTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	txManager        repository.TxManager
+	passwordHasher   service.PasswordHasher
+	passwordPolicy   service.PasswordPolicy
+	opaqueTokens     service.OpaqueTokenService
+}
+
+// NewResetPasswordUseCase creates a new reset password use case
+func NewResetPasswordUseCase(
+	userRepo repository.UserRepository,
+	verificationRepo repository.VerificationTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	txManager repository.TxManager,
+	passwordHasher service.PasswordHasher,
+	passwordPolicy service.PasswordPolicy,
+	opaqueTokens service.OpaqueTokenService,
+) *ResetPasswordUseCase {
+	return &ResetPasswordUseCase{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		txManager:        txManager,
+		passwordHasher:   passwordHasher,
+		passwordPolicy:   passwordPolicy,
+		opaqueTokens:     opaqueTokens,
+	}
+}
+
+// Execute executes the reset password use case
+func (uc *ResetPasswordUseCase) Execute(ctx context.Context, req dto.ResetPasswordRequest) error {
+	// Find reset token by its hash
+	token, err := uc.verificationRepo.FindByTokenHash(ctx, uc.opaqueTokens.Hash(req.Token))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidToken) {
+			return apperrors.ErrInvalidToken
+		}
+		return err
+	}
+
+	if token.Purpose != entity.TokenPurposePasswordReset && token.Purpose != entity.TokenPurposeInvite {
+		return apperrors.ErrInvalidToken
+	}
+	if token.IsUsed() {
+		return apperrors.ErrInvalidToken
+	}
+
+	if token.IsExpired() {
+		return apperrors.ErrExpiredToken
+	}
+
+	// Get user
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return apperrors.ErrInvalidToken
+	}
+
+	if !user.IsActive {
+		return apperrors.ErrUserInactive
+	}
+
+	// Validate password against the password policy
+	if err := uc.passwordPolicy.Validate(ctx, req.NewPassword, user); err != nil {
+		return err
+	}
+
+	// Hash password
+	passwordHash, err := uc.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// The token is spent only if the password is changed, and the password is changed
+	// only together with its history entry and the end of every session
+	return uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Consume token first so concurrent requests cannot use it twice
+		if err := uc.verificationRepo.Consume(ctx, token.ID); err != nil {
+			return err
+		}
+
+		user.ChangePassword(passwordHash)
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		if err := uc.passwordPolicy.Remember(ctx, user); err != nil {
+			return err
+		}
+
+		// Sign out everywhere: whoever had access before the reset must log in again
+		return uc.refreshTokenRepo.RevokeByUserID(ctx, user.ID)
+	})
+}
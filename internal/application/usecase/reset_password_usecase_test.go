@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestResetPasswordUseCase_Execute(t *testing.T) {
+	historyErr := errors.New("password history unavailable")
+
+	tests := []struct {
+		name        string
+		rememberErr error
+		wantErr     error
+		wantChanged bool
+	}{
+		{name: "reset", wantChanged: true},
+		{name: "history failure rolls back", rememberErr: historyErr, wantErr: historyErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("ada@example.com", "hashed:old")
+			opaqueTokens := &fakeOpaqueTokens{}
+			verificationRepo := &fakeVerificationRepo{}
+			resetToken := entity.NewVerificationToken(user.ID, entity.TokenPurposePasswordReset, opaqueTokens.Hash("reset-token"), time.Now().Add(time.Hour))
+			if err := verificationRepo.Create(context.Background(), resetToken); err != nil {
+				t.Fatal(err)
+			}
+			tokens := newFakeRefreshTokenRepo()
+			if err := tokens.Create(context.Background(), entity.NewRefreshToken(user.ID, "hash:session", time.Now().Add(time.Hour), uuid.New())); err != nil {
+				t.Fatal(err)
+			}
+			policy := &fakePasswordPolicy{rememberErr: tt.rememberErr}
+			uc := NewResetPasswordUseCase(newFakeUserRepo(user), verificationRepo, tokens, &fakeTxManager{}, &fakePasswordHasher{}, policy, opaqueTokens)
+
+			err := uc.Execute(context.Background(), dto.ResetPasswordRequest{Token: "reset-token", NewPassword: "new-secret"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if used := resetToken.IsUsed(); used != tt.wantChanged {
+				t.Errorf("token used = %v, want %v", used, tt.wantChanged)
+			}
+			sessions, _ := tokens.FindByUserID(context.Background(), user.ID)
+			if revoked := sessions[0].IsRevoked; revoked != tt.wantChanged {
+				t.Errorf("sessions revoked = %v, want %v", revoked, tt.wantChanged)
+			}
+			if tt.wantChanged && !policy.rememberedInTx {
+				t.Error("password history was written outside the reset's transaction")
+			}
+		})
+	}
+}
+
+func TestResetPasswordUseCase_TokenIsSingleUse(t *testing.T) {
+	user := entity.NewUser("ada@example.com", "hashed:old")
+	opaqueTokens := &fakeOpaqueTokens{}
+	verificationRepo := &fakeVerificationRepo{}
+	if err := verificationRepo.Create(context.Background(), entity.NewVerificationToken(user.ID, entity.TokenPurposePasswordReset, opaqueTokens.Hash("reset-token"), time.Now().Add(time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	uc := NewResetPasswordUseCase(newFakeUserRepo(user), verificationRepo, newFakeRefreshTokenRepo(), &fakeTxManager{}, &fakePasswordHasher{}, &fakePasswordPolicy{}, opaqueTokens)
+
+	req := dto.ResetPasswordRequest{Token: "reset-token", NewPassword: "new-secret"}
+	if err := uc.Execute(context.Background(), req); err != nil {
+		t.Fatalf("first Execute() error = %v", err)
+	}
+	if err := uc.Execute(context.Background(), req); err != apperrors.ErrInvalidToken {
+		t.Errorf("second Execute() error = %v, want %v", err, apperrors.ErrInvalidToken)
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// RevokeRoleUseCase removes a role from a user (admin only)
+type RevokeRoleUseCase struct {
+	userRepo    repository.UserRepository
+	roleRepo    repository.RoleRepository
+	auditLogger service.AuditLogger
+}
+
+// NewRevokeRoleUseCase creates a new revoke role use case
+func NewRevokeRoleUseCase(userRepo repository.UserRepository, roleRepo repository.RoleRepository, auditLogger service.AuditLogger) *RevokeRoleUseCase {
+	return &RevokeRoleUseCase{
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute executes the revoke role use case
+func (uc *RevokeRoleUseCase) Execute(ctx context.Context, actor dto.Actor, userID uuid.UUID, req dto.RoleRequest) (*entity.User, error) {
+	role, err := parseRole(ctx, uc.roleRepo, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admins cannot demote themselves and lock everyone out of the admin API
+	if role == entity.RoleAdmin && actor.UserID == userID {
+		return nil, apperrors.ErrForbidden
+	}
+
+	// Taking away permissions the actor does not hold is as privileged as granting them
+	permissions, err := rolePermissions(ctx, uc.roleRepo, []entity.Role{role})
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGrant(actor, permissions); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoking a role the user does not have is a no-op
+	if !user.HasRole(role) {
+		return user, nil
+	}
+
+	// Every user keeps at least one role
+	if len(user.Roles) == 1 {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	user.RemoveRole(role)
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventRoleRevoked, user.ID, map[string]interface{}{
+		"role": role.String(),
+	})
+
+	return user, nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// RevokeSessionUseCase signs the acting user out of one of their sessions (e.g. a lost phone)
+type RevokeSessionUseCase struct {
+	sessionRepo      repository.SessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditLogger      service.AuditLogger
+}
+
+// NewRevokeSessionUseCase creates a new revoke session use case
+func NewRevokeSessionUseCase(
+	sessionRepo repository.SessionRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	auditLogger service.AuditLogger,
+) *RevokeSessionUseCase {
+	return &RevokeSessionUseCase{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditLogger:      auditLogger,
+	}
+}
+
+// Execute executes the revoke session use case
+func (uc *RevokeSessionUseCase) Execute(ctx context.Context, actor dto.Actor, sessionID uuid.UUID) error {
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Sessions of other users do not exist as far as the actor is concerned
+	if session.UserID != actor.UserID {
+		return apperrors.ErrSessionNotFound
+	}
+
+	if err := uc.refreshTokenRepo.RevokeByTokenFamily(ctx, session.ID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventSessionRevoked, actor.UserID, map[string]interface{}{
+		"session_id": session.ID.String(),
+	})
+
+	return nil
+}
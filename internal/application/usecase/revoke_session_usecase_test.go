@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestRevokeSessionUseCase_Execute(t *testing.T) {
+	user := entity.NewUser("ada@example.com", "hashed:pw")
+	other := entity.NewUser("other@example.com", "hashed:pw")
+	phone := entity.NewSession(user.ID, "web", "Phone", "test", "203.0.113.7")
+	laptop := entity.NewSession(user.ID, "web", "Laptop", "test", "203.0.113.8")
+	elsewhere := entity.NewSession(other.ID, "web", "", "test", "203.0.113.9")
+
+	tests := []struct {
+		name        string
+		sessionID   uuid.UUID
+		wantErr     error
+		wantRevoked []bool
+	}{
+		{name: "own session", sessionID: phone.ID, wantRevoked: []bool{true, false, false}},
+		{name: "session of another user", sessionID: elsewhere.ID, wantErr: apperrors.ErrSessionNotFound, wantRevoked: []bool{false, false, false}},
+		{name: "unknown session", sessionID: uuid.New(), wantErr: apperrors.ErrSessionNotFound, wantRevoked: []bool{false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := newSignedInDevices(t, phone, laptop, elsewhere)
+			audit := &fakeAuditLogger{}
+			uc := NewRevokeSessionUseCase(newFakeSessionRepo(phone, laptop, elsewhere), tokens, audit)
+
+			err := uc.Execute(context.Background(), dto.Actor{UserID: user.ID}, tt.sessionID)
+			if err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got := revokedSessions(tokens, phone, laptop, elsewhere)
+			for i := range got {
+				if got[i] != tt.wantRevoked[i] {
+					t.Errorf("revoked = %v, want %v", got, tt.wantRevoked)
+					break
+				}
+			}
+			if wantAudit := tt.wantErr == nil; (len(audit.types()) == 1) != wantAudit {
+				t.Errorf("audit events = %v", audit.types())
+			}
+		})
+	}
+}
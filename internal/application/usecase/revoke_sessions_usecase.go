@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// RevokeSessionsUseCase signs a user out of every session (admin only)
+type RevokeSessionsUseCase struct {
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditLogger      service.AuditLogger
+}
+
+// NewRevokeSessionsUseCase creates a new revoke sessions use case
+func NewRevokeSessionsUseCase(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	auditLogger service.AuditLogger,
+) *RevokeSessionsUseCase {
+	return &RevokeSessionsUseCase{
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditLogger:      auditLogger,
+	}
+}
+
+// Execute executes the revoke sessions use case
+func (uc *RevokeSessionsUseCase) Execute(ctx context.Context, actor dto.Actor, userID uuid.UUID) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeTarget(ctx, uc.roleRepo, actor, user); err != nil {
+		return err
+	}
+
+	if err := uc.refreshTokenRepo.RevokeByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventSessionsRevoked, user.ID, nil)
+
+	return nil
+}
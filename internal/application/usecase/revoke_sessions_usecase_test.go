@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+func TestRevokeSessionsUseCase_Execute(t *testing.T) {
+	user := entity.NewUser("target@example.com", "hashed:pw")
+	other := entity.NewUser("other@example.com", "hashed:pw")
+	tokens := newFakeRefreshTokenRepo()
+	for _, owner := range []*entity.User{user, user, other} {
+		if err := tokens.Create(context.Background(), entity.NewRefreshToken(owner.ID, "hash:"+uuid.NewString(), time.Now().Add(time.Hour), uuid.New())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	audit := &fakeAuditLogger{}
+	uc := NewRevokeSessionsUseCase(newFakeUserRepo(user, other), grantRoles(), tokens, audit)
+
+	if err := uc.Execute(context.Background(), roleManager(), user.ID); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	for _, owner := range []*entity.User{user, other} {
+		sessions, _ := tokens.FindByUserID(context.Background(), owner.ID)
+		for _, session := range sessions {
+			if want := owner == user; session.IsRevoked != want {
+				t.Errorf("session of %s revoked = %v, want %v", owner.Email, session.IsRevoked, want)
+			}
+		}
+	}
+	if got := audit.types(); len(got) != 1 || got[0] != entity.AuditEventSessionsRevoked {
+		t.Errorf("audit events = %v, want %v", got, entity.AuditEventSessionsRevoked)
+	}
+}
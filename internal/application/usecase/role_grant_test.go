@@ -0,0 +1,371 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+)
+
+const roleAuditor entity.Role = "auditor"
+const roleSupport entity.Role = "support"
+
+// grantRoles returns the built-in roles, an auditor role that adds audit:read to what it
+// inherits from the moderator, and a support role with no parent
+func grantRoles() *fakeRoleRepo {
+	moderator := entity.RoleModerator
+	return newFakeRoleRepo(
+		entity.NewRoleDefinition(entity.RoleUser, "", nil, nil),
+		entity.NewRoleDefinition(entity.RoleModerator, "", []entity.Permission{entity.PermissionUsersRead, entity.PermissionUsersBan}, nil),
+		entity.NewRoleDefinition(entity.RoleAdmin, "", entity.AllPermissions, nil),
+		entity.NewRoleDefinition(roleAuditor, "", []entity.Permission{entity.PermissionAuditRead}, &moderator),
+		entity.NewRoleDefinition(roleSupport, "", []entity.Permission{entity.PermissionUsersRead}, nil),
+	)
+}
+
+// roleManager can manage users and roles but holds neither audit nor webhook permissions
+func roleManager(extra ...entity.Permission) dto.Actor {
+	return dto.Actor{Permissions: append([]entity.Permission{
+		entity.PermissionUsersRead,
+		entity.PermissionUsersWrite,
+		entity.PermissionUsersBan,
+		entity.PermissionRolesRead,
+		entity.PermissionRolesWrite,
+		entity.PermissionRolesAssign,
+	}, extra...)}
+}
+
+func TestAssignRoleUseCase_Grants(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   dto.Actor
+		role    entity.Role
+		wantErr error
+	}{
+		{name: "role with held permissions", actor: roleManager(), role: entity.RoleModerator},
+		{name: "admin role", actor: roleManager(), role: entity.RoleAdmin, wantErr: apperrors.ErrForbidden},
+		{name: "role adding a permission to its parent", actor: roleManager(), role: roleAuditor, wantErr: apperrors.ErrForbidden},
+		{name: "role whose lineage is held", actor: roleManager(entity.PermissionAuditRead), role: roleAuditor},
+		{name: "no permissions", actor: dto.Actor{}, role: entity.RoleModerator, wantErr: apperrors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("target@example.com", "hashed:pw")
+			audit := &fakeAuditLogger{}
+			uc := NewAssignRoleUseCase(newFakeUserRepo(user), grantRoles(), audit)
+
+			_, err := uc.Execute(context.Background(), tt.actor, user.ID, dto.RoleRequest{Role: tt.role.String()})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := user.HasRole(tt.role); got != (tt.wantErr == nil) {
+				t.Errorf("user has role %q = %v, want %v", tt.role, got, tt.wantErr == nil)
+			}
+			if tt.wantErr != nil && len(audit.types()) != 0 {
+				t.Errorf("audit events = %v, want none", audit.types())
+			}
+		})
+	}
+}
+
+func TestRevokeRoleUseCase_Grants(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   dto.Actor
+		role    entity.Role
+		wantErr error
+	}{
+		{name: "role with held permissions", actor: roleManager(), role: entity.RoleModerator},
+		{name: "admin role", actor: roleManager(), role: entity.RoleAdmin, wantErr: apperrors.ErrForbidden},
+		{name: "role adding a permission to its parent", actor: roleManager(), role: roleAuditor, wantErr: apperrors.ErrForbidden},
+		{name: "role whose lineage is held", actor: roleManager(entity.PermissionAuditRead), role: roleAuditor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("target@example.com", "hashed:pw")
+			user.SetRoles([]entity.Role{entity.RoleUser, tt.role})
+			audit := &fakeAuditLogger{}
+			uc := NewRevokeRoleUseCase(newFakeUserRepo(user), grantRoles(), audit)
+
+			_, err := uc.Execute(context.Background(), tt.actor, user.ID, dto.RoleRequest{Role: tt.role.String()})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := user.HasRole(tt.role); got != (tt.wantErr != nil) {
+				t.Errorf("user has role %q = %v, want %v", tt.role, got, tt.wantErr != nil)
+			}
+			if tt.wantErr != nil && len(audit.types()) != 0 {
+				t.Errorf("audit events = %v, want none", audit.types())
+			}
+		})
+	}
+}
+
+func TestCreateUserUseCase_Grants(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   []string
+		wantErr error
+	}{
+		{name: "default role", roles: nil},
+		{name: "role with held permissions", roles: []string{"moderator"}},
+		{name: "admin role", roles: []string{"user", "admin"}, wantErr: apperrors.ErrForbidden},
+		{name: "role adding a permission to its parent", roles: []string{"auditor"}, wantErr: apperrors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := newFakeUserRepo()
+			uc := NewCreateUserUseCase(userRepo, grantRoles(), &fakeVerificationRepo{}, &fakeOpaqueTokens{},
+				newFakeEmailSender(), &fakeAuditLogger{}, time.Hour, "https://example.com/invite")
+
+			_, err := uc.Execute(context.Background(), roleManager(), dto.CreateUserRequest{Email: "new@example.com", Roles: tt.roles})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			exists, _ := userRepo.ExistsByEmail(context.Background(), "new@example.com")
+			if exists != (tt.wantErr == nil) {
+				t.Errorf("user created = %v, want %v", exists, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestUpdateUserUseCase_Grants(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   []string
+		wantErr error
+	}{
+		{name: "keeping a role the actor could not grant", roles: []string{"auditor", "user"}},
+		{name: "removing a role the actor could not grant", roles: []string{"user"}, wantErr: apperrors.ErrForbidden},
+		{name: "adding a role with held permissions", roles: []string{"auditor", "support"}},
+		{name: "adding the admin role", roles: []string{"auditor", "admin"}, wantErr: apperrors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("target@example.com", "hashed:pw")
+			user.SetRoles([]entity.Role{roleAuditor})
+			uc := NewUpdateUserUseCase(newFakeUserRepo(user), grantRoles(), &fakeAuditLogger{})
+
+			_, err := uc.Execute(context.Background(), roleManager(), user.ID, dto.UpdateUserRequest{Roles: tt.roles})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && (len(user.Roles) != 1 || user.Roles[0] != roleAuditor) {
+				t.Errorf("roles = %v, want unchanged", user.Roles)
+			}
+		})
+	}
+}
+
+func TestCreateRoleUseCase_Grants(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     dto.CreateRoleRequest
+		wantErr error
+	}{
+		{name: "held permissions", req: dto.CreateRoleRequest{Name: "helpdesk", Permissions: []string{"users:read", "users:ban"}}},
+		{name: "held parent", req: dto.CreateRoleRequest{Name: "helpdesk", Parent: "moderator"}},
+		{name: "permission not held", req: dto.CreateRoleRequest{Name: "helpdesk", Permissions: []string{"webhooks:write"}}, wantErr: apperrors.ErrForbidden},
+		{name: "parent granting a permission not held", req: dto.CreateRoleRequest{Name: "helpdesk", Parent: "admin"}, wantErr: apperrors.ErrForbidden},
+		{name: "grandparent lineage not held", req: dto.CreateRoleRequest{Name: "helpdesk", Parent: "auditor"}, wantErr: apperrors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roleRepo := grantRoles()
+			uc := NewCreateRoleUseCase(roleRepo, &fakeAuditLogger{})
+
+			_, err := uc.Execute(context.Background(), roleManager(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			_, err = roleRepo.FindByName(context.Background(), entity.Role(tt.req.Name))
+			if created := err == nil; created != (tt.wantErr == nil) {
+				t.Errorf("role created = %v, want %v", created, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestUpdateRoleUseCase_Grants(t *testing.T) {
+	description := "updated"
+	noParent := ""
+	admin := "admin"
+	auditor := "auditor"
+
+	tests := []struct {
+		name    string
+		role    entity.Role
+		req     dto.UpdateRoleRequest
+		wantErr error
+	}{
+		{name: "description of a role the actor could not grant", role: roleAuditor, req: dto.UpdateRoleRequest{Description: &description}},
+		{name: "held permissions", role: roleSupport, req: dto.UpdateRoleRequest{Permissions: []string{"users:read", "users:ban"}}},
+		{name: "removing a parent", role: roleAuditor, req: dto.UpdateRoleRequest{Parent: &noParent}, wantErr: apperrors.ErrForbidden},
+		{name: "permission not held", role: roleSupport, req: dto.UpdateRoleRequest{Permissions: []string{"audit:read"}}, wantErr: apperrors.ErrForbidden},
+		{name: "parent granting a permission not held", role: roleSupport, req: dto.UpdateRoleRequest{Parent: &admin}, wantErr: apperrors.ErrForbidden},
+		{name: "parent lineage not held", role: roleSupport, req: dto.UpdateRoleRequest{Parent: &auditor}, wantErr: apperrors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roleRepo := grantRoles()
+			before, _ := roleRepo.FindByName(context.Background(), tt.role)
+			uc := NewUpdateRoleUseCase(roleRepo, &fakeAuditLogger{})
+
+			_, err := uc.Execute(context.Background(), roleManager(), tt.role.String(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			after, _ := roleRepo.FindByName(context.Background(), tt.role)
+			if tt.wantErr != nil && !after.UpdatedAt.Equal(before.UpdatedAt) {
+				t.Errorf("role was updated despite %v", tt.wantErr)
+			}
+		})
+	}
+}
+
+// moderator holds the permissions of the built-in moderator role
+func moderator() dto.Actor {
+	return dto.Actor{Permissions: []entity.Permission{entity.PermissionUsersRead, entity.PermissionUsersBan}}
+}
+
+func TestBanUseCases_Targets(t *testing.T) {
+	actions := map[string]func(uc banUseCases, actor dto.Actor, target *entity.User) error{
+		"deactivate": func(uc banUseCases, actor dto.Actor, target *entity.User) error {
+			_, err := uc.setUserActive.Execute(context.Background(), actor, target.ID, false)
+			return err
+		},
+		"revoke sessions": func(uc banUseCases, actor dto.Actor, target *entity.User) error {
+			return uc.revokeSessions.Execute(context.Background(), actor, target.ID)
+		},
+		"unlock": func(uc banUseCases, actor dto.Actor, target *entity.User) error {
+			return uc.unlockAccount.Execute(context.Background(), actor, target.ID)
+		},
+	}
+
+	tests := []struct {
+		name    string
+		actor   dto.Actor
+		target  entity.Role
+		wantErr error
+	}{
+		{name: "moderator on a user", actor: moderator(), target: entity.RoleUser},
+		{name: "moderator on a moderator", actor: moderator(), target: entity.RoleModerator},
+		{name: "moderator on an admin", actor: moderator(), target: entity.RoleAdmin, wantErr: apperrors.ErrForbidden},
+		{name: "moderator on an auditor", actor: moderator(), target: roleAuditor, wantErr: apperrors.ErrForbidden},
+		{name: "admin on an admin", actor: dto.Actor{Permissions: entity.AllPermissions}, target: entity.RoleAdmin},
+	}
+
+	for _, tt := range tests {
+		for action, execute := range actions {
+			t.Run(tt.name+"/"+action, func(t *testing.T) {
+				target := entity.NewUser("target@example.com", "hashed:pw")
+				target.SetRoles([]entity.Role{tt.target})
+				tokens := newFakeRefreshTokenRepo()
+				if err := tokens.Create(context.Background(), entity.NewRefreshToken(target.ID, "hash:session", time.Now().Add(time.Hour), target.ID)); err != nil {
+					t.Fatal(err)
+				}
+				audit := &fakeAuditLogger{}
+				uc := newBanUseCases(newFakeUserRepo(target), tokens, audit)
+
+				err := execute(uc, tt.actor, target)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantErr == nil {
+					return
+				}
+
+				sessions, _ := tokens.FindByUserID(context.Background(), target.ID)
+				if !target.IsActive || sessions[0].IsRevoked || len(audit.types()) != 0 {
+					t.Errorf("refused action changed the target: active %v, sessions revoked %v, audit events %v",
+						target.IsActive, sessions[0].IsRevoked, audit.types())
+				}
+			})
+		}
+	}
+}
+
+// banUseCases are the use cases guarded by users:ban
+type banUseCases struct {
+	setUserActive  *SetUserActiveUseCase
+	revokeSessions *RevokeSessionsUseCase
+	unlockAccount  *UnlockAccountUseCase
+}
+
+func newBanUseCases(users *fakeUserRepo, tokens *fakeRefreshTokenRepo, audit *fakeAuditLogger) banUseCases {
+	throttle, _ := newTestThrottle()
+	return banUseCases{
+		setUserActive:  NewSetUserActiveUseCase(users, grantRoles(), tokens, audit),
+		revokeSessions: NewRevokeSessionsUseCase(users, grantRoles(), tokens, audit),
+		unlockAccount:  NewUnlockAccountUseCase(users, grantRoles(), throttle, audit),
+	}
+}
+
+func TestUserManagementUseCases_Targets(t *testing.T) {
+	actions := map[string]func(users *fakeUserRepo, actor dto.Actor, target *entity.User) error{
+		"change email": func(users *fakeUserRepo, actor dto.Actor, target *entity.User) error {
+			email := "attacker@example.com"
+			_, err := NewUpdateUserUseCase(users, grantRoles(), &fakeAuditLogger{}).
+				Execute(context.Background(), actor, target.ID, dto.UpdateUserRequest{Email: &email})
+			return err
+		},
+		"delete": func(users *fakeUserRepo, actor dto.Actor, target *entity.User) error {
+			return NewDeleteUserUseCase(users, grantRoles(), &fakeAuditLogger{}).Execute(context.Background(), actor, target.ID)
+		},
+		"force password reset": func(users *fakeUserRepo, actor dto.Actor, target *entity.User) error {
+			forgot := NewForgotPasswordUseCase(users, &fakeVerificationRepo{}, &fakeOpaqueTokens{}, newFakeEmailSender(), time.Hour, "https://example.com/reset")
+			return NewForcePasswordResetUseCase(users, grantRoles(), newFakeRefreshTokenRepo(), forgot, &fakeAuditLogger{}).
+				Execute(context.Background(), actor, target.ID)
+		},
+	}
+
+	tests := []struct {
+		name    string
+		actor   dto.Actor
+		target  entity.Role
+		wantErr error
+	}{
+		{name: "user manager on a moderator", actor: roleManager(), target: entity.RoleModerator},
+		{name: "user manager on an admin", actor: roleManager(), target: entity.RoleAdmin, wantErr: apperrors.ErrForbidden},
+		{name: "admin on an admin", actor: dto.Actor{Permissions: entity.AllPermissions}, target: entity.RoleAdmin},
+	}
+
+	for _, tt := range tests {
+		for action, execute := range actions {
+			t.Run(tt.name+"/"+action, func(t *testing.T) {
+				target := entity.NewUser("target@example.com", "hashed:pw")
+				target.SetRoles([]entity.Role{tt.target})
+				users := newFakeUserRepo(target)
+
+				err := execute(users, tt.actor, target)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantErr == nil {
+					return
+				}
+
+				if _, err := users.FindByID(context.Background(), target.ID); err != nil {
+					t.Errorf("refused action deleted the target: %v", err)
+				}
+				if target.Email != "target@example.com" || !target.HasPassword() {
+					t.Errorf("refused action changed the target: email %s, has password %v", target.Email, target.HasPassword())
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+)
+
+// parsePermissions validates and de-duplicates permission names; a role may grant none
+func parsePermissions(names []string) ([]entity.Permission, error) {
+	permissions := make([]entity.Permission, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !entity.IsValidPermission(name) {
+			return nil, apperrors.ErrInvalidInput
+		}
+		if !seen[name] {
+			seen[name] = true
+			permissions = append(permissions, entity.Permission(name))
+		}
+	}
+
+	return permissions, nil
+}
+
+// parseParentRole resolves the parent of role. An empty name means no parent; otherwise the parent
+// must exist and must not inherit from role, which would make the role inherit from itself.
+func parseParentRole(ctx context.Context, roleRepo repository.RoleRepository, role entity.Role, name string) (*entity.Role, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	parent := entity.Role(name)
+	if parent == role {
+		return nil, apperrors.ErrInvalidParentRole
+	}
+
+	definitions, err := roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := entity.NewRoleSet(definitions)
+	if _, ok := roles[parent]; !ok {
+		return nil, apperrors.ErrInvalidParentRole
+	}
+	if roles.Inherits(parent, role) {
+		return nil, apperrors.ErrInvalidParentRole
+	}
+
+	return &parent, nil
+}
+
+// rolePermissions returns the permissions the roles grant, including inherited ones
+func rolePermissions(ctx context.Context, roleRepo repository.RoleRepository, roles []entity.Role) ([]entity.Permission, error) {
+	definitions, err := roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewRoleSet(definitions).PermissionsOf(roles), nil
+}
+
+// definitionPermissions returns the permissions role would grant once saved, including
+// those it inherits from its parent
+func definitionPermissions(ctx context.Context, roleRepo repository.RoleRepository, role *entity.RoleDefinition) ([]entity.Permission, error) {
+	definitions, err := roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := entity.NewRoleSet(definitions)
+	roles[role.Name] = role
+	return roles.PermissionsOf([]entity.Role{role.Name}), nil
+}
+
+// authorizeGrant rejects granting permissions the actor does not hold, so that roles:assign
+// and roles:write cannot be used to escalate to a more privileged role
+func authorizeGrant(actor dto.Actor, permissions []entity.Permission) error {
+	for _, permission := range permissions {
+		if !entity.HasPermission(actor.Permissions, permission) {
+			return apperrors.ErrForbidden
+		}
+	}
+	return nil
+}
+
+// authorizeTarget rejects actions on a user holding permissions the actor does not hold,
+// so that a moderator cannot ban an admin, nor a user manager take over an admin's account
+func authorizeTarget(ctx context.Context, roleRepo repository.RoleRepository, actor dto.Actor, target *entity.User) error {
+	permissions, err := rolePermissions(ctx, roleRepo, target.Roles)
+	if err != nil {
+		return err
+	}
+	return authorizeGrant(actor, permissions)
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+)
+
+func TestParseRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []entity.Role
+		wantErr error
+	}{
+		{name: "defined roles", names: []string{"user", "auditor"}, want: []entity.Role{entity.RoleUser, roleAuditor}},
+		{name: "duplicates", names: []string{"admin", "user", "admin"}, want: []entity.Role{entity.RoleAdmin, entity.RoleUser}},
+		{name: "no roles", names: nil, wantErr: apperrors.ErrInvalidInput},
+		{name: "empty list", names: []string{}, wantErr: apperrors.ErrInvalidInput},
+		{name: "undefined role", names: []string{"user", "superuser"}, wantErr: apperrors.ErrRoleNotFound},
+		{name: "wrong case", names: []string{"Admin"}, wantErr: apperrors.ErrRoleNotFound},
+		{name: "surrounding spaces", names: []string{" admin"}, wantErr: apperrors.ErrRoleNotFound},
+		{name: "empty name", names: []string{""}, wantErr: apperrors.ErrRoleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRoles(context.Background(), grantRoles(), tt.names)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseRoles(%q) error = %v, want %v", tt.names, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRoles(%q) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []entity.Permission
+		wantErr error
+	}{
+		{name: "none", names: nil, want: []entity.Permission{}},
+		{name: "known", names: []string{"users:read", "audit:read"}, want: []entity.Permission{entity.PermissionUsersRead, entity.PermissionAuditRead}},
+		{name: "duplicates", names: []string{"users:read", "users:read"}, want: []entity.Permission{entity.PermissionUsersRead}},
+		{name: "unknown", names: []string{"users:read", "users:delete"}, wantErr: apperrors.ErrInvalidInput},
+		{name: "wildcard", names: []string{"*"}, wantErr: apperrors.ErrInvalidInput},
+		{name: "wrong case", names: []string{"Users:Read"}, wantErr: apperrors.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePermissions(tt.names)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parsePermissions(%q) error = %v, want %v", tt.names, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePermissions(%q) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParentRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    entity.Role
+		parent  string
+		want    entity.Role
+		wantErr error
+	}{
+		{name: "no parent", role: roleSupport, parent: ""},
+		{name: "defined parent", role: roleSupport, parent: "moderator", want: entity.RoleModerator},
+		{name: "new role", role: "helpdesk", parent: "auditor", want: roleAuditor},
+		{name: "itself", role: roleSupport, parent: "support", wantErr: apperrors.ErrInvalidParentRole},
+		{name: "undefined parent", role: roleSupport, parent: "ghost", wantErr: apperrors.ErrInvalidParentRole},
+		{name: "descendant", role: entity.RoleModerator, parent: "auditor", wantErr: apperrors.ErrInvalidParentRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseParentRole(context.Background(), grantRoles(), tt.role, tt.parent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseParentRole(%q, %q) error = %v, want %v", tt.role, tt.parent, err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == "") || (got != nil && *got != tt.want) {
+				t.Errorf("parseParentRole(%q, %q) = %v, want %q", tt.role, tt.parent, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// SetUserActiveUseCase activates or deactivates a user account (admin only)
+type SetUserActiveUseCase struct {
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditLogger      service.AuditLogger
+}
+
+// NewSetUserActiveUseCase creates a new set user active use case
+func NewSetUserActiveUseCase(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	auditLogger service.AuditLogger,
+) *SetUserActiveUseCase {
+	return &SetUserActiveUseCase{
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditLogger:      auditLogger,
+	}
+}
+
+// Execute executes the set user active use case
+func (uc *SetUserActiveUseCase) Execute(ctx context.Context, actor dto.Actor, userID uuid.UUID, active bool) (*entity.User, error) {
+	// Admins cannot lock themselves out
+	if !active && actor.UserID == userID {
+		return nil, apperrors.ErrForbidden
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeTarget(ctx, uc.roleRepo, actor, user); err != nil {
+		return nil, err
+	}
+
+	if user.IsActive == active {
+		return user, nil
+	}
+
+	eventType := entity.AuditEventUserActivated
+	if active {
+		user.Activate()
+	} else {
+		eventType = entity.AuditEventUserDeactivated
+		user.Deactivate()
+	}
+
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// A deactivated user must not be able to refresh existing sessions
+	if !active {
+		if err := uc.refreshTokenRepo.RevokeByUserID(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, eventType, user.ID, nil)
+
+	return user, nil
+}
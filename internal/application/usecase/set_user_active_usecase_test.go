@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestSetUserActiveUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		self        bool
+		active      bool
+		wasActive   bool
+		wantErr     error
+		wantActive  bool
+		wantRevoked bool
+		wantAudit   []entity.AuditEventType
+	}{
+		{name: "deactivate", wasActive: true, wantRevoked: true, wantAudit: []entity.AuditEventType{entity.AuditEventUserDeactivated}},
+		{name: "activate", active: true, wantActive: true, wantAudit: []entity.AuditEventType{entity.AuditEventUserActivated}},
+		{name: "already active", active: true, wasActive: true, wantActive: true},
+		{name: "deactivate self", self: true, wasActive: true, wantErr: apperrors.ErrForbidden, wantActive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.NewUser("target@example.com", "hashed:pw")
+			if !tt.wasActive {
+				user.Deactivate()
+			}
+			tokens := newFakeRefreshTokenRepo()
+			if err := tokens.Create(context.Background(), entity.NewRefreshToken(user.ID, "hash:session", time.Now().Add(time.Hour), uuid.New())); err != nil {
+				t.Fatal(err)
+			}
+			actor := roleManager()
+			if tt.self {
+				actor.UserID = user.ID
+			}
+			audit := &fakeAuditLogger{}
+			uc := NewSetUserActiveUseCase(newFakeUserRepo(user), grantRoles(), tokens, audit)
+
+			_, err := uc.Execute(context.Background(), actor, user.ID, tt.active)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if user.IsActive != tt.wantActive {
+				t.Errorf("IsActive = %v, want %v", user.IsActive, tt.wantActive)
+			}
+			sessions, _ := tokens.FindByUserID(context.Background(), user.ID)
+			if sessions[0].IsRevoked != tt.wantRevoked {
+				t.Errorf("sessions revoked = %v, want %v", sessions[0].IsRevoked, tt.wantRevoked)
+			}
+			if got := audit.types(); len(got) != len(tt.wantAudit) || (len(got) == 1 && got[0] != tt.wantAudit[0]) {
+				t.Errorf("audit events = %v, want %v", got, tt.wantAudit)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// UnlockAccountUseCase lifts a failed login lockout (admin only)
+type UnlockAccountUseCase struct {
+	userRepo      repository.UserRepository
+	roleRepo      repository.RoleRepository
+	loginThrottle *LoginThrottle
+	auditLogger   service.AuditLogger
+}
+
+// NewUnlockAccountUseCase creates a new unlock account use case
+func NewUnlockAccountUseCase(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	loginThrottle *LoginThrottle,
+	auditLogger service.AuditLogger,
+) *UnlockAccountUseCase {
+	return &UnlockAccountUseCase{
+		userRepo:      userRepo,
+		roleRepo:      roleRepo,
+		loginThrottle: loginThrottle,
+		auditLogger:   auditLogger,
+	}
+}
+
+// Execute executes the unlock account use case
+func (uc *UnlockAccountUseCase) Execute(ctx context.Context, actor dto.Actor, userID uuid.UUID) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeTarget(ctx, uc.roleRepo, actor, user); err != nil {
+		return err
+	}
+
+	if err := uc.loginThrottle.Reset(ctx, user.Email); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventUserUnlocked, user.ID, nil)
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// UpdateWebhookUseCase changes a webhook endpoint (admin only)
+type UpdateWebhookUseCase struct {
+	endpointRepo repository.WebhookEndpointRepository
+	opaqueTokens service.OpaqueTokenService
+	auditLogger  service.AuditLogger
+}
+
+// NewUpdateWebhookUseCase creates a new update webhook use case
+func NewUpdateWebhookUseCase(
+	endpointRepo repository.WebhookEndpointRepository,
+	opaqueTokens service.OpaqueTokenService,
+	auditLogger service.AuditLogger,
+) *UpdateWebhookUseCase {
+	return &UpdateWebhookUseCase{
+		endpointRepo: endpointRepo,
+		opaqueTokens: opaqueTokens,
+		auditLogger:  auditLogger,
+	}
+}
+
+// Execute executes the update webhook use case
+func (uc *UpdateWebhookUseCase) Execute(ctx context.Context, actor dto.Actor, webhookID uuid.UUID, req dto.UpdateWebhookRequest) (*entity.WebhookEndpoint, error) {
+	endpoint, err := uc.endpointRepo.FindByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{"webhook_id": endpoint.ID.String()}
+
+	if req.URL != nil {
+		webhookURL := strings.TrimSpace(*req.URL)
+		if err := validateWebhookURL(webhookURL); err != nil {
+			return nil, err
+		}
+		changes["url"] = map[string]string{"from": endpoint.URL, "to": webhookURL}
+		endpoint.URL = webhookURL
+	}
+
+	if req.Description != nil {
+		endpoint.Description = strings.TrimSpace(*req.Description)
+		changes["description"] = endpoint.Description
+	}
+
+	if req.EventTypes != nil {
+		eventTypes, err := parseWebhookEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.EventTypes = eventTypes
+		changes["event_types"] = req.EventTypes
+	}
+
+	if req.IsActive != nil {
+		endpoint.IsActive = *req.IsActive
+		changes["is_active"] = endpoint.IsActive
+	}
+
+	if req.RotateSecret {
+		secret, err := generateWebhookSecret(uc.opaqueTokens)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Secret = secret
+		changes["secret_rotated"] = true
+	}
+
+	endpoint.UpdatedAt = time.Now()
+	if err := uc.endpointRepo.Update(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.auditLogger, actor, entity.AuditEventWebhookUpdated, uuid.Nil, changes)
+
+	return endpoint, nil
+}
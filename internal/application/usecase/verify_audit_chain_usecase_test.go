@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// fakeAuditEventRepo serves a fixed audit trail ordered by sequence
+type fakeAuditEventRepo struct {
+	events []*entity.AuditEvent
+}
+
+func (r *fakeAuditEventRepo) Search(ctx context.Context, query repository.AuditEventQuery) ([]*entity.AuditEvent, error) {
+	return nil, nil
+}
+
+func (r *fakeAuditEventRepo) ListAfterSequence(ctx context.Context, afterSequence int64, limit int) ([]*entity.AuditEvent, error) {
+	var events []*entity.AuditEvent
+	for _, event := range r.events {
+		if event.Sequence > afterSequence && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// auditChain builds n chained events after legacy unhashed events
+func auditChain(legacy, n int) []*entity.AuditEvent {
+	var events []*entity.AuditEvent
+	for i := 0; i < legacy; i++ {
+		event := entity.NewAuditEvent(entity.AuditEventLoginSucceeded, nil, nil, nil)
+		event.Sequence = int64(len(events) + 1)
+		events = append(events, event)
+	}
+
+	prevHash := ""
+	for i := 0; i < n; i++ {
+		subjectID := uuid.New()
+		event := entity.NewAuditEvent(entity.AuditEventLoginSucceeded, nil, &subjectID, map[string]interface{}{"i": i})
+		event.Sequence = int64(len(events) + 1)
+		event.PrevHash = prevHash
+		event.Hash = event.ComputeHash()
+		prevHash = event.Hash
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestVerifyAuditChainUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		events      []*entity.AuditEvent
+		tamper      func(events []*entity.AuditEvent) []*entity.AuditEvent
+		wantChecked int
+		wantBrokeAt int64
+	}{
+		{name: "empty trail", wantChecked: 0},
+		{name: "intact chain", events: auditChain(0, 5), wantChecked: 5},
+		{name: "legacy events before the chain are skipped", events: auditChain(3, 4), wantChecked: 4},
+		{name: "chain spanning several batches", events: auditChain(0, auditVerifyBatchSize+5), wantChecked: auditVerifyBatchSize + 5},
+		{
+			name:   "modified event",
+			events: auditChain(0, 5),
+			tamper: func(events []*entity.AuditEvent) []*entity.AuditEvent {
+				events[2].Details["i"] = 42
+				return events
+			},
+			wantChecked: 3,
+			wantBrokeAt: 3,
+		},
+		{
+			name:   "modified event with recomputed hash",
+			events: auditChain(0, 5),
+			tamper: func(events []*entity.AuditEvent) []*entity.AuditEvent {
+				events[2].IPAddress = "198.51.100.1"
+				events[2].Hash = events[2].ComputeHash()
+				return events
+			},
+			wantChecked: 4,
+			wantBrokeAt: 4,
+		},
+		{
+			name:   "removed event",
+			events: auditChain(0, 5),
+			tamper: func(events []*entity.AuditEvent) []*entity.AuditEvent {
+				return append(events[:1], events[2:]...)
+			},
+			wantChecked: 2,
+			wantBrokeAt: 3,
+		},
+		{
+			name:   "hash removed to pass as legacy",
+			events: auditChain(0, 5),
+			tamper: func(events []*entity.AuditEvent) []*entity.AuditEvent {
+				events[3].Hash = ""
+				return events
+			},
+			wantChecked: 4,
+			wantBrokeAt: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := tt.events
+			if tt.tamper != nil {
+				events = tt.tamper(events)
+			}
+			uc := NewVerifyAuditChainUseCase(&fakeAuditEventRepo{events: events})
+
+			report, err := uc.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if report.Checked != tt.wantChecked {
+				t.Errorf("Checked = %d, want %d", report.Checked, tt.wantChecked)
+			}
+
+			if tt.wantBrokeAt == 0 {
+				if !report.Valid {
+					t.Errorf("report invalid at %d: %s", *report.BrokenAtSequence, report.Reason)
+				}
+				return
+			}
+			if report.Valid || report.BrokenAtSequence == nil || *report.BrokenAtSequence != tt.wantBrokeAt {
+				t.Errorf("report = %+v, want broken at %d", report, tt.wantBrokeAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"net/url"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+)
+
+// webhookSecretPrefix marks webhook signing secrets so they are recognisable when leaked
+const webhookSecretPrefix = "whsec_"
+
+// validateWebhookURL accepts absolute http and https URLs
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apperrors.ErrInvalidInput
+	}
+	return nil
+}
+
+// parseWebhookEventTypes validates and de-duplicates event types; at least one is required
+func parseWebhookEventTypes(names []string) ([]entity.WebhookEventType, error) {
+	if len(names) == 0 {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	eventTypes := make([]entity.WebhookEventType, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !entity.IsValidWebhookEventType(name) {
+			return nil, apperrors.ErrInvalidInput
+		}
+		if !seen[name] {
+			seen[name] = true
+			eventTypes = append(eventTypes, entity.WebhookEventType(name))
+		}
+	}
+
+	return eventTypes, nil
+}
+
+// generateWebhookSecret returns a new signing secret
+func generateWebhookSecret(opaqueTokens service.OpaqueTokenService) (string, error) {
+	token, err := opaqueTokens.Generate()
+	if err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + token, nil
+}
@@ -0,0 +1,27 @@
+package entity
+
+// Authentication context class references (acr claim), weakest first
+const (
+	// ACRBasic is a sign in with a password
+	ACRBasic = "urn:auth-go:acr:basic"
+	// ACRElevated is a re-authentication confirming the user before a high-risk operation
+	ACRElevated = "urn:auth-go:acr:elevated"
+)
+
+// Authentication method references (amr claim, RFC 8176)
+const (
+	AMRPassword = "pwd"
+)
+
+// acrLevels ranks the authentication context classes by strength
+var acrLevels = map[string]int{
+	ACRBasic:    1,
+	ACRElevated: 2,
+}
+
+// ACRSatisfies reports whether an authentication of class acr is at least as strong as required.
+// Unknown classes satisfy nothing.
+func ACRSatisfies(acr, required string) bool {
+	level, ok := acrLevels[acr]
+	return ok && level >= acrLevels[required]
+}
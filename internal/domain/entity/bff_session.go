@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BFFSession is a browser session of the backend-for-frontend. The browser holds only an
+// opaque cookie; the tokens of the underlying session are kept on the server.
+type BFFSession struct {
+	ID uuid.UUID
+	// TokenHash is the digest of the cookie value
+	TokenHash string
+	UserID    uuid.UUID
+	// SessionID is the underlying session (refresh token family)
+	SessionID            uuid.UUID
+	AccessToken          string
+	AccessTokenExpiresAt time.Time
+	RefreshToken         string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	// ExpiresAt follows the expiry of the refresh token
+	ExpiresAt time.Time
+}
+
+// NewBFFSession creates a new BFF session holding the tokens of a session
+func NewBFFSession(tokenHash string, userID, sessionID uuid.UUID) *BFFSession {
+	now := time.Now()
+	return &BFFSession{
+		ID:        uuid.New(),
+		TokenHash: tokenHash,
+		UserID:    userID,
+		SessionID: sessionID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SetTokens stores a new access and refresh token pair
+func (s *BFFSession) SetTokens(accessToken string, accessTokenExpiresAt time.Time, refreshToken string, expiresAt time.Time) {
+	s.AccessToken = accessToken
+	s.AccessTokenExpiresAt = accessTokenExpiresAt
+	s.RefreshToken = refreshToken
+	s.ExpiresAt = expiresAt
+	s.UpdatedAt = time.Now()
+}
+
+// AccessTokenExpiresWithin reports whether the access token expires in less than d,
+// so it should be refreshed before being sent upstream
+func (s *BFFSession) AccessTokenExpiresWithin(d time.Duration) bool {
+	return time.Until(s.AccessTokenExpiresAt) < d
+}
+
+// IsExpired checks if the session is expired
+func (s *BFFSession) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
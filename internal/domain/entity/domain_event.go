@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainEventType identifies a state change of an aggregate
+type DomainEventType string
+
+const (
+	EventUserCreated               DomainEventType = "user.created"
+	EventUserRegistered            DomainEventType = "user.registered"
+	EventUserEmailChanged          DomainEventType = "user.email_changed"
+	EventUserEmailVerified         DomainEventType = "user.email_verified"
+	EventUserPasswordChanged       DomainEventType = "user.password_changed"
+	EventUserRolesChanged          DomainEventType = "user.roles_changed"
+	EventUserActivated             DomainEventType = "user.activated"
+	EventUserDeactivated           DomainEventType = "user.deactivated"
+	EventRefreshTokenIssued        DomainEventType = "refresh_token.issued"
+	EventRefreshTokenRevoked       DomainEventType = "refresh_token.revoked"
+	EventRefreshTokenReuseDetected DomainEventType = "refresh_token.reuse_detected"
+)
+
+// Aggregate types that raise domain events
+const (
+	AggregateUser         = "user"
+	AggregateRefreshToken = "refresh_token"
+)
+
+// DomainEvent records a state change of an aggregate. Events are saved to the outbox
+// in the same transaction as the change and published afterwards, at least once.
+type DomainEvent struct {
+	ID            uuid.UUID
+	Type          DomainEventType
+	AggregateType string
+	AggregateID   uuid.UUID
+	Payload       map[string]interface{}
+	OccurredAt    time.Time
+}
+
+// events collects the domain events raised by an aggregate until it is saved
+type events struct {
+	pending []DomainEvent
+}
+
+func (e *events) raise(eventType DomainEventType, aggregateType string, aggregateID uuid.UUID, payload map[string]interface{}) {
+	e.pending = append(e.pending, DomainEvent{
+		ID:            uuid.New(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       payload,
+		OccurredAt:    time.Now().UTC(),
+	})
+}
+
+// PullEvents returns the events raised since the last call and forgets them.
+// Repositories call it when saving the aggregate.
+func (e *events) PullEvents() []DomainEvent {
+	pending := e.pending
+	e.pending = nil
+	return pending
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"math"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// earthRadiusKm is the mean radius of the earth
+const earthRadiusKm = 6371.0
+
+// minTravelDistanceKm ignores shorter jumps, which are within the accuracy of IP geolocation
+const minTravelDistanceKm = 100.0
+
+// GeoLocation is the approximate location of an IP address
+type GeoLocation struct {
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+// DistanceKm returns the great-circle distance to another location
+func (l GeoLocation) DistanceKm(other GeoLocation) float64 {
+	lat1, lat2 := l.Latitude*math.Pi/180, other.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - l.Longitude) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// KnownDevice is a device a user has signed in from before
+type KnownDevice struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	// Fingerprint is the digest of the device signature
+	Fingerprint string
+	UserAgent   string
+	IPAddress   string
+	// Location of the last sign in, if the IP address could be located
+	Location    *GeoLocation
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// NewKnownDevice creates a new known device of a user
+func NewKnownDevice(userID uuid.UUID, fingerprint, userAgent, ipAddress string, location *GeoLocation) *KnownDevice {
+	now := time.Now()
+	return &KnownDevice{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		UserAgent:   userAgent,
+		IPAddress:   ipAddress,
+		Location:    location,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+}
+
+// Seen records another sign in from the device
+func (d *KnownDevice) Seen(ipAddress string, location *GeoLocation) {
+	d.IPAddress = ipAddress
+	d.Location = location
+	d.LastSeenAt = time.Now()
+}
+
+// IsImpossibleTravel reports whether getting from the device's last sign in to a sign in at
+// location now would have required travelling faster than maxSpeedKmh
+func (d *KnownDevice) IsImpossibleTravel(location *GeoLocation, maxSpeedKmh float64) bool {
+	if d.Location == nil || location == nil || maxSpeedKmh <= 0 {
+		return false
+	}
+
+	distance := d.Location.DistanceKm(*location)
+	if distance < minTravelDistanceKm {
+		return false
+	}
+
+	hours := time.Since(d.LastSeenAt).Hours()
+	return hours <= 0 || distance/hours > maxSpeedKmh
+}
+
+// DeviceSignature combines what identifies a device: its user agent and the ID of its
+// device cookie. Only cookieless clients are told apart by the network they are on
+// (the /24 of an IPv4 or the /48 of an IPv6 address), so a device with a cookie stays
+// known when it moves between networks.
+func DeviceSignature(userAgent, ipAddress, deviceID string) string {
+	if deviceID != "" {
+		return strings.Join([]string{strings.TrimSpace(userAgent), "", deviceID}, "\n")
+	}
+
+	network := ipAddress
+	if addr, err := netip.ParseAddr(ipAddress); err == nil {
+		bits := 48
+		if addr.Unmap().Is4() {
+			addr, bits = addr.Unmap(), 24
+		}
+		if prefix, err := addr.Prefix(bits); err == nil {
+			network = prefix.String()
+		}
+	}
+
+	return strings.Join([]string{strings.TrimSpace(userAgent), network, ""}, "\n")
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDeviceSignature(t *testing.T) {
+	const chrome = "Mozilla/5.0 Chrome/124.0"
+	tests := []struct {
+		name      string
+		a, b      [3]string // user agent, IP address, device ID
+		wantEqual bool
+	}{
+		{name: "cookie device changing networks", a: [3]string{chrome, "203.0.113.7", "dev-1"}, b: [3]string{chrome, "198.51.100.9", "dev-1"}, wantEqual: true},
+		{name: "cookie device changing IP family", a: [3]string{chrome, "203.0.113.7", "dev-1"}, b: [3]string{chrome, "2001:db8::1", "dev-1"}, wantEqual: true},
+		{name: "different cookies on one network", a: [3]string{chrome, "203.0.113.7", "dev-1"}, b: [3]string{chrome, "203.0.113.7", "dev-2"}},
+		{name: "cookie device with another user agent", a: [3]string{chrome, "203.0.113.7", "dev-1"}, b: [3]string{"curl/8.0", "203.0.113.7", "dev-1"}},
+		{name: "cookieless within a /24", a: [3]string{chrome, "203.0.113.7", ""}, b: [3]string{chrome, "203.0.113.200", ""}, wantEqual: true},
+		{name: "cookieless across /24s", a: [3]string{chrome, "203.0.113.7", ""}, b: [3]string{chrome, "203.0.114.7", ""}},
+		{name: "cookieless IPv4-mapped IPv6", a: [3]string{chrome, "203.0.113.7", ""}, b: [3]string{chrome, "::ffff:203.0.113.8", ""}, wantEqual: true},
+		{name: "cookieless within a /48", a: [3]string{chrome, "2001:db8:1::1", ""}, b: [3]string{chrome, "2001:db8:1:ffff::2", ""}, wantEqual: true},
+		{name: "cookieless across /48s", a: [3]string{chrome, "2001:db8:1::1", ""}, b: [3]string{chrome, "2001:db8:2::1", ""}},
+		{name: "cookie and cookieless", a: [3]string{chrome, "203.0.113.7", "dev-1"}, b: [3]string{chrome, "203.0.113.7", ""}},
+		{name: "user agent whitespace", a: [3]string{chrome + " ", "203.0.113.7", ""}, b: [3]string{chrome, "203.0.113.7", ""}, wantEqual: true},
+		{name: "unparsable address", a: [3]string{chrome, "unknown", ""}, b: [3]string{chrome, "unknown", ""}, wantEqual: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := DeviceSignature(tt.a[0], tt.a[1], tt.a[2])
+			b := DeviceSignature(tt.b[0], tt.b[1], tt.b[2])
+			if (a == b) != tt.wantEqual {
+				t.Errorf("signatures %q and %q: equal = %v, want %v", a, b, a == b, tt.wantEqual)
+			}
+		})
+	}
+}
+
+var (
+	paris      = GeoLocation{Country: "FR", Latitude: 48.8566, Longitude: 2.3522}
+	london     = GeoLocation{Country: "GB", Latitude: 51.5074, Longitude: -0.1278}
+	sydney     = GeoLocation{Country: "AU", Latitude: -33.8688, Longitude: 151.2093}
+	versailles = GeoLocation{Country: "FR", Latitude: 48.8049, Longitude: 2.1204}
+)
+
+func TestGeoLocation_DistanceKm(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b GeoLocation
+		want float64
+	}{
+		{name: "same place", a: paris, b: paris, want: 0},
+		{name: "paris to london", a: paris, b: london, want: 344},
+		{name: "paris to sydney", a: paris, b: sydney, want: 16960},
+		{name: "antipodes", a: GeoLocation{Latitude: 0, Longitude: 0}, b: GeoLocation{Latitude: 0, Longitude: 180}, want: math.Pi * earthRadiusKm},
+		{name: "across the date line", a: GeoLocation{Latitude: 0, Longitude: 179.5}, b: GeoLocation{Latitude: 0, Longitude: -179.5}, want: 111},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.a.DistanceKm(tt.b)
+			if math.Abs(got-tt.want) > 0.01*tt.want+1 {
+				t.Errorf("DistanceKm() = %.0f, want about %.0f", got, tt.want)
+			}
+			if back := tt.b.DistanceKm(tt.a); math.Abs(back-got) > 1e-6 {
+				t.Errorf("DistanceKm() is not symmetric: %f and %f", got, back)
+			}
+		})
+	}
+}
+
+func TestKnownDevice_IsImpossibleTravel(t *testing.T) {
+	tests := []struct {
+		name     string
+		last     *GeoLocation
+		ago      time.Duration
+		next     *GeoLocation
+		maxSpeed float64
+		want     bool
+	}{
+		{name: "paris to london by train", last: &paris, ago: 3 * time.Hour, next: &london, maxSpeed: 900},
+		{name: "paris to london in ten minutes", last: &paris, ago: 10 * time.Minute, next: &london, maxSpeed: 900, want: true},
+		{name: "paris to sydney overnight", last: &paris, ago: 8 * time.Hour, next: &sydney, maxSpeed: 900, want: true},
+		{name: "paris to sydney after a day", last: &paris, ago: 24 * time.Hour, next: &sydney, maxSpeed: 900},
+		{name: "short jumps are geolocation noise", last: &paris, ago: time.Second, next: &versailles, maxSpeed: 900},
+		{name: "unknown previous location", ago: time.Minute, next: &sydney, maxSpeed: 900},
+		{name: "unknown new location", last: &paris, ago: time.Minute, maxSpeed: 900},
+		{name: "check disabled", last: &paris, ago: time.Minute, next: &sydney},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := NewKnownDevice(uuid.New(), "fp", "ua", "203.0.113.7", tt.last)
+			device.LastSeenAt = time.Now().Add(-tt.ago)
+			if got := device.IsImpossibleTravel(tt.next, tt.maxSpeed); got != tt.want {
+				t.Errorf("IsImpossibleTravel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// LoginAttempt tracks consecutive failed logins for a throttling key (an account or an IP address)
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt *time.Time
+	LockedUntil   *time.Time
+}
+
+// IsLocked checks if attempts for this key are currently blocked
+func (a *LoginAttempt) IsLocked() bool {
+	return a.LockedUntil != nil && time.Now().Before(*a.LockedUntil)
+}
+
+// RetryAfter returns how long attempts for this key remain blocked
+func (a *LoginAttempt) RetryAfter() time.Duration {
+	if !a.IsLocked() {
+		return 0
+	}
+	return time.Until(*a.LockedUntil)
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHistoryEntry represents a password hash previously used by a user
+type PasswordHistoryEntry struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	PasswordHash string
+	CreatedAt    time.Time
+}
+
+// NewPasswordHistoryEntry creates a new password history entry
+func NewPasswordHistoryEntry(userID uuid.UUID, passwordHash string) *PasswordHistoryEntry {
+	return &PasswordHistoryEntry{
+		ID:           uuid.New(),
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	}
+}
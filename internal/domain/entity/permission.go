@@ -0,0 +1,53 @@
+package entity
+
+// Permission is a named capability, written resource:action (e.g. "users:write")
+type Permission string
+
+const (
+	// PermissionUsersRead allows listing and viewing user accounts
+	PermissionUsersRead Permission = "users:read"
+	// PermissionUsersWrite allows creating, updating, deleting and resetting user accounts
+	PermissionUsersWrite Permission = "users:write"
+	// PermissionUsersBan allows activating, deactivating and unlocking users and revoking their sessions
+	PermissionUsersBan Permission = "users:ban"
+	// PermissionRolesRead allows viewing role definitions
+	PermissionRolesRead Permission = "roles:read"
+	// PermissionRolesWrite allows creating, changing and deleting role definitions
+	PermissionRolesWrite Permission = "roles:write"
+	// PermissionRolesAssign allows granting and revoking roles
+	PermissionRolesAssign Permission = "roles:assign"
+	// PermissionAuditRead allows reading and exporting the audit trail
+	PermissionAuditRead Permission = "audit:read"
+	// PermissionWebhooksRead allows viewing webhooks and their deliveries
+	PermissionWebhooksRead Permission = "webhooks:read"
+	// PermissionWebhooksWrite allows managing webhooks and redelivering events
+	PermissionWebhooksWrite Permission = "webhooks:write"
+)
+
+// AllPermissions lists every permission, the only ones roles can grant
+var AllPermissions = []Permission{
+	PermissionUsersRead,
+	PermissionUsersWrite,
+	PermissionUsersBan,
+	PermissionRolesRead,
+	PermissionRolesWrite,
+	PermissionRolesAssign,
+	PermissionAuditRead,
+	PermissionWebhooksRead,
+	PermissionWebhooksWrite,
+}
+
+// IsValidPermission reports whether s names a known permission
+func IsValidPermission(s string) bool {
+	return HasPermission(AllPermissions, Permission(s))
+}
+
+// HasPermission reports whether permissions contains the permission
+func HasPermission(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "testing"
+
+func TestIsValidPermission(t *testing.T) {
+	for _, p := range AllPermissions {
+		if !IsValidPermission(string(p)) {
+			t.Errorf("IsValidPermission(%q) = false, want true", p)
+		}
+	}
+
+	for _, s := range []string{"", "users", "users:", "Users:Read", " users:read", "users:read ", "users:*", "*", "admin"} {
+		if IsValidPermission(s) {
+			t.Errorf("IsValidPermission(%q) = true, want false", s)
+		}
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	held := []Permission{PermissionUsersRead, PermissionUsersBan}
+
+	tests := []struct {
+		name        string
+		permissions []Permission
+		permission  Permission
+		want        bool
+	}{
+		{name: "held", permissions: held, permission: PermissionUsersBan, want: true},
+		{name: "not held", permissions: held, permission: PermissionUsersWrite},
+		{name: "no permissions", permissions: nil, permission: PermissionUsersRead},
+		{name: "prefix of a held permission", permissions: held, permission: "users:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasPermission(tt.permissions, tt.permission); got != tt.want {
+				t.Errorf("HasPermission(%v, %q) = %v, want %v", tt.permissions, tt.permission, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,32 +6,39 @@ import (
 	"github.com/google/uuid"
 )
 
-// RefreshToken represents a refresh token for token rotation
+// RefreshToken represents a refresh token for token rotation.
+// Only the hash of the token is stored; the raw value is known to the client only.
 type RefreshToken struct {
 	ID        uuid.UUID
 	UserID    uuid.UUID
-	Token     string
+	TokenHash string
 	ExpiresAt time.Time
 	CreatedAt time.Time
 	IsRevoked bool
 	RevokedAt *time.Time
 	// Token family for rotation detection
 	TokenFamily uuid.UUID
-	// Previous token in the rotation chain (for detecting reuse)
-	ParentToken *string
+	// ID of the previous token in the rotation chain (for detecting reuse)
+	ParentID *uuid.UUID
+
+	events
 }
 
 // NewRefreshToken creates a new refresh token
-func NewRefreshToken(userID uuid.UUID, token string, expiresAt time.Time, tokenFamily uuid.UUID) *RefreshToken {
-	return &RefreshToken{
+func NewRefreshToken(userID uuid.UUID, tokenHash string, expiresAt time.Time, tokenFamily uuid.UUID) *RefreshToken {
+	rt := &RefreshToken{
 		ID:          uuid.New(),
 		UserID:      userID,
-		Token:       token,
+		TokenHash:   tokenHash,
 		ExpiresAt:   expiresAt,
 		CreatedAt:   time.Now(),
 		IsRevoked:   false,
 		TokenFamily: tokenFamily,
 	}
+	rt.raise(EventRefreshTokenIssued, map[string]interface{}{
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	})
+	return rt
 }
 
 // IsExpired checks if the token is expired
@@ -46,7 +53,31 @@ func (rt *RefreshToken) IsValid() bool {
 
 // Revoke revokes the refresh token
 func (rt *RefreshToken) Revoke() {
+	if !rt.IsRevoked {
+		rt.raise(EventRefreshTokenRevoked, nil)
+	}
 	rt.IsRevoked = true
 	now := time.Now()
 	rt.RevokedAt = &now
 }
+
+// RevokedWithin reports whether the token was revoked less than d ago
+func (rt *RefreshToken) RevokedWithin(d time.Duration) bool {
+	return rt.IsRevoked && rt.RevokedAt != nil && time.Since(*rt.RevokedAt) < d
+}
+
+// ReportReuse records that the already rotated token was presented again,
+// which means it was stolen from either the user or the attacker
+func (rt *RefreshToken) ReportReuse() {
+	rt.raise(EventRefreshTokenReuseDetected, nil)
+}
+
+// raise records a domain event of the token; payloads never contain the token itself
+func (rt *RefreshToken) raise(eventType DomainEventType, payload map[string]interface{}) {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	payload["user_id"] = rt.UserID.String()
+	payload["session_id"] = rt.TokenFamily.String()
+	rt.events.raise(eventType, AggregateRefreshToken, rt.ID, payload)
+}
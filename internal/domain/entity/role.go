@@ -1,38 +1,129 @@
 package entity
 
-// Role represents user roles in the system (RBAC)
-type Role int
+import (
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Role names a role of the system (RBAC). Roles are defined in the database; a role
+// grants its permissions and those of its parent roles.
+type Role string
 
+// Built-in roles, which always exist
 const (
-	RoleUser Role = iota + 1
-	RoleModerator
-	RoleAdmin
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
 )
 
 // String returns string representation of role
 func (r Role) String() string {
+	return string(r)
+}
+
+// IsBuiltIn reports whether the role is one of the built-in roles, which cannot be deleted
+func (r Role) IsBuiltIn() bool {
 	switch r {
-	case RoleUser:
-		return "user"
-	case RoleModerator:
-		return "moderator"
-	case RoleAdmin:
-		return "admin"
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
 	default:
-		return "unknown"
+		return false
 	}
 }
 
-// ParseRole parses string to Role
-func ParseRole(s string) Role {
-	switch s {
-	case "user":
-		return RoleUser
-	case "moderator":
-		return RoleModerator
-	case "admin":
-		return RoleAdmin
-	default:
-		return RoleUser
+// roleNamePattern is the format of new role names
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+
+// IsValidRoleName reports whether name is well-formed for a new role: lowercase letters,
+// digits, '-' and '_', starting with a letter, at most 50 characters
+func IsValidRoleName(name string) bool {
+	return roleNamePattern.MatchString(name)
+}
+
+// RoleDefinition is a role stored in the database
+type RoleDefinition struct {
+	Name        Role
+	Description string
+	Permissions []Permission
+	// Parent is the role whose permissions this role inherits, if any
+	Parent    *Role
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewRoleDefinition creates a new role definition
+func NewRoleDefinition(name Role, description string, permissions []Permission, parent *Role) *RoleDefinition {
+	now := time.Now()
+	return &RoleDefinition{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+		Parent:      parent,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// RoleSet holds the role definitions by name and resolves inherited permissions
+type RoleSet map[Role]*RoleDefinition
+
+// NewRoleSet creates a role set from role definitions
+func NewRoleSet(definitions []*RoleDefinition) RoleSet {
+	set := make(RoleSet, len(definitions))
+	for _, definition := range definitions {
+		set[definition.Name] = definition
+	}
+	return set
+}
+
+// PermissionsOf returns the union of the permissions of the roles and the roles they
+// inherit from, sorted by name. Unknown roles grant nothing.
+func (s RoleSet) PermissionsOf(roles []Role) []Permission {
+	seen := make(map[Permission]bool)
+	var permissions []Permission
+	for _, role := range roles {
+		for _, definition := range s.lineage(role) {
+			for _, p := range definition.Permissions {
+				if !seen[p] {
+					seen[p] = true
+					permissions = append(permissions, p)
+				}
+			}
+		}
+	}
+
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions
+}
+
+// Inherits reports whether role inherits from ancestor, directly or through other roles
+func (s RoleSet) Inherits(role, ancestor Role) bool {
+	lineage := s.lineage(role)
+	for i := 1; i < len(lineage); i++ {
+		if lineage[i].Name == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// lineage returns the definition of a role followed by those of its ancestors,
+// stopping at unknown roles and cycles
+func (s RoleSet) lineage(role Role) []*RoleDefinition {
+	var lineage []*RoleDefinition
+	visited := make(map[Role]bool)
+	for !visited[role] {
+		definition, ok := s[role]
+		if !ok {
+			break
+		}
+		visited[role] = true
+		lineage = append(lineage, definition)
+		if definition.Parent == nil {
+			break
+		}
+		role = *definition.Parent
 	}
+	return lineage
 }
@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lineageRoles returns a role set where auditor inherits from moderator, which inherits
+// from user, and where loop-a and loop-b inherit from each other
+func lineageRoles() RoleSet {
+	parent := func(role Role) *Role { return &role }
+	return NewRoleSet([]*RoleDefinition{
+		NewRoleDefinition(RoleUser, "", nil, nil),
+		NewRoleDefinition(RoleModerator, "", []Permission{PermissionUsersRead, PermissionUsersBan}, parent(RoleUser)),
+		NewRoleDefinition("auditor", "", []Permission{PermissionAuditRead, PermissionUsersRead}, parent(RoleModerator)),
+		NewRoleDefinition("orphan", "", []Permission{PermissionWebhooksRead}, parent("deleted")),
+		NewRoleDefinition("loop-a", "", []Permission{PermissionRolesRead}, parent("loop-b")),
+		NewRoleDefinition("loop-b", "", []Permission{PermissionRolesWrite}, parent("loop-a")),
+	})
+}
+
+func TestRoleSet_PermissionsOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles []Role
+		want  []Permission
+	}{
+		{name: "no roles", roles: nil, want: nil},
+		{name: "role without permissions", roles: []Role{RoleUser}, want: nil},
+		{name: "own permissions", roles: []Role{RoleModerator}, want: []Permission{PermissionUsersBan, PermissionUsersRead}},
+		{
+			name:  "inherited permissions, de-duplicated and sorted",
+			roles: []Role{"auditor"},
+			want:  []Permission{PermissionAuditRead, PermissionUsersBan, PermissionUsersRead},
+		},
+		{
+			name:  "union of several roles",
+			roles: []Role{RoleModerator, "orphan"},
+			want:  []Permission{PermissionUsersBan, PermissionUsersRead, PermissionWebhooksRead},
+		},
+		{name: "unknown role grants nothing", roles: []Role{"ghost"}, want: nil},
+		{name: "unknown parent stops the lineage", roles: []Role{"orphan"}, want: []Permission{PermissionWebhooksRead}},
+		{name: "cycle", roles: []Role{"loop-a"}, want: []Permission{PermissionRolesRead, PermissionRolesWrite}},
+	}
+
+	roles := lineageRoles()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roles.PermissionsOf(tt.roles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PermissionsOf(%v) = %v, want %v", tt.roles, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoleSet_Inherits(t *testing.T) {
+	tests := []struct {
+		role, ancestor Role
+		want           bool
+	}{
+		{role: "auditor", ancestor: RoleModerator, want: true},
+		{role: "auditor", ancestor: RoleUser, want: true},
+		{role: RoleModerator, ancestor: "auditor", want: false},
+		{role: RoleUser, ancestor: RoleUser, want: false},
+		{role: "orphan", ancestor: "deleted", want: false},
+		{role: "ghost", ancestor: RoleUser, want: false},
+		{role: "loop-a", ancestor: "loop-b", want: true},
+		{role: "loop-a", ancestor: "loop-a", want: false},
+	}
+
+	roles := lineageRoles()
+	for _, tt := range tests {
+		if got := roles.Inherits(tt.role, tt.ancestor); got != tt.want {
+			t.Errorf("Inherits(%q, %q) = %v, want %v", tt.role, tt.ancestor, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidRoleName(t *testing.T) {
+	valid := []string{"a", "support", "help-desk", "tier_2", strings.Repeat("a", 50)}
+	for _, name := range valid {
+		if !IsValidRoleName(name) {
+			t.Errorf("IsValidRoleName(%q) = false, want true", name)
+		}
+	}
+
+	invalid := []string{"", "Support", "2nd", "-admin", "_admin", "help desk", "admin!", "rôle", strings.Repeat("a", 51)}
+	for _, name := range invalid {
+		if IsValidRoleName(name) {
+			t.Errorf("IsValidRoleName(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestRole_IsBuiltIn(t *testing.T) {
+	for _, role := range []Role{RoleUser, RoleModerator, RoleAdmin} {
+		if !role.IsBuiltIn() {
+			t.Errorf("%q.IsBuiltIn() = false, want true", role)
+		}
+	}
+	for _, role := range []Role{"auditor", "Admin", ""} {
+		if role.IsBuiltIn() {
+			t.Errorf("%q.IsBuiltIn() = true, want false", role)
+		}
+	}
+}
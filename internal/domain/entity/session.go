@@ -0,0 +1,217 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxDeviceNameLength caps client supplied device names
+const maxDeviceNameLength = 100
+
+// maxClientIDLength caps client supplied client IDs
+const maxClientIDLength = 100
+
+// SessionLifetime bounds how long a session can be kept alive by refreshing; zero means unlimited
+type SessionLifetime struct {
+	// MaxLifetime is measured from the original login
+	MaxLifetime time.Duration
+	// IdleTimeout is measured from the last use of the session
+	IdleTimeout time.Duration
+}
+
+// SessionLifetimePolicy holds the session lifetime of every client
+type SessionLifetimePolicy struct {
+	Default SessionLifetime
+	// Clients overrides the default for clients identified by their client ID
+	Clients map[string]SessionLifetime
+}
+
+// For returns the session lifetime of a client
+func (p SessionLifetimePolicy) For(clientID string) SessionLifetime {
+	if lifetime, ok := p.Clients[clientID]; ok {
+		return lifetime
+	}
+	return p.Default
+}
+
+// ParseSessionLifetimes parses a comma-separated list of CLIENT=MAX/IDLE entries,
+// e.g. "web=12h/30m,mobile=2160h/336h", where 0 means unlimited
+func ParseSessionLifetimes(spec string) (map[string]SessionLifetime, error) {
+	lifetimes := make(map[string]SessionLifetime)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		clientID, durations, ok := strings.Cut(part, "=")
+		maxSpec, idleSpec, ok2 := strings.Cut(durations, "/")
+		if !ok || !ok2 || strings.TrimSpace(clientID) == "" {
+			return nil, fmt.Errorf("invalid session lifetime %q, expected CLIENT=MAX/IDLE", part)
+		}
+
+		maxLifetime, err := time.ParseDuration(strings.TrimSpace(maxSpec))
+		if err != nil || maxLifetime < 0 {
+			return nil, fmt.Errorf("invalid session lifetime %q: max must be a duration", part)
+		}
+		idleTimeout, err := time.ParseDuration(strings.TrimSpace(idleSpec))
+		if err != nil || idleTimeout < 0 {
+			return nil, fmt.Errorf("invalid session lifetime %q: idle must be a duration", part)
+		}
+
+		lifetimes[strings.TrimSpace(clientID)] = SessionLifetime{MaxLifetime: maxLifetime, IdleTimeout: idleTimeout}
+	}
+
+	return lifetimes, nil
+}
+
+// SessionLimitPolicy caps the number of active sessions of a user; zero means unlimited
+type SessionLimitPolicy struct {
+	// Default applies to every user
+	Default int
+	// Roles caps the sessions of users holding a role; the smallest applicable limit wins
+	Roles map[Role]int
+	// EvictOldest signs out the oldest sessions to make room instead of rejecting the login
+	EvictOldest bool
+}
+
+// LimitFor returns the session limit of a user
+func (p SessionLimitPolicy) LimitFor(user *User) int {
+	limit := p.Default
+	for _, role := range user.Roles {
+		if n, ok := p.Roles[role]; ok && n > 0 && (limit == 0 || n < limit) {
+			limit = n
+		}
+	}
+	return limit
+}
+
+// ParseSessionLimits parses a comma-separated list of ROLE=LIMIT entries, e.g. "admin=2,moderator=5"
+func ParseSessionLimits(spec string) (map[Role]int, error) {
+	limits := make(map[Role]int)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, limitSpec, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid session limit %q, expected ROLE=LIMIT", part)
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(limitSpec))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid session limit %q: limit must be a positive integer", part)
+		}
+
+		limits[Role(name)] = limit
+	}
+
+	return limits, nil
+}
+
+// Session represents a signed-in device: one refresh token family from login until
+// its tokens are revoked or expire
+type Session struct {
+	// ID is the token family of the session's refresh tokens
+	ID     uuid.UUID
+	UserID uuid.UUID
+	// ClientID is the self-declared client application (X-Client-ID header) that signed in
+	ClientID string
+	// DeviceName is an optional, user supplied label (e.g. "Work laptop")
+	DeviceName *string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	// AuthTime is when the user last authenticated for the session
+	AuthTime time.Time
+	// ACR is the authentication context class of that authentication
+	ACR string
+	// AMR lists the authentication methods used (e.g. "pwd")
+	AMR []string
+}
+
+// NewSession creates a new session for a login
+func NewSession(userID uuid.UUID, clientID, deviceName, userAgent, ipAddress string) *Session {
+	if runes := []rune(clientID); len(runes) > maxClientIDLength {
+		clientID = string(runes[:maxClientIDLength])
+	}
+
+	now := time.Now()
+	s := &Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		ClientID:   clientID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		AuthTime:   now,
+	}
+	s.Rename(deviceName)
+	return s
+}
+
+// Rename sets the device name; a blank name clears it
+func (s *Session) Rename(deviceName string) {
+	deviceName = strings.TrimSpace(deviceName)
+	if deviceName == "" {
+		s.DeviceName = nil
+		return
+	}
+	if runes := []rune(deviceName); len(runes) > maxDeviceNameLength {
+		deviceName = string(runes[:maxDeviceNameLength])
+	}
+	s.DeviceName = &deviceName
+}
+
+// Authenticate records that the user just authenticated for the session with the given
+// context class and methods
+func (s *Session) Authenticate(acr string, amr []string) {
+	s.AuthTime = time.Now()
+	s.ACR = acr
+	s.AMR = amr
+}
+
+// Touch records a use of the session (a token refresh) from the given client
+func (s *Session) Touch(userAgent, ipAddress string) {
+	s.UserAgent = userAgent
+	s.IPAddress = ipAddress
+	s.LastUsedAt = time.Now()
+}
+
+// IsExpired reports whether the session outlived its maximum lifetime or was idle for too long
+func (s *Session) IsExpired(lifetime SessionLifetime) bool {
+	now := time.Now()
+	if lifetime.MaxLifetime > 0 && now.After(s.CreatedAt.Add(lifetime.MaxLifetime)) {
+		return true
+	}
+	return lifetime.IdleTimeout > 0 && now.After(s.LastUsedAt.Add(lifetime.IdleTimeout))
+}
+
+// RefreshTokenExpiry returns the expiry of a refresh token issued now for the session:
+// tokenTTL from now, but never past the end of the session's lifetime
+func (s *Session) RefreshTokenExpiry(lifetime SessionLifetime, tokenTTL time.Duration) time.Time {
+	now := time.Now()
+	expiresAt := now.Add(tokenTTL)
+	if lifetime.MaxLifetime > 0 {
+		if end := s.CreatedAt.Add(lifetime.MaxLifetime); end.Before(expiresAt) {
+			expiresAt = end
+		}
+	}
+	if lifetime.IdleTimeout > 0 {
+		if end := now.Add(lifetime.IdleTimeout); end.Before(expiresAt) {
+			expiresAt = end
+		}
+	}
+	return expiresAt
+}
@@ -0,0 +1,170 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseSessionLifetimes(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[string]SessionLifetime
+		wantErr bool
+	}{
+		{name: "empty", spec: "", want: map[string]SessionLifetime{}},
+		{
+			name: "several clients",
+			spec: "web=12h/30m, mobile=2160h/336h",
+			want: map[string]SessionLifetime{
+				"web":    {MaxLifetime: 12 * time.Hour, IdleTimeout: 30 * time.Minute},
+				"mobile": {MaxLifetime: 2160 * time.Hour, IdleTimeout: 336 * time.Hour},
+			},
+		},
+		{name: "zero means unlimited", spec: "cli=0/1h", want: map[string]SessionLifetime{"cli": {IdleTimeout: time.Hour}}},
+		{name: "blank entries and spaces", spec: " , web = 1h / 5m ,", want: map[string]SessionLifetime{"web": {MaxLifetime: time.Hour, IdleTimeout: 5 * time.Minute}}},
+		{name: "missing idle", spec: "web=12h", wantErr: true},
+		{name: "missing client", spec: "=12h/30m", wantErr: true},
+		{name: "missing separator", spec: "web", wantErr: true},
+		{name: "bad max", spec: "web=forever/30m", wantErr: true},
+		{name: "bad idle", spec: "web=12h/30", wantErr: true},
+		{name: "negative", spec: "web=-1h/30m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSessionLifetimes(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSessionLifetimes(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSessionLifetimes(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionLifetimePolicy_For(t *testing.T) {
+	policy := SessionLifetimePolicy{
+		Default: SessionLifetime{MaxLifetime: 720 * time.Hour},
+		Clients: map[string]SessionLifetime{"web": {MaxLifetime: 12 * time.Hour, IdleTimeout: 30 * time.Minute}},
+	}
+
+	if got := policy.For("web"); got != policy.Clients["web"] {
+		t.Errorf("For(web) = %v, want the client override", got)
+	}
+	if got := policy.For("unknown"); got != policy.Default {
+		t.Errorf("For(unknown) = %v, want the default", got)
+	}
+}
+
+func TestSession_IsExpired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		createdAgo time.Duration
+		idleFor    time.Duration
+		lifetime   SessionLifetime
+		want       bool
+	}{
+		{name: "unlimited", createdAgo: 10000 * time.Hour, idleFor: 1000 * time.Hour},
+		{name: "within both limits", createdAgo: time.Hour, idleFor: time.Minute, lifetime: SessionLifetime{MaxLifetime: 12 * time.Hour, IdleTimeout: 30 * time.Minute}},
+		{name: "past max lifetime", createdAgo: 13 * time.Hour, idleFor: time.Minute, lifetime: SessionLifetime{MaxLifetime: 12 * time.Hour, IdleTimeout: 30 * time.Minute}, want: true},
+		{name: "idle too long", createdAgo: time.Hour, idleFor: 31 * time.Minute, lifetime: SessionLifetime{MaxLifetime: 12 * time.Hour, IdleTimeout: 30 * time.Minute}, want: true},
+		{name: "only idle timeout", createdAgo: 10000 * time.Hour, idleFor: time.Minute, lifetime: SessionLifetime{IdleTimeout: 30 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{ID: uuid.New(), CreatedAt: now.Add(-tt.createdAgo), LastUsedAt: now.Add(-tt.idleFor)}
+			if got := session.IsExpired(tt.lifetime); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSession_RefreshTokenExpiry(t *testing.T) {
+	const tokenTTL = 7 * 24 * time.Hour
+	tests := []struct {
+		name       string
+		createdAgo time.Duration
+		lifetime   SessionLifetime
+		want       time.Duration
+	}{
+		{name: "unlimited", want: tokenTTL},
+		{name: "token ends first", lifetime: SessionLifetime{MaxLifetime: 30 * 24 * time.Hour}, want: tokenTTL},
+		{name: "capped by max lifetime", createdAgo: 10 * time.Hour, lifetime: SessionLifetime{MaxLifetime: 12 * time.Hour}, want: 2 * time.Hour},
+		{name: "capped by idle timeout", lifetime: SessionLifetime{IdleTimeout: 30 * time.Minute}, want: 30 * time.Minute},
+		{name: "earliest limit wins", createdAgo: 11*time.Hour + 50*time.Minute, lifetime: SessionLifetime{MaxLifetime: 12 * time.Hour, IdleTimeout: 30 * time.Minute}, want: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			session := &Session{CreatedAt: now.Add(-tt.createdAgo), LastUsedAt: now}
+			got := session.RefreshTokenExpiry(tt.lifetime, tokenTTL).Sub(now)
+			if got < tt.want-time.Second || got > tt.want+time.Second {
+				t.Errorf("RefreshTokenExpiry() in %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSessionLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[Role]int
+		wantErr bool
+	}{
+		{name: "empty", spec: "", want: map[Role]int{}},
+		{name: "several roles", spec: "admin=2, moderator=5", want: map[Role]int{RoleAdmin: 2, RoleModerator: 5}},
+		{name: "blank entries and spaces", spec: " , admin = 2 ,", want: map[Role]int{RoleAdmin: 2}},
+		{name: "missing limit", spec: "admin", wantErr: true},
+		{name: "missing role", spec: "=2", wantErr: true},
+		{name: "not a number", spec: "admin=two", wantErr: true},
+		{name: "zero", spec: "admin=0", wantErr: true},
+		{name: "negative", spec: "admin=-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSessionLimits(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSessionLimits(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSessionLimits(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionLimitPolicy_LimitFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SessionLimitPolicy
+		roles  []Role
+		want   int
+	}{
+		{name: "unlimited", roles: []Role{RoleUser}},
+		{name: "default", policy: SessionLimitPolicy{Default: 10}, roles: []Role{RoleUser}, want: 10},
+		{name: "role tighter than default", policy: SessionLimitPolicy{Default: 10, Roles: map[Role]int{RoleAdmin: 2}}, roles: []Role{RoleUser, RoleAdmin}, want: 2},
+		{name: "role looser than default", policy: SessionLimitPolicy{Default: 3, Roles: map[Role]int{RoleAdmin: 5}}, roles: []Role{RoleAdmin}, want: 3},
+		{name: "role limit without default", policy: SessionLimitPolicy{Roles: map[Role]int{RoleAdmin: 2}}, roles: []Role{RoleAdmin}, want: 2},
+		{name: "smallest role limit wins", policy: SessionLimitPolicy{Roles: map[Role]int{RoleAdmin: 2, RoleModerator: 4}}, roles: []Role{RoleModerator, RoleAdmin}, want: 2},
+		{name: "role not held", policy: SessionLimitPolicy{Default: 10, Roles: map[Role]int{RoleAdmin: 2}}, roles: []Role{RoleUser}, want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.LimitFor(&User{Roles: tt.roles}); got != tt.want {
+				t.Errorf("LimitFor(%v) = %d, want %d", tt.roles, got, tt.want)
+			}
+		})
+	}
+}
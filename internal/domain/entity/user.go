@@ -16,12 +16,14 @@ type User struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	LastLoginAt  *time.Time
+
+	events
 }
 
 // NewUser creates a new user entity
 func NewUser(email, passwordHash string) *User {
 	now := time.Now()
-	return &User{
+	user := &User{
 		ID:           uuid.New(),
 		Email:        email,
 		PasswordHash: passwordHash,
@@ -30,6 +32,16 @@ func NewUser(email, passwordHash string) *User {
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
+	user.raise(EventUserCreated, map[string]interface{}{
+		"email": email,
+	})
+	return user
+}
+
+// MarkSelfRegistered records that the user signed up through public registration,
+// as opposed to being created by an administrator
+func (u *User) MarkSelfRegistered() {
+	u.raise(EventUserRegistered, nil)
 }
 
 // HasRole checks if user has a specific role (RBAC)
@@ -42,25 +54,13 @@ func (u *User) HasRole(role Role) bool {
 	return false
 }
 
-// HasPermission checks if user has permission based on role hierarchy
-func (u *User) HasPermission(requiredRole Role) bool {
-	if !u.IsActive {
-		return false
-	}
-
-	for _, userRole := range u.Roles {
-		if userRole >= requiredRole {
-			return true
-		}
-	}
-	return false
-}
-
 // AddRole adds a role to the user (RBAC)
 func (u *User) AddRole(role Role) {
 	if !u.HasRole(role) {
-		u.Roles = append(u.Roles, role)
+		previous := u.Roles
+		u.Roles = append(append([]Role(nil), u.Roles...), role)
 		u.UpdatedAt = time.Now()
+		u.raiseRolesChanged(previous)
 	}
 }
 
@@ -68,13 +68,28 @@ func (u *User) AddRole(role Role) {
 func (u *User) RemoveRole(role Role) {
 	for i, r := range u.Roles {
 		if r == role {
-			u.Roles = append(u.Roles[:i], u.Roles[i+1:]...)
+			previous := u.Roles
+			u.Roles = append(append([]Role(nil), u.Roles[:i]...), u.Roles[i+1:]...)
 			u.UpdatedAt = time.Now()
+			u.raiseRolesChanged(previous)
 			break
 		}
 	}
 }
 
+// SetRoles replaces all roles of the user (RBAC)
+func (u *User) SetRoles(roles []Role) {
+	previous := u.Roles
+	u.Roles = append([]Role(nil), roles...)
+	u.UpdatedAt = time.Now()
+	u.raiseRolesChanged(previous)
+}
+
+// HasPassword reports whether the user has set a password (invited users have not yet)
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != ""
+}
+
 // UpdateLastLogin updates the last login timestamp
 func (u *User) UpdateLastLogin() {
 	now := time.Now()
@@ -82,14 +97,77 @@ func (u *User) UpdateLastLogin() {
 	u.UpdatedAt = now
 }
 
+// ChangePassword replaces the user's password hash
+func (u *User) ChangePassword(passwordHash string) {
+	// Setting the first password (registration, accepting an invite) is not a change
+	if u.HasPassword() {
+		u.raise(EventUserPasswordChanged, nil)
+	}
+	u.PasswordHash = passwordHash
+	u.UpdatedAt = time.Now()
+}
+
+// ChangeEmail replaces the user's email address
+func (u *User) ChangeEmail(email string) {
+	u.raise(EventUserEmailChanged, map[string]interface{}{
+		"email":          email,
+		"previous_email": u.Email,
+	})
+	u.Email = email
+	u.UpdatedAt = time.Now()
+}
+
+// VerifyEmail replaces the user's email address with a new address the user has proven to own
+func (u *User) VerifyEmail(email string) {
+	u.raise(EventUserEmailVerified, map[string]interface{}{
+		"email":          email,
+		"previous_email": u.Email,
+	})
+	u.Email = email
+	u.UpdatedAt = time.Now()
+}
+
 // Deactivate deactivates the user account
 func (u *User) Deactivate() {
+	if u.IsActive {
+		u.raise(EventUserDeactivated, nil)
+	}
 	u.IsActive = false
 	u.UpdatedAt = time.Now()
 }
 
 // Activate activates the user account
 func (u *User) Activate() {
+	if !u.IsActive {
+		u.raise(EventUserActivated, nil)
+	}
 	u.IsActive = true
 	u.UpdatedAt = time.Now()
 }
+
+// raise records a domain event of the user; every payload carries the user ID and email
+func (u *User) raise(eventType DomainEventType, payload map[string]interface{}) {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	payload["user_id"] = u.ID.String()
+	if _, ok := payload["email"]; !ok {
+		payload["email"] = u.Email
+	}
+	u.events.raise(eventType, AggregateUser, u.ID, payload)
+}
+
+func (u *User) raiseRolesChanged(previous []Role) {
+	u.raise(EventUserRolesChanged, map[string]interface{}{
+		"roles":          roleStrings(u.Roles),
+		"previous_roles": roleStrings(previous),
+	})
+}
+
+func roleStrings(roles []Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return names
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenPurpose describes what a verification token can be used for
+type TokenPurpose string
+
+const (
+	// TokenPurposePasswordReset is used by the forgot password flow
+	TokenPurposePasswordReset TokenPurpose = "password_reset"
+	// TokenPurposeEmailChange confirms ownership of a new email address
+	TokenPurposeEmailChange TokenPurpose = "email_change"
+	// TokenPurposeInvite lets a user created by an admin choose their first password
+	TokenPurposeInvite TokenPurpose = "invite"
+)
+
+// VerificationToken represents a single-use token sent to a user out of band (e.g. by email).
+// Only the hash of the token is stored; the raw value is known to the recipient only.
+type VerificationToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Purpose   TokenPurpose
+	TokenHash string
+	// NewEmail is the address being verified (email change only)
+	NewEmail *string
+	// SessionID is the session that requested the change, kept signed in once it is confirmed (email change only)
+	SessionID *uuid.UUID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewVerificationToken creates a new verification token
+func NewVerificationToken(userID uuid.UUID, purpose TokenPurpose, tokenHash string, expiresAt time.Time) *VerificationToken {
+	return &VerificationToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsExpired checks if the token is expired
+func (t *VerificationToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsUsed checks if the token has already been consumed
+func (t *VerificationToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsValid checks if token is valid (not expired and not used)
+func (t *VerificationToken) IsValid() bool {
+	return !t.IsExpired() && !t.IsUsed()
+}
@@ -0,0 +1,170 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventType identifies an identity lifecycle event delivered to webhook endpoints
+type WebhookEventType string
+
+const (
+	WebhookEventUserCreated       WebhookEventType = "user.created"
+	WebhookEventUserRegistered    WebhookEventType = "user.registered"
+	WebhookEventUserEmailVerified WebhookEventType = "user.email_verified"
+	WebhookEventUserRolesChanged  WebhookEventType = "user.roles_changed"
+	WebhookEventUserDeactivated   WebhookEventType = "user.deactivated"
+	WebhookEventRefreshTokenReuse WebhookEventType = "auth.refresh_token_reuse"
+)
+
+// WebhookEventTypes lists every event type endpoints can subscribe to
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventUserCreated,
+	WebhookEventUserRegistered,
+	WebhookEventUserEmailVerified,
+	WebhookEventUserRolesChanged,
+	WebhookEventUserDeactivated,
+	WebhookEventRefreshTokenReuse,
+}
+
+// IsValidWebhookEventType checks if a string is a known webhook event type
+func IsValidWebhookEventType(s string) bool {
+	for _, eventType := range WebhookEventTypes {
+		if string(eventType) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is an identity event sent to the endpoints subscribed to its type
+type WebhookEvent struct {
+	// ID identifies the event across endpoints and retries, so receivers can de-duplicate
+	ID         uuid.UUID
+	Type       WebhookEventType
+	OccurredAt time.Time
+	Data       map[string]interface{}
+}
+
+// WebhookEndpoint is a URL subscribed to a set of webhook event types
+type WebhookEndpoint struct {
+	ID          uuid.UUID
+	URL         string
+	Description string
+	// Secret signs every payload sent to the endpoint
+	Secret     string
+	EventTypes []WebhookEventType
+	IsActive   bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewWebhookEndpoint creates a new active webhook endpoint
+func NewWebhookEndpoint(url, description, secret string, eventTypes []WebhookEventType) *WebhookEndpoint {
+	now := time.Now()
+	return &WebhookEndpoint{
+		ID:          uuid.New(),
+		URL:         url,
+		Description: description,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Subscribes checks if the endpoint receives events of the given type
+func (e *WebhookEndpoint) Subscribes(eventType WebhookEventType) bool {
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is waiting for its first attempt or a retry
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered was acknowledged with a 2xx response
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead ran out of attempts and is only retried manually
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event queued for one endpoint
+type WebhookDelivery struct {
+	ID         uuid.UUID
+	EndpointID uuid.UUID
+	// EventID is shared by the deliveries of the same event to different endpoints
+	EventID        uuid.UUID
+	EventType      WebhookEventType
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
+
+// NewWebhookDelivery creates a delivery due immediately
+func NewWebhookDelivery(endpointID, eventID uuid.UUID, eventType WebhookEventType, payload []byte) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:            uuid.New(),
+		EndpointID:    endpointID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// MarkDelivered records a successful attempt
+func (d *WebhookDelivery) MarkDelivered(statusCode int) {
+	now := time.Now()
+	d.Attempts++
+	d.Status = WebhookDeliveryDelivered
+	d.LastAttemptAt = &now
+	d.LastStatusCode = &statusCode
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// MarkFailed records a failed attempt and schedules a retry at nextAttemptAt,
+// or moves the delivery to the dead letter state if nextAttemptAt is nil.
+// statusCode is 0 if no response was received.
+func (d *WebhookDelivery) MarkFailed(statusCode int, reason string, nextAttemptAt *time.Time) {
+	now := time.Now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.LastStatusCode = nil
+	if statusCode != 0 {
+		d.LastStatusCode = &statusCode
+	}
+	d.LastError = reason
+
+	if nextAttemptAt == nil {
+		d.Status = WebhookDeliveryDead
+		return
+	}
+	d.Status = WebhookDeliveryPending
+	d.NextAttemptAt = *nextAttemptAt
+}
+
+// Requeue schedules a dead or delivered delivery for another immediate attempt
+func (d *WebhookDelivery) Requeue() {
+	d.Status = WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// AuditEventRepository defines the interface for reading the audit trail.
+// Events are written through service.AuditLogger and are never updated or deleted.
+type AuditEventRepository interface {
+	// Search finds events matching the query, newest first
+	Search(ctx context.Context, query AuditEventQuery) ([]*entity.AuditEvent, error)
+
+	// ListAfterSequence lists up to limit events with a sequence greater than afterSequence, oldest first
+	ListAfterSequence(ctx context.Context, afterSequence int64, limit int) ([]*entity.AuditEvent, error)
+}
+
+// AuditEventQuery filters audit events. Nil and zero fields do not filter.
+type AuditEventQuery struct {
+	Type      entity.AuditEventType
+	ActorID   *uuid.UUID
+	SubjectID *uuid.UUID
+	IPAddress string
+	From      *time.Time
+	To        *time.Time
+
+	// BeforeSequence only returns events older than this sequence (keyset pagination)
+	BeforeSequence int64
+	Limit          int
+}
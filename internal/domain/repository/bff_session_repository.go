@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// BFFSessionRepository defines the interface for the server-side session store of the
+// backend-for-frontend; implementations must keep the tokens encrypted at rest
+type BFFSessionRepository interface {
+	// Create creates a new BFF session
+	Create(ctx context.Context, session *entity.BFFSession) error
+
+	// FindByTokenHash finds an unexpired BFF session by the digest of its cookie,
+	// locking it until the surrounding transaction ends
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.BFFSession, error)
+
+	// Update updates a BFF session
+	Update(ctx context.Context, session *entity.BFFSession) error
+
+	// Delete deletes a BFF session
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// KnownDeviceRepository defines the interface for the devices users have signed in from
+type KnownDeviceRepository interface {
+	// Create creates a new known device
+	Create(ctx context.Context, device *entity.KnownDevice) error
+
+	// FindByFingerprint finds a device of a user by its fingerprint
+	FindByFingerprint(ctx context.Context, userID uuid.UUID, fingerprint string) (*entity.KnownDevice, error)
+
+	// FindLastSeenByUserID finds the device the user signed in from most recently
+	FindLastSeenByUserID(ctx context.Context, userID uuid.UUID) (*entity.KnownDevice, error)
+
+	// Update updates a known device
+	Update(ctx context.Context, device *entity.KnownDevice) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-go/internal/domain/entity"
+)
+
+// LoginAttemptRepository defines the interface for failed login tracking persistence
+type LoginAttemptRepository interface {
+	// FindByKey finds the attempts for a key; an empty record is returned for unknown keys
+	FindByKey(ctx context.Context, key string) (*entity.LoginAttempt, error)
+
+	// RecordFailure atomically increments the failure count of a key.
+	// Failures older than resetAfter are forgotten before counting.
+	RecordFailure(ctx context.Context, key string, resetAfter time.Duration) (*entity.LoginAttempt, error)
+
+	// Lock blocks attempts for a key until the given time
+	Lock(ctx context.Context, key string, until time.Time) error
+
+	// Reset clears the failures and lock of a key
+	Reset(ctx context.Context, key string) error
+}
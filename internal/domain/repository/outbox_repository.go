@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEntry is a domain event waiting in the outbox to be published
+type OutboxEntry struct {
+	Event entity.DomainEvent
+	// Attempts is the number of failed publish attempts so far
+	Attempts int
+}
+
+// OutboxRepository defines the interface for relaying events from the transactional outbox.
+// Events are added by the repositories of the aggregates that raised them.
+type OutboxRepository interface {
+	// ClaimDue returns up to limit unpublished events that are due, oldest first, and hides
+	// them from other relays for lease, so an event whose relay crashes is retried later
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*OutboxEntry, error)
+
+	// MarkPublished marks an event as published to every sink
+	MarkPublished(ctx context.Context, eventID uuid.UUID) error
+
+	// MarkFailed records a failed publish attempt and schedules the next one
+	MarkFailed(ctx context.Context, eventID uuid.UUID, reason string, nextAttemptAt time.Time) error
+
+	// DeletePublishedBefore removes events published before the given time
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
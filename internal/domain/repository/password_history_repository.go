@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHistoryRepository defines the interface for password history persistence
+type PasswordHistoryRepository interface {
+	// Create creates a new password history entry
+	Create(ctx context.Context, entry *entity.PasswordHistoryEntry) error
+
+	// FindRecentByUserID finds the most recent entries for a user, newest first
+	FindRecentByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*entity.PasswordHistoryEntry, error)
+
+	// Prune deletes all but the most recent entries for a user
+	Prune(ctx context.Context, userID uuid.UUID, keep int) error
+}
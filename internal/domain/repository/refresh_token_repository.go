@@ -13,8 +13,8 @@ type RefreshTokenRepository interface {
 	// Create creates a new refresh token
 	Create(ctx context.Context, token *entity.RefreshToken) error
 
-	// FindByToken finds a refresh token by token string
-	FindByToken(ctx context.Context, token string) (*entity.RefreshToken, error)
+	// FindByTokenHash finds a refresh token by the hash of its value
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
 
 	// FindByUserID finds all refresh tokens for a user
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.RefreshToken, error)
@@ -22,12 +22,19 @@ type RefreshTokenRepository interface {
 	// Update updates a refresh token
 	Update(ctx context.Context, token *entity.RefreshToken) error
 
+	// Rotate persists the revocation of a token only if it is still active,
+	// returning ErrTokenRevoked if another request revoked it first
+	Rotate(ctx context.Context, token *entity.RefreshToken) error
+
 	// RevokeByTokenFamily revokes all tokens in a token family (for rotation security)
 	RevokeByTokenFamily(ctx context.Context, tokenFamily uuid.UUID) error
 
 	// RevokeByUserID revokes all tokens for a user
 	RevokeByUserID(ctx context.Context, userID uuid.UUID) error
 
+	// RevokeByUserIDExceptFamily revokes all tokens for a user except those of one token family
+	RevokeByUserIDExceptFamily(ctx context.Context, userID uuid.UUID, keepFamily uuid.UUID) error
+
 	// DeleteExpired deletes all expired tokens
 	DeleteExpired(ctx context.Context) error
 }
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+)
+
+// RoleRepository defines the interface for role definition persistence
+type RoleRepository interface {
+	// Create creates a new role
+	Create(ctx context.Context, role *entity.RoleDefinition) error
+
+	// FindByName finds a role by name
+	FindByName(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error)
+
+	// FindAll lists every role, ordered by name
+	FindAll(ctx context.Context) ([]*entity.RoleDefinition, error)
+
+	// Update updates a role
+	Update(ctx context.Context, role *entity.RoleDefinition) error
+
+	// Delete deletes a role that no user holds and no role inherits from
+	Delete(ctx context.Context, name entity.Role) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// SessionRepository defines the interface for session persistence
+type SessionRepository interface {
+	// Create creates a new session
+	Create(ctx context.Context, session *entity.Session) error
+
+	// FindByID finds a session by ID (token family)
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Session, error)
+
+	// FindActiveByUserID lists the sessions of a user that still hold a valid refresh token,
+	// most recently used first
+	FindActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error)
+
+	// Update updates a session
+	Update(ctx context.Context, session *entity.Session) error
+
+	// LockUserSessions serializes session changes of a user until the surrounding transaction ends
+	LockUserSessions(ctx context.Context, userID uuid.UUID) error
+}
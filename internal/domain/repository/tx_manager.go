@@ -0,0 +1,11 @@
+package repository
+
+import "context"
+
+// TxManager runs units of work spanning several repositories in one transaction
+type TxManager interface {
+	// WithinTransaction runs fn in a transaction, committing if it returns nil and rolling back otherwise.
+	// Repository calls made with the ctx passed to fn take part in the transaction;
+	// nested calls join the outer transaction.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"auth-go/internal/domain/entity"
 
@@ -28,6 +29,42 @@ type UserRepository interface {
 	// ExistsByEmail checks if a user exists by email
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 
-	// FindAll finds all users
-	FindAll(ctx context.Context) ([]*entity.User, error)
+	// Search finds a page of users matching the query
+	Search(ctx context.Context, query UserQuery) (*UserPage, error)
+}
+
+// UserSortField is a field users can be sorted by
+type UserSortField string
+
+const (
+	UserSortCreatedAt   UserSortField = "created_at"
+	UserSortEmail       UserSortField = "email"
+	UserSortLastLoginAt UserSortField = "last_login_at"
+)
+
+// UserQuery filters, sorts and paginates users. Nil and zero fields do not filter.
+type UserQuery struct {
+	EmailPrefix     string
+	Role            *entity.Role
+	IsActive        *bool
+	CreatedAfter    *time.Time
+	CreatedBefore   *time.Time
+	LastLoginAfter  *time.Time
+	LastLoginBefore *time.Time
+
+	SortBy   UserSortField
+	SortDesc bool
+
+	Limit int
+	// Cursor is the NextCursor of the previous page, empty for the first page
+	Cursor string
+}
+
+// UserPage is one page of a user search
+type UserPage struct {
+	Users []*entity.User
+	// NextCursor fetches the following page, empty on the last page
+	NextCursor string
+	// Total is the number of users matching the filters, across all pages
+	Total int
 }
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// VerificationTokenRepository defines the interface for verification token persistence
+type VerificationTokenRepository interface {
+	// Create creates a new verification token
+	Create(ctx context.Context, token *entity.VerificationToken) error
+
+	// FindByTokenHash finds a verification token by the hash of its value
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.VerificationToken, error)
+
+	// Consume marks a token as used; it fails if the token was already used
+	Consume(ctx context.Context, id uuid.UUID) error
+
+	// InvalidateByUserID marks all outstanding tokens of a purpose for a user as used
+	InvalidateByUserID(ctx context.Context, userID uuid.UUID, purpose entity.TokenPurpose) error
+
+	// DeleteExpired deletes all expired tokens
+	DeleteExpired(ctx context.Context) error
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEndpointRepository defines the interface for webhook endpoint persistence
+type WebhookEndpointRepository interface {
+	// Create creates a new webhook endpoint
+	Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+
+	// FindByID finds a webhook endpoint by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error)
+
+	// FindAll returns every webhook endpoint, oldest first
+	FindAll(ctx context.Context) ([]*entity.WebhookEndpoint, error)
+
+	// FindActiveByEventType returns the active endpoints subscribed to an event type
+	FindActiveByEventType(ctx context.Context, eventType entity.WebhookEventType) ([]*entity.WebhookEndpoint, error)
+
+	// Update updates a webhook endpoint
+	Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+
+	// Delete deletes a webhook endpoint and its deliveries
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// WebhookDeliveryRepository defines the interface for the webhook delivery queue
+type WebhookDeliveryRepository interface {
+	// Create queues a new delivery; an event already queued for the endpoint is ignored
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// FindByID finds a delivery by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error)
+
+	// ClaimDue locks up to limit pending deliveries that are due and hides them from other
+	// workers for lease, so a delivery whose worker crashes is retried once the lease expires
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error)
+
+	// Update saves the outcome of a delivery attempt
+	Update(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// Search returns deliveries matching the query, newest first
+	Search(ctx context.Context, query WebhookDeliveryQuery) ([]*entity.WebhookDelivery, error)
+}
+
+// WebhookDeliveryQuery filters and paginates webhook deliveries. Nil and zero fields do not filter.
+type WebhookDeliveryQuery struct {
+	EndpointID *uuid.UUID
+	Status     entity.WebhookDeliveryStatus
+	EventType  entity.WebhookEventType
+	// Before returns deliveries created before this delivery, for pagination
+	Before *uuid.UUID
+	Limit  int
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+)
+
+// AuditLogger defines the interface for recording security-relevant actions
+type AuditLogger interface {
+	Record(ctx context.Context, event *entity.AuditEvent) error
+}
@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+// EmailMessage represents an outgoing email
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender defines the interface for sending emails
+type EmailSender interface {
+	// Send sends an email message
+	Send(ctx context.Context, msg EmailMessage) error
+}
@@ -0,0 +1,9 @@
+package service
+
+import "auth-go/internal/domain/entity"
+
+// GeoIPResolver defines the interface for locating IP addresses
+type GeoIPResolver interface {
+	// Lookup returns the approximate location of an IP address, or nil if it is unknown
+	Lookup(ipAddress string) *entity.GeoLocation
+}
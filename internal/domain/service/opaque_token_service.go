@@ -0,0 +1,11 @@
+package service
+
+// OpaqueTokenService defines the interface for random, non-JWT tokens
+// (password reset links, verification links, ...) that are stored hashed
+type OpaqueTokenService interface {
+	// Generate generates a cryptographically secure random token
+	Generate() (string, error)
+
+	// Hash returns the digest of a token that is safe to store at rest
+	Hash(token string) string
+}
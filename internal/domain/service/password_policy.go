@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+)
+
+// PasswordPolicy defines the interface for password acceptance rules
+type PasswordPolicy interface {
+	// Validate checks a candidate password for the given user.
+	// It returns an *errors.PasswordPolicyError listing every rule that failed.
+	Validate(ctx context.Context, password string, user *entity.User) error
+
+	// Remember records the user's current password hash so it cannot be reused later
+	Remember(ctx context.Context, user *entity.User) error
+}
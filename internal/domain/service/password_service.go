@@ -7,4 +7,7 @@ type PasswordHasher interface {
 
 	// Compare compares a plain text password with a hash
 	Compare(password, hash string) error
+
+	// NeedsRehash reports whether a hash was produced with an outdated algorithm or parameters
+	NeedsRehash(hash string) bool
 }
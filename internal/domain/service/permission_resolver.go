@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+)
+
+// PermissionResolver resolves the permissions granted by a set of roles
+type PermissionResolver interface {
+	// Resolve returns the union of the permissions of the roles
+	Resolve(ctx context.Context, roles []entity.Role) ([]entity.Permission, error)
+}
@@ -0,0 +1,11 @@
+package service
+
+// SecretBox defines the interface for authenticated encryption of secrets at rest
+type SecretBox interface {
+	// Seal encrypts plaintext; associatedData is authenticated but not encrypted,
+	// binding the ciphertext to its context
+	Seal(plaintext, associatedData []byte) (string, error)
+
+	// Open decrypts a ciphertext made by Seal with the same associatedData
+	Open(ciphertext string, associatedData []byte) ([]byte, error)
+}
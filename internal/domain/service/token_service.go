@@ -13,6 +13,17 @@ type TokenClaims struct {
 	UserID uuid.UUID
 	Email  string
 	Roles  []entity.Role
+	// SessionID identifies the refresh token family the access token was issued for
+	SessionID uuid.UUID
+	// AuthTime is when the user authenticated (auth_time claim)
+	AuthTime time.Time
+	// ACR is the authentication context class (acr claim)
+	ACR string
+	// AMR lists the authentication methods used (amr claim)
+	AMR []string
+	// Permissions are the user's permissions when embedded in the token; nil leaves
+	// them to be resolved server-side from the roles
+	Permissions []entity.Permission
 }
 
 // TokenPair represents an access and refresh token pair
@@ -27,9 +38,16 @@ type TokenService interface {
 	// GenerateAccessToken generates a JWT access token
 	GenerateAccessToken(claims TokenClaims) (string, error)
 
+	// GenerateAccessTokenWithExpiry generates a JWT access token valid for the given duration
+	GenerateAccessTokenWithExpiry(claims TokenClaims, expiry time.Duration) (string, error)
+
 	// GenerateRefreshToken generates a refresh token
 	GenerateRefreshToken() (string, error)
 
+	// DeriveRefreshToken returns the successor of a refresh token.
+	// The result is deterministic, so a retried rotation yields the same successor.
+	DeriveRefreshToken(parent string) string
+
 	// ValidateAccessToken validates and parses an access token
 	ValidateAccessToken(token string) (*TokenClaims, error)
 
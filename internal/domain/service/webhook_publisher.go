@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+)
+
+// WebhookPublisher defines the interface for notifying webhook endpoints of identity events
+type WebhookPublisher interface {
+	// Publish queues the event for every active endpoint subscribed to its type.
+	// Publishing the same event ID again does not queue it twice.
+	Publish(ctx context.Context, event entity.WebhookEvent) error
+}
@@ -8,15 +8,34 @@ import (
 
 // Config holds application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Password  PasswordConfig
+	Lockout   LockoutConfig
+	Device    DeviceConfig
+	StepUp    StepUpConfig
+	RateLimit RateLimitConfig
+	Session   SessionConfig
+	Webhook   WebhookConfig
+	Outbox    OutboxConfig
+	Web       WebConfig
+	BFF       BFFConfig
+	App       AppConfig
+	SMTP      SMTPConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
 	Port int
 	Host string
+	// TrustProxyHeaders enables X-Forwarded-For (only behind a trusted reverse proxy)
+	TrustProxyHeaders bool
+	// TrustedProxies lists the proxy addresses and CIDR ranges in front of the server, comma-separated;
+	// if empty, only the proxy the request came from is trusted
+	TrustedProxies string
+	// TrustXRealIP also honours X-Real-IP (only if every trusted proxy overwrites it)
+	TrustXRealIP bool
 }
 
 // DatabaseConfig holds database configuration
@@ -35,6 +54,167 @@ type JWTConfig struct {
 	AccessTokenExpiry  time.Duration
 	RefreshTokenExpiry time.Duration
 	Issuer             string
+	// RefreshReuseGrace is how long a rotated refresh token may be presented again
+	// and get its successor back instead of being treated as reuse
+	RefreshReuseGrace time.Duration
+	// EmbedPermissions puts the user's permissions in access tokens; otherwise they are
+	// resolved server-side from the roles on each request
+	EmbedPermissions bool
+}
+
+// PasswordConfig holds password hashing configuration
+type PasswordConfig struct {
+	// HashAlgorithm is the algorithm used for new hashes: "argon2id" or "bcrypt"
+	HashAlgorithm     string
+	BcryptCost        int
+	Argon2Memory      int // KiB
+	Argon2Iterations  int
+	Argon2Parallelism int
+
+	// Password policy
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	MinStrength      int // 0-4, 0 disables the strength check
+	HistorySize      int // previous passwords that cannot be reused, 0 disables the check
+	// BreachedHashesFile is a local k-anonymity prefix file of SHA-1 hashes of breached passwords
+	BreachedHashesFile string
+}
+
+// LockoutConfig holds failed login throttling configuration
+type LockoutConfig struct {
+	MaxAttempts   int
+	LockDuration  time.Duration
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	IPMaxAttempts int
+	ResetAfter    time.Duration
+}
+
+// DeviceConfig holds new device and suspicious login detection configuration
+type DeviceConfig struct {
+	// NotifyNewDevices emails users who sign in from a device not seen before
+	NotifyNewDevices bool
+	// GeoIPDatabaseFile is a local IP-to-location CSV file enabling the impossible travel check
+	GeoIPDatabaseFile string
+	// MaxTravelSpeedKmh is the fastest plausible travel between two sign ins
+	MaxTravelSpeedKmh int
+}
+
+// StepUpConfig holds step-up authentication configuration for high-risk operations
+type StepUpConfig struct {
+	// MaxAge is how recently the user must have re-authenticated
+	MaxAge time.Duration
+	// TokenExpiry is the lifetime of the elevated access token issued on re-authentication
+	TokenExpiry time.Duration
+}
+
+// RateLimitConfig holds request rate limiting configuration
+type RateLimitConfig struct {
+	Enabled bool
+	// Backend stores token buckets: "memory" (per replica) or "postgres" (shared across replicas)
+	Backend string
+	// Rules per route, as comma-separated KEY=LIMIT/WINDOW entries (keys: ip, email, client)
+	Login          string
+	Register       string
+	Refresh        string
+	ForgotPassword string
+	ResetPassword  string
+	ChangePassword string
+	ChangeEmail    string
+	Reauthenticate string
+	// Clients lists the X-Client-ID values that the client key gives buckets of their own, comma-separated
+	Clients string
+}
+
+// SessionConfig holds session lifetime configuration; zero durations mean unlimited
+type SessionConfig struct {
+	// MaxLifetime is measured from the login, regardless of refreshes
+	MaxLifetime time.Duration
+	// IdleTimeout ends sessions that were not refreshed for this long
+	IdleTimeout time.Duration
+	// ClientLifetimes overrides both per X-Client-ID, as comma-separated CLIENT=MAX/IDLE entries
+	ClientLifetimes string
+	// MaxPerUser caps the active sessions of every user (0 = unlimited)
+	MaxPerUser int
+	// RoleLimits caps the active sessions of users holding a role, as comma-separated ROLE=LIMIT entries
+	RoleLimits string
+	// LimitMode is "reject" (refuse new logins) or "evict" (sign out the oldest session)
+	LimitMode string
+}
+
+// WebhookConfig holds outbound webhook delivery configuration
+type WebhookConfig struct {
+	// WorkerEnabled runs the delivery worker in this process
+	WorkerEnabled bool
+	// MaxAttempts before a delivery is moved to the dead letter state
+	MaxAttempts  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Timeout      time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// OutboxConfig holds domain event relay configuration
+type OutboxConfig struct {
+	// RelayEnabled runs the outbox relay in this process
+	RelayEnabled bool
+	// Sinks is a comma-separated list of event destinations: webhook, nats, stdout
+	Sinks        string
+	PollInterval time.Duration
+	BatchSize    int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Retention    time.Duration
+	// NATS sink
+	NATSURL           string
+	NATSSubjectPrefix string
+}
+
+// WebConfig holds web UI configuration
+type WebConfig struct {
+	// CookieMode keeps the web UI tokens in HttpOnly cookies instead of localStorage,
+	// guarded by a double-submit CSRF token
+	CookieMode bool
+	// CookieSecure marks the cookies Secure; only disable for local development over plain HTTP
+	CookieSecure bool
+}
+
+// BFFConfig holds backend-for-frontend configuration
+type BFFConfig struct {
+	// Enabled serves /bff/login, /bff/session, /bff/logout and the /bff/api/ proxy
+	Enabled bool
+	// Routes maps /bff/api/NAME/... to upstream services, as comma-separated NAME=URL entries
+	Routes string
+	// EncryptionKey is the base64 encoded 32-byte AES key sealing the stored tokens
+	EncryptionKey string
+}
+
+// AppConfig holds general application configuration
+type AppConfig struct {
+	// BaseURL is the public URL of the service, used to build links in emails
+	BaseURL             string
+	PasswordResetExpiry time.Duration
+	EmailVerifyExpiry   time.Duration
+	InviteExpiry        time.Duration
+	// TokenPepper is an optional secret mixed into digests of stored opaque tokens
+	TokenPepper string
+	// RegistrationEnumerationSafe answers every registration with 202 and emails the
+	// owner of an existing account instead of returning 409
+	RegistrationEnumerationSafe bool
+}
+
+// SMTPConfig holds outgoing email configuration
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
 }
 
 // Load loads configuration from environment variables
@@ -43,6 +223,10 @@ func Load() *Config {
 		Server: ServerConfig{
 			Port: getEnvAsInt("SERVER_PORT", 8080),
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+
+			TrustProxyHeaders: getEnvAsBool("SERVER_TRUST_PROXY_HEADERS", false),
+			TrustedProxies:    getEnv("SERVER_TRUSTED_PROXIES", ""),
+			TrustXRealIP:      getEnvAsBool("SERVER_TRUST_X_REAL_IP", false),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -56,7 +240,109 @@ func Load() *Config {
 			SecretKey:          getEnv("JWT_SECRET_KEY", "your-secret-key-change-in-production"),
 			AccessTokenExpiry:  time.Duration(getEnvAsInt("JWT_ACCESS_TOKEN_EXPIRY_MINUTES", 15)) * time.Minute,
 			RefreshTokenExpiry: time.Duration(getEnvAsInt("JWT_REFRESH_TOKEN_EXPIRY_DAYS", 7)) * 24 * time.Hour,
+			RefreshReuseGrace:  time.Duration(getEnvAsInt("JWT_REFRESH_TOKEN_REUSE_GRACE_SECONDS", 10)) * time.Second,
 			Issuer:             getEnv("JWT_ISSUER", "auth-go"),
+			EmbedPermissions:   getEnvAsBool("JWT_EMBED_PERMISSIONS", true),
+		},
+		Password: PasswordConfig{
+			HashAlgorithm:     getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        getEnvAsInt("BCRYPT_COST", 10),
+			Argon2Memory:      getEnvAsInt("ARGON2_MEMORY_KB", 64*1024),
+			Argon2Iterations:  getEnvAsInt("ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getEnvAsInt("ARGON2_PARALLELISM", 2),
+
+			MinLength:          getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+			MaxLength:          getEnvAsInt("PASSWORD_MAX_LENGTH", 128),
+			RequireUppercase:   getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", true),
+			RequireLowercase:   getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", true),
+			RequireDigit:       getEnvAsBool("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol:      getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+			MinStrength:        getEnvAsInt("PASSWORD_MIN_STRENGTH", 2),
+			HistorySize:        getEnvAsInt("PASSWORD_HISTORY_SIZE", 5),
+			BreachedHashesFile: getEnv("PASSWORD_BREACHED_HASHES_FILE", ""),
+		},
+		Lockout: LockoutConfig{
+			MaxAttempts:   getEnvAsInt("LOCKOUT_MAX_ATTEMPTS", 5),
+			LockDuration:  time.Duration(getEnvAsInt("LOCKOUT_DURATION_MINUTES", 15)) * time.Minute,
+			BaseDelay:     time.Duration(getEnvAsInt("LOCKOUT_BASE_DELAY_SECONDS", 1)) * time.Second,
+			MaxDelay:      time.Duration(getEnvAsInt("LOCKOUT_MAX_DELAY_SECONDS", 60)) * time.Second,
+			IPMaxAttempts: getEnvAsInt("LOCKOUT_IP_MAX_ATTEMPTS", 50),
+			ResetAfter:    time.Duration(getEnvAsInt("LOCKOUT_RESET_AFTER_MINUTES", 60)) * time.Minute,
+		},
+		Device: DeviceConfig{
+			NotifyNewDevices:  getEnvAsBool("DEVICE_NOTIFY_NEW", true),
+			GeoIPDatabaseFile: getEnv("GEOIP_DATABASE_FILE", ""),
+			MaxTravelSpeedKmh: getEnvAsInt("GEOIP_MAX_TRAVEL_SPEED_KMH", 1000),
+		},
+		StepUp: StepUpConfig{
+			MaxAge:      time.Duration(getEnvAsInt("STEP_UP_MAX_AGE_MINUTES", 10)) * time.Minute,
+			TokenExpiry: time.Duration(getEnvAsInt("STEP_UP_TOKEN_EXPIRY_MINUTES", 5)) * time.Minute,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			Backend:        getEnv("RATE_LIMIT_BACKEND", "memory"),
+			Login:          getEnv("RATE_LIMIT_LOGIN", "ip=20/1m,email=5/1m"),
+			Register:       getEnv("RATE_LIMIT_REGISTER", "ip=5/10m"),
+			Refresh:        getEnv("RATE_LIMIT_REFRESH", "client=30/1m"),
+			ForgotPassword: getEnv("RATE_LIMIT_FORGOT_PASSWORD", "ip=10/15m,email=3/15m"),
+			ResetPassword:  getEnv("RATE_LIMIT_RESET_PASSWORD", "ip=10/15m"),
+			ChangePassword: getEnv("RATE_LIMIT_CHANGE_PASSWORD", "ip=10/15m"),
+			ChangeEmail:    getEnv("RATE_LIMIT_CHANGE_EMAIL", "ip=10/15m"),
+			Reauthenticate: getEnv("RATE_LIMIT_REAUTHENTICATE", "ip=10/15m"),
+			Clients:        getEnv("RATE_LIMIT_CLIENTS", ""),
+		},
+		Session: SessionConfig{
+			MaxLifetime:     time.Duration(getEnvAsInt("SESSION_MAX_LIFETIME_HOURS", 720)) * time.Hour,
+			IdleTimeout:     time.Duration(getEnvAsInt("SESSION_IDLE_TIMEOUT_HOURS", 168)) * time.Hour,
+			ClientLifetimes: getEnv("SESSION_CLIENT_LIFETIMES", ""),
+			MaxPerUser:      getEnvAsInt("SESSION_MAX_PER_USER", 0),
+			RoleLimits:      getEnv("SESSION_ROLE_LIMITS", ""),
+			LimitMode:       getEnv("SESSION_LIMIT_MODE", "reject"),
+		},
+		Webhook: WebhookConfig{
+			WorkerEnabled: getEnvAsBool("WEBHOOK_WORKER_ENABLED", true),
+			MaxAttempts:   getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			BaseDelay:     time.Duration(getEnvAsInt("WEBHOOK_RETRY_BASE_DELAY_SECONDS", 30)) * time.Second,
+			MaxDelay:      time.Duration(getEnvAsInt("WEBHOOK_RETRY_MAX_DELAY_MINUTES", 360)) * time.Minute,
+			Timeout:       time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+			PollInterval:  time.Duration(getEnvAsInt("WEBHOOK_POLL_INTERVAL_SECONDS", 5)) * time.Second,
+			BatchSize:     getEnvAsInt("WEBHOOK_BATCH_SIZE", 20),
+		},
+		Outbox: OutboxConfig{
+			RelayEnabled:      getEnvAsBool("OUTBOX_RELAY_ENABLED", true),
+			Sinks:             getEnv("OUTBOX_SINKS", "webhook"),
+			PollInterval:      time.Duration(getEnvAsInt("OUTBOX_POLL_INTERVAL_MS", 500)) * time.Millisecond,
+			BatchSize:         getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			BaseDelay:         time.Duration(getEnvAsInt("OUTBOX_RETRY_BASE_DELAY_SECONDS", 5)) * time.Second,
+			MaxDelay:          time.Duration(getEnvAsInt("OUTBOX_RETRY_MAX_DELAY_MINUTES", 30)) * time.Minute,
+			Retention:         time.Duration(getEnvAsInt("OUTBOX_RETENTION_HOURS", 168)) * time.Hour,
+			NATSURL:           getEnv("OUTBOX_NATS_URL", "nats://localhost:4222"),
+			NATSSubjectPrefix: getEnv("OUTBOX_NATS_SUBJECT_PREFIX", "auth.events"),
+		},
+		Web: WebConfig{
+			CookieMode:   getEnvAsBool("WEB_COOKIE_MODE", false),
+			CookieSecure: getEnvAsBool("WEB_COOKIE_SECURE", true),
+		},
+		BFF: BFFConfig{
+			Enabled:       getEnvAsBool("BFF_ENABLED", false),
+			Routes:        getEnv("BFF_ROUTES", ""),
+			EncryptionKey: getEnv("BFF_ENCRYPTION_KEY", ""),
+		},
+		App: AppConfig{
+			BaseURL:             getEnv("APP_BASE_URL", "http://localhost:8080"),
+			PasswordResetExpiry: time.Duration(getEnvAsInt("PASSWORD_RESET_EXPIRY_MINUTES", 30)) * time.Minute,
+			EmailVerifyExpiry:   time.Duration(getEnvAsInt("EMAIL_VERIFY_EXPIRY_HOURS", 24)) * time.Hour,
+			InviteExpiry:        time.Duration(getEnvAsInt("INVITE_EXPIRY_HOURS", 72)) * time.Hour,
+			TokenPepper:         getEnv("TOKEN_PEPPER", ""),
+
+			RegistrationEnumerationSafe: getEnvAsBool("REGISTRATION_ENUMERATION_SAFE", false),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvAsInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "no-reply@auth-go.local"),
 		},
 	}
 }
@@ -78,3 +364,13 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsBool gets an environment variable as bool or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
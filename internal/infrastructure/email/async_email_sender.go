@@ -0,0 +1,38 @@
+package email
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"auth-go/internal/domain/service"
+)
+
+// AsyncEmailSender sends emails in the background so that request latency
+// does not depend on the mail server (and does not leak whether an email was sent)
+type AsyncEmailSender struct {
+	next    service.EmailSender
+	timeout time.Duration
+}
+
+// NewAsyncEmailSender wraps an email sender so that Send returns immediately
+func NewAsyncEmailSender(next service.EmailSender) service.EmailSender {
+	return &AsyncEmailSender{
+		next:    next,
+		timeout: 30 * time.Second,
+	}
+}
+
+// Send queues the email message and returns without waiting for delivery
+func (s *AsyncEmailSender) Send(ctx context.Context, msg service.EmailMessage) error {
+	go func() {
+		// Detach from the request context, which is cancelled once the response is written
+		sendCtx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+
+		if err := s.next.Send(sendCtx, msg); err != nil {
+			log.Printf("Failed to send email to %s: %v", msg.To, err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,23 @@
+package email
+
+import (
+	"context"
+	"log"
+
+	"auth-go/internal/domain/service"
+)
+
+// LogEmailSender implements EmailSender by writing emails to the application log.
+// It is used for local development when no SMTP server is configured.
+type LogEmailSender struct{}
+
+// NewLogEmailSender creates a new log email sender
+func NewLogEmailSender() service.EmailSender {
+	return &LogEmailSender{}
+}
+
+// Send logs the email message
+func (s *LogEmailSender) Send(ctx context.Context, msg service.EmailMessage) error {
+	log.Printf("Email to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,60 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"auth-go/internal/domain/service"
+)
+
+// SMTPConfig holds SMTP configuration
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPEmailSender implements EmailSender using an SMTP server
+type SMTPEmailSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPEmailSender creates a new SMTP email sender
+func NewSMTPEmailSender(config SMTPConfig) service.EmailSender {
+	return &SMTPEmailSender{config: config}
+}
+
+// Send sends an email message through the configured SMTP server
+func (s *SMTPEmailSender) Send(ctx context.Context, msg service.EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	return smtp.SendMail(addr, auth, s.config.From, []string{msg.To}, s.buildMessage(msg))
+}
+
+// buildMessage builds an RFC 5322 plain text message
+func (s *SMTPEmailSender) buildMessage(msg service.EmailMessage) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + s.config.From + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,136 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"auth-go/internal/domain/entity"
+)
+
+// ipRange is a block of addresses sharing a location
+type ipRange struct {
+	start, end netip.Addr
+	location   entity.GeoLocation
+}
+
+// CSVDatabase implements GeoIPResolver with an in-memory copy of a local IP range file,
+// so no IP address is sent to a third party
+type CSVDatabase struct {
+	ranges []ipRange
+}
+
+// LoadCSVDatabase loads an IP-to-location CSV file, sorted or not. Each row is either
+// "ip_start,ip_end,country,latitude,longitude" or the DB-IP City Lite layout
+// "ip_start,ip_end,continent,country,stateprov,city,latitude,longitude".
+// Empty lines and lines starting with '#' are ignored.
+func LoadCSVDatabase(path string) (*CSVDatabase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+
+	db := &CSVDatabase{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		r, err := parseRange(record)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		db.ranges = append(db.ranges, r)
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool {
+		return db.ranges[i].start.Less(db.ranges[j].start)
+	})
+
+	return db, nil
+}
+
+// Lookup returns the location of the range containing the IP address, or nil
+func (db *CSVDatabase) Lookup(ipAddress string) *entity.GeoLocation {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return nil
+	}
+	addr = addr.Unmap()
+
+	// The last range starting at or before the address is the only candidate
+	i := sort.Search(len(db.ranges), func(i int) bool {
+		return addr.Less(db.ranges[i].start)
+	}) - 1
+	if i < 0 || db.ranges[i].end.Less(addr) {
+		return nil
+	}
+
+	location := db.ranges[i].location
+	return &location
+}
+
+// Size returns the number of IP ranges in the database
+func (db *CSVDatabase) Size() int {
+	return len(db.ranges)
+}
+
+// parseRange parses a CSV row in one of the supported layouts
+func parseRange(record []string) (ipRange, error) {
+	var country, latitude, longitude string
+	switch len(record) {
+	case 5:
+		country, latitude, longitude = record[2], record[3], record[4]
+	case 8:
+		country, latitude, longitude = record[3], record[6], record[7]
+	default:
+		return ipRange{}, fmt.Errorf("expected 5 or 8 columns, got %d", len(record))
+	}
+
+	start, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+	if err != nil {
+		return ipRange{}, fmt.Errorf("invalid start address: %v", err)
+	}
+	end, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+	if err != nil {
+		return ipRange{}, fmt.Errorf("invalid end address: %v", err)
+	}
+	start, end = start.Unmap(), end.Unmap()
+	if start.Is4() != end.Is4() || end.Less(start) {
+		return ipRange{}, fmt.Errorf("invalid address range")
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latitude), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return ipRange{}, fmt.Errorf("invalid latitude %q", latitude)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(longitude), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return ipRange{}, fmt.Errorf("invalid longitude %q", longitude)
+	}
+
+	return ipRange{
+		start: start,
+		end:   end,
+		location: entity.GeoLocation{
+			Country:   strings.TrimSpace(country),
+			Latitude:  lat,
+			Longitude: lon,
+		},
+	}, nil
+}
@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"auth-go/internal/domain/entity"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSSink publishes every event to the NATS subject "<prefix>.<event type>",
+// e.g. "auth.events.user.created"
+type NATSSink struct {
+	conn          *nats.Conn
+	subjectPrefix string
+	flushTimeout  time.Duration
+}
+
+// NewNATSSink connects to a NATS server and creates a sink publishing to it
+func NewNATSSink(url, subjectPrefix string) (*NATSSink, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("auth-go outbox relay"),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSSink{
+		conn:          conn,
+		subjectPrefix: subjectPrefix,
+		flushTimeout:  5 * time.Second,
+	}, nil
+}
+
+// Name identifies the sink in logs
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+// Publish sends the event and waits until the server has received it.
+// The Nats-Msg-Id header lets JetStream streams drop events relayed twice.
+func (s *NATSSink) Publish(ctx context.Context, event entity.DomainEvent) error {
+	data, err := marshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(s.subjectPrefix + "." + string(event.Type))
+	msg.Header.Set(nats.MsgIdHdr, event.ID.String())
+	msg.Data = data
+
+	if err := s.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	flushCtx, cancel := context.WithTimeout(ctx, s.flushTimeout)
+	defer cancel()
+	return s.conn.FlushWithContext(flushCtx)
+}
+
+// Close drains and closes the NATS connection
+func (s *NATSSink) Close() error {
+	return s.conn.Drain()
+}
@@ -0,0 +1,100 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+)
+
+func TestNATSSink_Publish(t *testing.T) {
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	server := natsserver.RunServer(&opts)
+	defer server.Shutdown()
+
+	conn, err := nats.Connect(server.ClientURL())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close()
+
+	js, err := conn.JetStream()
+	if err != nil {
+		t.Fatalf("JetStream: %v", err)
+	}
+	if _, err := js.AddStream(&nats.StreamConfig{
+		Name:       "AUTH_EVENTS",
+		Subjects:   []string{"auth.events.>"},
+		Duplicates: time.Minute,
+	}); err != nil {
+		t.Fatalf("AddStream: %v", err)
+	}
+
+	messages, err := conn.SubscribeSync("auth.events.>")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := conn.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	sink, err := NewNATSSink(server.ClientURL(), "auth.events")
+	if err != nil {
+		t.Fatalf("NewNATSSink() error = %v", err)
+	}
+	defer func() {
+		if err := sink.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	}()
+
+	user := entity.NewUser("ada@example.com", "")
+	event := user.PullEvents()[0]
+
+	// The relay delivers at least once, so the same event can be published twice
+	for i := 0; i < 2; i++ {
+		if err := sink.Publish(context.Background(), event); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	msg, err := messages.NextMsg(time.Second)
+	if err != nil {
+		t.Fatalf("no message received: %v", err)
+	}
+	if msg.Subject != "auth.events.user.created" {
+		t.Errorf("Subject = %q, want %q", msg.Subject, "auth.events.user.created")
+	}
+	if id := msg.Header.Get(nats.MsgIdHdr); id != event.ID.String() {
+		t.Errorf("%s = %q, want the event ID %q", nats.MsgIdHdr, id, event.ID)
+	}
+
+	var got envelope
+	if err := json.Unmarshal(msg.Data, &got); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if got.ID != event.ID.String() || got.Type != "user.created" || got.AggregateType != entity.AggregateUser ||
+		got.AggregateID != user.ID.String() || got.Data["email"] != "ada@example.com" {
+		t.Errorf("payload = %s", msg.Data)
+	}
+
+	// Both copies reach core subscribers, but the stream keeps only one
+	if _, err := messages.NextMsg(time.Second); err != nil {
+		t.Errorf("second publish not received: %v", err)
+	}
+	info, err := js.StreamInfo("AUTH_EVENTS")
+	if err != nil {
+		t.Fatalf("StreamInfo: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("stream holds %d messages, want 1 after de-duplication", info.State.Msgs)
+	}
+}
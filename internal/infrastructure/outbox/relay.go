@@ -0,0 +1,123 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"auth-go/internal/domain/repository"
+)
+
+// RelayConfig configures the outbox relay
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// BaseDelay before retrying an event a sink rejected; every further retry waits twice as long
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries. Events are never dropped.
+	MaxDelay time.Duration
+	// Retention of published events before they are deleted
+	Retention time.Duration
+}
+
+// Relay publishes events from the transactional outbox to every sink. Several replicas can run
+// a relay against the same database: events are claimed with row locks.
+type Relay struct {
+	outboxRepo repository.OutboxRepository
+	sinks      []Sink
+	config     RelayConfig
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(outboxRepo repository.OutboxRepository, sinks []Sink, config RelayConfig) *Relay {
+	return &Relay{
+		outboxRepo: outboxRepo,
+		sinks:      sinks,
+		config:     config,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for {
+		// Keep going while full batches are waiting, otherwise sleep until the next tick
+		claimed := r.ProcessDue(ctx)
+		if claimed == r.config.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		if time.Since(lastCleanup) > time.Hour {
+			lastCleanup = time.Now()
+			if _, err := r.outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-r.config.Retention)); err != nil {
+				log.Printf("Error cleaning up outbox: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue publishes one batch of due events, oldest first, and returns how many were claimed
+func (r *Relay) ProcessDue(ctx context.Context) int {
+	entries, err := r.outboxRepo.ClaimDue(ctx, r.config.BatchSize, time.Minute)
+	if err != nil {
+		log.Printf("Error claiming outbox events: %v", err)
+		return 0
+	}
+
+	for _, entry := range entries {
+		if err := r.publish(ctx, entry); err != nil {
+			next := time.Now().Add(r.retryDelay(entry.Attempts + 1))
+			log.Printf("Error publishing outbox event %s (%s), retrying at %s: %v",
+				entry.Event.ID, entry.Event.Type, next.Format(time.RFC3339), err)
+			if err := r.outboxRepo.MarkFailed(ctx, entry.Event.ID, err.Error(), next); err != nil {
+				log.Printf("Error saving outbox event %s: %v", entry.Event.ID, err)
+			}
+			continue
+		}
+
+		if err := r.outboxRepo.MarkPublished(ctx, entry.Event.ID); err != nil {
+			log.Printf("Error saving outbox event %s: %v", entry.Event.ID, err)
+		}
+	}
+
+	return len(entries)
+}
+
+// publish sends the event to every sink. If any sink fails, the event is retried on all of
+// them, so sinks that already succeeded see it again.
+func (r *Relay) publish(ctx context.Context, entry *repository.OutboxEntry) error {
+	var failures []string
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, entry.Event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sink.Name(), err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// retryDelay returns how long to wait after the given number of failed attempts
+func (r *Relay) retryDelay(attempts int) time.Duration {
+	delay := r.config.BaseDelay
+	for i := 1; i < attempts && delay < r.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.config.MaxDelay {
+		delay = r.config.MaxDelay
+	}
+	return delay
+}
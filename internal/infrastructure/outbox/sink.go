@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"auth-go/internal/domain/entity"
+)
+
+// Sink is a destination the relay publishes domain events to.
+// Events are delivered at least once, so sinks and their consumers must tolerate duplicates.
+type Sink interface {
+	// Name identifies the sink in logs
+	Name() string
+
+	// Publish delivers one event, returning an error if it has to be retried
+	Publish(ctx context.Context, event entity.DomainEvent) error
+}
+
+// envelope is the JSON encoding of a domain event used by message-oriented sinks
+type envelope struct {
+	ID            string                 `json:"id"`
+	Type          string                 `json:"type"`
+	AggregateType string                 `json:"aggregate_type"`
+	AggregateID   string                 `json:"aggregate_id"`
+	OccurredAt    string                 `json:"occurred_at"`
+	Data          map[string]interface{} `json:"data"`
+}
+
+// marshalEvent encodes a domain event as a JSON envelope
+func marshalEvent(event entity.DomainEvent) ([]byte, error) {
+	return json.Marshal(envelope{
+		ID:            event.ID.String(),
+		Type:          string(event.Type),
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID.String(),
+		OccurredAt:    event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		Data:          event.Payload,
+	})
+}
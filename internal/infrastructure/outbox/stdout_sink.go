@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"auth-go/internal/domain/entity"
+)
+
+// StdoutSink writes every event as one line of JSON, for development and log shipping
+type StdoutSink struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewStdoutSink creates a sink writing to out (usually os.Stdout)
+func NewStdoutSink(out io.Writer) *StdoutSink {
+	return &StdoutSink{out: out}
+}
+
+// Name identifies the sink in logs
+func (s *StdoutSink) Name() string {
+	return "stdout"
+}
+
+// Publish writes the event as a JSON line
+func (s *StdoutSink) Publish(ctx context.Context, event entity.DomainEvent) error {
+	line, err := marshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.out.Write(append(line, '\n'))
+	return err
+}
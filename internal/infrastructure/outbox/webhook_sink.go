@@ -0,0 +1,49 @@
+package outbox
+
+import (
+	"context"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/service"
+)
+
+// webhookEventTypes maps the domain events exposed to webhook endpoints to their webhook event type
+var webhookEventTypes = map[entity.DomainEventType]entity.WebhookEventType{
+	entity.EventUserCreated:               entity.WebhookEventUserCreated,
+	entity.EventUserRegistered:            entity.WebhookEventUserRegistered,
+	entity.EventUserEmailVerified:         entity.WebhookEventUserEmailVerified,
+	entity.EventUserRolesChanged:          entity.WebhookEventUserRolesChanged,
+	entity.EventUserDeactivated:           entity.WebhookEventUserDeactivated,
+	entity.EventRefreshTokenReuseDetected: entity.WebhookEventRefreshTokenReuse,
+}
+
+// WebhookSink queues webhook deliveries for the domain events endpoints can subscribe to
+type WebhookSink struct {
+	publisher service.WebhookPublisher
+}
+
+// NewWebhookSink creates a sink publishing to webhook endpoints
+func NewWebhookSink(publisher service.WebhookPublisher) *WebhookSink {
+	return &WebhookSink{publisher: publisher}
+}
+
+// Name identifies the sink in logs
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Publish queues the event for its subscribers; other domain events are ignored.
+// The webhook event reuses the domain event ID, so a relayed duplicate is not queued twice.
+func (s *WebhookSink) Publish(ctx context.Context, event entity.DomainEvent) error {
+	eventType, ok := webhookEventTypes[event.Type]
+	if !ok {
+		return nil
+	}
+
+	return s.publisher.Publish(ctx, entity.WebhookEvent{
+		ID:         event.ID,
+		Type:       eventType,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Payload,
+	})
+}
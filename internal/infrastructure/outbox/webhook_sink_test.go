@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+
+	"auth-go/internal/domain/entity"
+)
+
+// fakeWebhookPublisher records the published webhook events
+type fakeWebhookPublisher struct {
+	published []entity.WebhookEvent
+}
+
+func (p *fakeWebhookPublisher) Publish(ctx context.Context, event entity.WebhookEvent) error {
+	p.published = append(p.published, event)
+	return nil
+}
+
+func TestWebhookSink_Publish(t *testing.T) {
+	registered := entity.NewUser("ada@example.com", "")
+	registered.MarkSelfRegistered()
+	created := entity.NewUser("bob@example.com", "")
+
+	tests := []struct {
+		name   string
+		events []entity.DomainEvent
+		want   []entity.WebhookEventType
+	}{
+		{name: "self-registration", events: registered.PullEvents(), want: []entity.WebhookEventType{entity.WebhookEventUserCreated, entity.WebhookEventUserRegistered}},
+		{name: "created by an administrator", events: created.PullEvents(), want: []entity.WebhookEventType{entity.WebhookEventUserCreated}},
+		{name: "not exposed to webhooks", events: []entity.DomainEvent{{Type: entity.EventRefreshTokenIssued}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &fakeWebhookPublisher{}
+			sink := NewWebhookSink(publisher)
+			for _, event := range tt.events {
+				if err := sink.Publish(context.Background(), event); err != nil {
+					t.Fatalf("Publish() error = %v", err)
+				}
+			}
+
+			if len(publisher.published) != len(tt.want) {
+				t.Fatalf("published %d events, want %d", len(publisher.published), len(tt.want))
+			}
+			for i, event := range publisher.published {
+				if event.Type != tt.want[i] {
+					t.Errorf("event %d = %s, want %s", i, event.Type, tt.want[i])
+				}
+				if event.ID != tt.events[i].ID {
+					t.Errorf("event %d ID = %s, want the domain event ID %s", i, event.ID, tt.events[i].ID)
+				}
+			}
+		})
+	}
+}
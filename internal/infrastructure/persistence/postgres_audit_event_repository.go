@@ -0,0 +1,128 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const auditEventColumns = `sequence, id, event_type, actor_id, subject_id, ip_address, user_agent, details, created_at, prev_hash, hash`
+
+// PostgresAuditEventRepository implements AuditEventRepository using PostgreSQL
+type PostgresAuditEventRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAuditEventRepository creates a new PostgreSQL audit event repository
+func NewPostgresAuditEventRepository(db *sql.DB) repository.AuditEventRepository {
+	return &PostgresAuditEventRepository{db: db}
+}
+
+// Search finds events matching the query, newest first
+func (r *PostgresAuditEventRepository) Search(ctx context.Context, q repository.AuditEventQuery) ([]*entity.AuditEvent, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if q.Type != "" {
+		conditions = append(conditions, "event_type = "+arg(string(q.Type)))
+	}
+	if q.ActorID != nil {
+		conditions = append(conditions, "actor_id = "+arg(*q.ActorID))
+	}
+	if q.SubjectID != nil {
+		conditions = append(conditions, "subject_id = "+arg(*q.SubjectID))
+	}
+	if q.IPAddress != "" {
+		conditions = append(conditions, "ip_address = "+arg(q.IPAddress))
+	}
+	if q.From != nil {
+		conditions = append(conditions, "created_at >= "+arg(*q.From))
+	}
+	if q.To != nil {
+		conditions = append(conditions, "created_at < "+arg(*q.To))
+	}
+	if q.BeforeSequence > 0 {
+		conditions = append(conditions, "sequence < "+arg(q.BeforeSequence))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM audit_events %s ORDER BY sequence DESC LIMIT %s`, auditEventColumns, where, arg(q.Limit))
+
+	return r.query(ctx, query, args...)
+}
+
+// ListAfterSequence lists up to limit events with a sequence greater than afterSequence, oldest first
+func (r *PostgresAuditEventRepository) ListAfterSequence(ctx context.Context, afterSequence int64, limit int) ([]*entity.AuditEvent, error) {
+	query := fmt.Sprintf(`SELECT %s FROM audit_events WHERE sequence > $1 ORDER BY sequence ASC LIMIT $2`, auditEventColumns)
+
+	return r.query(ctx, query, afterSequence, limit)
+}
+
+func (r *PostgresAuditEventRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.AuditEvent, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			// Log error but don't fail the operation
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var events []*entity.AuditEvent
+	for rows.Next() {
+		event := &entity.AuditEvent{}
+		var eventType string
+		var actorID, subjectID uuid.NullUUID
+		var details []byte
+
+		err := rows.Scan(
+			&event.Sequence,
+			&event.ID,
+			&eventType,
+			&actorID,
+			&subjectID,
+			&event.IPAddress,
+			&event.UserAgent,
+			&details,
+			&event.CreatedAt,
+			&event.PrevHash,
+			&event.Hash,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Type = entity.AuditEventType(eventType)
+		if actorID.Valid {
+			event.ActorID = &actorID.UUID
+		}
+		if subjectID.Valid {
+			event.SubjectID = &subjectID.UUID
+		}
+		if err := json.Unmarshal(details, &event.Details); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/service"
+)
+
+// auditChainLockID is the advisory lock serializing appends to the audit hash chain.
+// There is a single chain, so appends from every instance queue on this lock: audit
+// throughput is capped at one append per lock hold (a few database round trips).
+const auditChainLockID = 0x617564697400
+
+// PostgresAuditLogger implements AuditLogger using an append-only PostgreSQL table
+type PostgresAuditLogger struct {
+	db *sql.DB
+}
+
+// NewPostgresAuditLogger creates a new PostgreSQL audit logger
+func NewPostgresAuditLogger(db *sql.DB) service.AuditLogger {
+	return &PostgresAuditLogger{db: db}
+}
+
+// Record appends an audit event to the hash chain.
+// It always runs in its own short transaction, never in a transaction carried by ctx,
+// so the chain lock is only held for the append itself and not for the caller's work.
+func (l *PostgresAuditLogger) Record(ctx context.Context, event *entity.AuditEvent) error {
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return err
+	}
+
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// No-op once committed
+		_ = tx.Rollback()
+	}()
+
+	// Appends must not interleave, otherwise two events would share a predecessor
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockID); err != nil {
+		return err
+	}
+
+	var prevHash string
+	err = tx.QueryRowContext(ctx, `SELECT hash FROM audit_events ORDER BY sequence DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	event.PrevHash = prevHash
+	event.Hash = event.ComputeHash()
+
+	query := `
+		INSERT INTO audit_events (id, event_type, actor_id, subject_id, ip_address, user_agent, details, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING sequence
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		event.ID,
+		string(event.Type),
+		event.ActorID,
+		event.SubjectID,
+		event.IPAddress,
+		event.UserAgent,
+		details,
+		event.CreatedAt,
+		event.PrevHash,
+		event.Hash,
+	).Scan(&event.Sequence)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
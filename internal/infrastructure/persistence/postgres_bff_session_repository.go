@@ -0,0 +1,173 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// PostgresBFFSessionRepository implements BFFSessionRepository using PostgreSQL.
+// Tokens are sealed with the secret box before they are written.
+type PostgresBFFSessionRepository struct {
+	db        *sql.DB
+	secretBox service.SecretBox
+}
+
+// NewPostgresBFFSessionRepository creates a new PostgreSQL BFF session repository
+func NewPostgresBFFSessionRepository(db *sql.DB, secretBox service.SecretBox) repository.BFFSessionRepository {
+	return &PostgresBFFSessionRepository{
+		db:        db,
+		secretBox: secretBox,
+	}
+}
+
+// Create creates a new BFF session
+func (r *PostgresBFFSessionRepository) Create(ctx context.Context, session *entity.BFFSession) error {
+	accessToken, refreshToken, err := r.seal(session)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO bff_sessions (id, token_hash, user_id, session_id, access_token, access_token_expires_at,
+			refresh_token, created_at, updated_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		session.ID,
+		session.TokenHash,
+		session.UserID,
+		session.SessionID,
+		accessToken,
+		session.AccessTokenExpiresAt,
+		refreshToken,
+		session.CreatedAt,
+		session.UpdatedAt,
+		session.ExpiresAt,
+	)
+
+	return err
+}
+
+// FindByTokenHash finds an unexpired BFF session by the digest of its cookie, locking the row
+func (r *PostgresBFFSessionRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.BFFSession, error) {
+	query := `
+		SELECT id, token_hash, user_id, session_id, access_token, access_token_expires_at,
+			refresh_token, created_at, updated_at, expires_at
+		FROM bff_sessions
+		WHERE token_hash = $1 AND expires_at > NOW()
+		FOR UPDATE
+	`
+
+	session := &entity.BFFSession{}
+	var accessToken, refreshToken string
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash).Scan(
+		&session.ID,
+		&session.TokenHash,
+		&session.UserID,
+		&session.SessionID,
+		&accessToken,
+		&session.AccessTokenExpiresAt,
+		&refreshToken,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+		&session.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.open(session, accessToken, refreshToken); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Update updates a BFF session
+func (r *PostgresBFFSessionRepository) Update(ctx context.Context, session *entity.BFFSession) error {
+	accessToken, refreshToken, err := r.seal(session)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE bff_sessions
+		SET access_token = $2, access_token_expires_at = $3, refresh_token = $4, updated_at = $5, expires_at = $6
+		WHERE id = $1
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		session.ID,
+		accessToken,
+		session.AccessTokenExpiresAt,
+		refreshToken,
+		session.UpdatedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apperrors.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a BFF session
+func (r *PostgresBFFSessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM bff_sessions WHERE id = $1`, id)
+	return err
+}
+
+// seal encrypts the tokens of a session, binding each ciphertext to its row and column
+func (r *PostgresBFFSessionRepository) seal(session *entity.BFFSession) (string, string, error) {
+	accessToken, err := r.secretBox.Seal([]byte(session.AccessToken), tokenAssociatedData(session.ID, "access_token"))
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := r.secretBox.Seal([]byte(session.RefreshToken), tokenAssociatedData(session.ID, "refresh_token"))
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+// open decrypts the tokens of a session read from the database
+func (r *PostgresBFFSessionRepository) open(session *entity.BFFSession, accessToken, refreshToken string) error {
+	plaintext, err := r.secretBox.Open(accessToken, tokenAssociatedData(session.ID, "access_token"))
+	if err != nil {
+		return err
+	}
+	session.AccessToken = string(plaintext)
+
+	plaintext, err = r.secretBox.Open(refreshToken, tokenAssociatedData(session.ID, "refresh_token"))
+	if err != nil {
+		return err
+	}
+	session.RefreshToken = string(plaintext)
+
+	return nil
+}
+
+// tokenAssociatedData identifies where a sealed token belongs
+func tokenAssociatedData(id uuid.UUID, column string) []byte {
+	return []byte("bff_sessions:" + id.String() + ":" + column)
+}
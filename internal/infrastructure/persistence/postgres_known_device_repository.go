@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// knownDeviceColumns lists the columns scanned by scanKnownDevice
+const knownDeviceColumns = `id, user_id, fingerprint, user_agent, ip_address, country, latitude, longitude, first_seen_at, last_seen_at`
+
+// PostgresKnownDeviceRepository implements KnownDeviceRepository using PostgreSQL
+type PostgresKnownDeviceRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresKnownDeviceRepository creates a new PostgreSQL known device repository
+func NewPostgresKnownDeviceRepository(db *sql.DB) repository.KnownDeviceRepository {
+	return &PostgresKnownDeviceRepository{db: db}
+}
+
+// Create creates a new known device
+func (r *PostgresKnownDeviceRepository) Create(ctx context.Context, device *entity.KnownDevice) error {
+	query := `
+		INSERT INTO known_devices (id, user_id, fingerprint, user_agent, ip_address, country, latitude, longitude, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	country, latitude, longitude := locationColumns(device.Location)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		device.ID,
+		device.UserID,
+		device.Fingerprint,
+		device.UserAgent,
+		device.IPAddress,
+		country,
+		latitude,
+		longitude,
+		device.FirstSeenAt,
+		device.LastSeenAt,
+	)
+
+	return err
+}
+
+// FindByFingerprint finds a device of a user by its fingerprint
+func (r *PostgresKnownDeviceRepository) FindByFingerprint(ctx context.Context, userID uuid.UUID, fingerprint string) (*entity.KnownDevice, error) {
+	query := `SELECT ` + knownDeviceColumns + ` FROM known_devices WHERE user_id = $1 AND fingerprint = $2`
+	return r.findOne(ctx, query, userID, fingerprint)
+}
+
+// FindLastSeenByUserID finds the device the user signed in from most recently
+func (r *PostgresKnownDeviceRepository) FindLastSeenByUserID(ctx context.Context, userID uuid.UUID) (*entity.KnownDevice, error) {
+	query := `SELECT ` + knownDeviceColumns + ` FROM known_devices WHERE user_id = $1 ORDER BY last_seen_at DESC LIMIT 1`
+	return r.findOne(ctx, query, userID)
+}
+
+// Update updates a known device
+func (r *PostgresKnownDeviceRepository) Update(ctx context.Context, device *entity.KnownDevice) error {
+	query := `
+		UPDATE known_devices
+		SET user_agent = $2, ip_address = $3, country = $4, latitude = $5, longitude = $6, last_seen_at = $7
+		WHERE id = $1
+	`
+
+	country, latitude, longitude := locationColumns(device.Location)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		device.ID,
+		device.UserAgent,
+		device.IPAddress,
+		country,
+		latitude,
+		longitude,
+		device.LastSeenAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apperrors.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
+// findOne runs a query selecting knownDeviceColumns of at most one device
+func (r *PostgresKnownDeviceRepository) findOne(ctx context.Context, query string, args ...interface{}) (*entity.KnownDevice, error) {
+	device := &entity.KnownDevice{}
+	var country sql.NullString
+	var latitude, longitude sql.NullFloat64
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
+		&device.ID,
+		&device.UserID,
+		&device.Fingerprint,
+		&device.UserAgent,
+		&device.IPAddress,
+		&country,
+		&latitude,
+		&longitude,
+		&device.FirstSeenAt,
+		&device.LastSeenAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrDeviceNotFound
+		}
+		return nil, err
+	}
+
+	if latitude.Valid && longitude.Valid {
+		device.Location = &entity.GeoLocation{
+			Country:   country.String,
+			Latitude:  latitude.Float64,
+			Longitude: longitude.Float64,
+		}
+	}
+
+	return device, nil
+}
+
+// locationColumns returns the nullable column values of a location
+func locationColumns(location *entity.GeoLocation) (sql.NullString, sql.NullFloat64, sql.NullFloat64) {
+	if location == nil {
+		return sql.NullString{}, sql.NullFloat64{}, sql.NullFloat64{}
+	}
+	return sql.NullString{String: location.Country, Valid: location.Country != ""},
+		sql.NullFloat64{Float64: location.Latitude, Valid: true},
+		sql.NullFloat64{Float64: location.Longitude, Valid: true}
+}
@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+)
+
+// PostgresLoginAttemptRepository implements LoginAttemptRepository using PostgreSQL
+type PostgresLoginAttemptRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresLoginAttemptRepository creates a new PostgreSQL login attempt repository
+func NewPostgresLoginAttemptRepository(db *sql.DB) repository.LoginAttemptRepository {
+	return &PostgresLoginAttemptRepository{db: db}
+}
+
+// FindByKey finds the attempts for a key; an empty record is returned for unknown keys
+func (r *PostgresLoginAttemptRepository) FindByKey(ctx context.Context, key string) (*entity.LoginAttempt, error) {
+	query := `
+		SELECT key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE key = $1
+	`
+
+	attempt, err := scanLoginAttempt(conn(ctx, r.db).QueryRowContext(ctx, query, key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &entity.LoginAttempt{Key: key}, nil
+		}
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// RecordFailure atomically increments the failure count of a key
+func (r *PostgresLoginAttemptRepository) RecordFailure(ctx context.Context, key string, resetAfter time.Duration) (*entity.LoginAttempt, error) {
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE
+				WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => $2) THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = NOW()
+		RETURNING key, failures, last_failure_at, locked_until
+	`
+
+	return scanLoginAttempt(conn(ctx, r.db).QueryRowContext(ctx, query, key, resetAfter.Seconds()))
+}
+
+// Lock blocks attempts for a key until the given time
+func (r *PostgresLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `
+		UPDATE login_attempts
+		SET locked_until = $2
+		WHERE key = $1
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, key, until)
+	return err
+}
+
+// Reset clears the failures and lock of a key
+func (r *PostgresLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	query := `DELETE FROM login_attempts WHERE key = $1`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, key)
+	return err
+}
+
+// scanLoginAttempt scans a login attempt row
+func scanLoginAttempt(row *sql.Row) (*entity.LoginAttempt, error) {
+	attempt := &entity.LoginAttempt{}
+	var lastFailureAt, lockedUntil sql.NullTime
+
+	if err := row.Scan(&attempt.Key, &attempt.Failures, &lastFailureAt, &lockedUntil); err != nil {
+		return nil, err
+	}
+
+	if lastFailureAt.Valid {
+		attempt.LastFailureAt = &lastFailureAt.Time
+	}
+
+	if lockedUntil.Valid {
+		attempt.LockedUntil = &lockedUntil.Time
+	}
+
+	return attempt, nil
+}
@@ -0,0 +1,147 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// PostgresOutboxRepository implements OutboxRepository using PostgreSQL
+type PostgresOutboxRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresOutboxRepository creates a new PostgreSQL outbox repository
+func NewPostgresOutboxRepository(db *sql.DB) repository.OutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+// ClaimDue locks due events with SKIP LOCKED, so concurrent relays never claim the same
+// event, and pushes their next attempt past the lease while they are being published
+func (r *PostgresOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*repository.OutboxEntry, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE outbox_events
+			SET next_attempt_at = NOW() + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id FROM outbox_events
+				WHERE published_at IS NULL AND next_attempt_at <= NOW()
+				ORDER BY sequence
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, sequence, aggregate_type, aggregate_id, event_type, payload, occurred_at, attempts
+		)
+		SELECT id, aggregate_type, aggregate_id, event_type, payload, occurred_at, attempts
+		FROM claimed
+		ORDER BY sequence
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			// Log error but don't fail the operation
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var entries []*repository.OutboxEntry
+	for rows.Next() {
+		entry := &repository.OutboxEntry{}
+		var eventType string
+		var payload []byte
+
+		err := rows.Scan(
+			&entry.Event.ID,
+			&entry.Event.AggregateType,
+			&entry.Event.AggregateID,
+			&eventType,
+			&payload,
+			&entry.Event.OccurredAt,
+			&entry.Attempts,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Event.Type = entity.DomainEventType(eventType)
+		if err := json.Unmarshal(payload, &entry.Event.Payload); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// MarkPublished marks an event as published to every sink
+func (r *PostgresOutboxRepository) MarkPublished(ctx context.Context, eventID uuid.UUID) error {
+	query := `UPDATE outbox_events SET published_at = NOW(), last_error = '' WHERE id = $1`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, eventID)
+	return err
+}
+
+// MarkFailed records a failed publish attempt and schedules the next one
+func (r *PostgresOutboxRepository) MarkFailed(ctx context.Context, eventID uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, eventID, reason, nextAttemptAt)
+	return err
+}
+
+// DeletePublishedBefore removes events published before the given time
+func (r *PostgresOutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM outbox_events WHERE published_at < $1`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// saveEvents adds domain events to the outbox as part of the transaction saving their aggregate
+func saveEvents(ctx context.Context, tx *sql.Tx, events []entity.DomainEvent) error {
+	query := `
+		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for _, event := range events {
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query,
+			event.ID,
+			event.AggregateType,
+			event.AggregateID,
+			string(event.Type),
+			payload,
+			event.OccurredAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
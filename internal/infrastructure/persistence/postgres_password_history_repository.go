@@ -0,0 +1,82 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// PostgresPasswordHistoryRepository implements PasswordHistoryRepository using PostgreSQL
+type PostgresPasswordHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresPasswordHistoryRepository creates a new PostgreSQL password history repository
+func NewPostgresPasswordHistoryRepository(db *sql.DB) repository.PasswordHistoryRepository {
+	return &PostgresPasswordHistoryRepository{db: db}
+}
+
+// Create creates a new password history entry
+func (r *PostgresPasswordHistoryRepository) Create(ctx context.Context, entry *entity.PasswordHistoryEntry) error {
+	query := `
+		INSERT INTO password_history (id, user_id, password_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, entry.ID, entry.UserID, entry.PasswordHash, entry.CreatedAt)
+	return err
+}
+
+// FindRecentByUserID finds the most recent entries for a user, newest first
+func (r *PostgresPasswordHistoryRepository) FindRecentByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*entity.PasswordHistoryEntry, error) {
+	query := `
+		SELECT id, user_id, password_hash, created_at
+		FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			// Log error but don't fail the operation
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var entries []*entity.PasswordHistoryEntry
+	for rows.Next() {
+		entry := &entity.PasswordHistoryEntry{}
+		if err := rows.Scan(&entry.ID, &entry.UserID, &entry.PasswordHash, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// Prune deletes all but the most recent entries for a user
+func (r *PostgresPasswordHistoryRepository) Prune(ctx context.Context, userID uuid.UUID, keep int) error {
+	query := `
+		DELETE FROM password_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM password_history
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, keep)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"time"
 
 	"auth-go/internal/domain/entity"
 	"auth-go/internal/domain/repository"
@@ -26,46 +27,56 @@ func NewPostgresRefreshTokenRepository(db *sql.DB) repository.RefreshTokenReposi
 // Create creates a new refresh token
 func (r *PostgresRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, user_id, token, expires_at, created_at, is_revoked, token_family, parent_token)
+		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, created_at, is_revoked, token_family, parent_id)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		token.ID,
-		token.UserID,
-		token.Token,
-		token.ExpiresAt,
-		token.CreatedAt,
-		token.IsRevoked,
-		token.TokenFamily,
-		token.ParentToken,
-	)
+	return inTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			token.ID,
+			token.UserID,
+			token.TokenHash,
+			token.ExpiresAt,
+			token.CreatedAt,
+			token.IsRevoked,
+			token.TokenFamily,
+			token.ParentID,
+		)
+		if err != nil {
+			return err
+		}
 
-	return err
+		return saveEvents(ctx, tx, token.PullEvents())
+	})
 }
 
-// FindByToken finds a refresh token by token string
-func (r *PostgresRefreshTokenRepository) FindByToken(ctx context.Context, tokenStr string) (*entity.RefreshToken, error) {
+// FindByTokenHash finds a refresh token by the hash of its value
+func (r *PostgresRefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token, expires_at, created_at, is_revoked, revoked_at, token_family, parent_token
+		SELECT id, user_id, token_hash, expires_at, created_at, is_revoked, revoked_at, token_family, parent_id
 		FROM refresh_tokens
-		WHERE token = $1
+		WHERE token_hash = $1
 	`
 
+	return r.findOne(ctx, query, tokenHash)
+}
+
+// findOne runs a single-row refresh token query
+func (r *PostgresRefreshTokenRepository) findOne(ctx context.Context, query string, args ...interface{}) (*entity.RefreshToken, error) {
 	token := &entity.RefreshToken{}
 	var revokedAt sql.NullTime
-	var parentToken sql.NullString
+	var parentID uuid.NullUUID
 
-	err := r.db.QueryRowContext(ctx, query, tokenStr).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
 		&token.ID,
 		&token.UserID,
-		&token.Token,
+		&token.TokenHash,
 		&token.ExpiresAt,
 		&token.CreatedAt,
 		&token.IsRevoked,
 		&revokedAt,
 		&token.TokenFamily,
-		&parentToken,
+		&parentID,
 	)
 
 	if err != nil {
@@ -79,8 +90,8 @@ func (r *PostgresRefreshTokenRepository) FindByToken(ctx context.Context, tokenS
 		token.RevokedAt = &revokedAt.Time
 	}
 
-	if parentToken.Valid {
-		token.ParentToken = &parentToken.String
+	if parentID.Valid {
+		token.ParentID = &parentID.UUID
 	}
 
 	return token, nil
@@ -89,13 +100,13 @@ func (r *PostgresRefreshTokenRepository) FindByToken(ctx context.Context, tokenS
 // FindByUserID finds all refresh tokens for a user
 func (r *PostgresRefreshTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token, expires_at, created_at, is_revoked, revoked_at, token_family, parent_token
+		SELECT id, user_id, token_hash, expires_at, created_at, is_revoked, revoked_at, token_family, parent_id
 		FROM refresh_tokens
 		WHERE user_id = $1
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -110,18 +121,18 @@ func (r *PostgresRefreshTokenRepository) FindByUserID(ctx context.Context, userI
 	for rows.Next() {
 		token := &entity.RefreshToken{}
 		var revokedAt sql.NullTime
-		var parentToken sql.NullString
+		var parentID uuid.NullUUID
 
 		err := rows.Scan(
 			&token.ID,
 			&token.UserID,
-			&token.Token,
+			&token.TokenHash,
 			&token.ExpiresAt,
 			&token.CreatedAt,
 			&token.IsRevoked,
 			&revokedAt,
 			&token.TokenFamily,
-			&parentToken,
+			&parentID,
 		)
 		if err != nil {
 			return nil, err
@@ -131,8 +142,8 @@ func (r *PostgresRefreshTokenRepository) FindByUserID(ctx context.Context, userI
 			token.RevokedAt = &revokedAt.Time
 		}
 
-		if parentToken.Valid {
-			token.ParentToken = &parentToken.String
+		if parentID.Valid {
+			token.ParentID = &parentID.UUID
 		}
 
 		tokens = append(tokens, token)
@@ -149,38 +160,100 @@ func (r *PostgresRefreshTokenRepository) Update(ctx context.Context, token *enti
 		WHERE id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query, token.ID, token.IsRevoked, token.RevokedAt)
-	return err
+	return inTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, token.ID, token.IsRevoked, token.RevokedAt); err != nil {
+			return err
+		}
+
+		return saveEvents(ctx, tx, token.PullEvents())
+	})
 }
 
-// RevokeByTokenFamily revokes all tokens in a token family
-func (r *PostgresRefreshTokenRepository) RevokeByTokenFamily(ctx context.Context, tokenFamily uuid.UUID) error {
+// Rotate revokes a token with a conditional update, so only one concurrent rotation wins
+func (r *PostgresRefreshTokenRepository) Rotate(ctx context.Context, token *entity.RefreshToken) error {
 	query := `
 		UPDATE refresh_tokens
-		SET is_revoked = true, revoked_at = NOW()
-		WHERE token_family = $1 AND is_revoked = false
+		SET is_revoked = true, revoked_at = $2
+		WHERE id = $1 AND is_revoked = false
+		RETURNING revoked_at
 	`
 
-	_, err := r.db.ExecContext(ctx, query, tokenFamily)
-	return err
+	return inTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		var revokedAt time.Time
+		if err := tx.QueryRowContext(ctx, query, token.ID, token.RevokedAt).Scan(&revokedAt); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apperrors.ErrTokenRevoked
+			}
+			return err
+		}
+		token.RevokedAt = &revokedAt
+
+		return saveEvents(ctx, tx, token.PullEvents())
+	})
+}
+
+// RevokeByTokenFamily revokes all tokens in a token family
+func (r *PostgresRefreshTokenRepository) RevokeByTokenFamily(ctx context.Context, tokenFamily uuid.UUID) error {
+	return r.revokeWhere(ctx, `token_family = $1`, tokenFamily)
 }
 
 // RevokeByUserID revokes all tokens for a user
 func (r *PostgresRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.revokeWhere(ctx, `user_id = $1`, userID)
+}
+
+// RevokeByUserIDExceptFamily revokes all tokens for a user except those of one token family
+func (r *PostgresRefreshTokenRepository) RevokeByUserIDExceptFamily(ctx context.Context, userID uuid.UUID, keepFamily uuid.UUID) error {
+	return r.revokeWhere(ctx, `user_id = $1 AND token_family <> $2`, userID, keepFamily)
+}
+
+// revokeWhere revokes the active tokens matching condition and saves their revoked
+// events in the same transaction
+func (r *PostgresRefreshTokenRepository) revokeWhere(ctx context.Context, condition string, args ...interface{}) error {
 	query := `
 		UPDATE refresh_tokens
 		SET is_revoked = true, revoked_at = NOW()
-		WHERE user_id = $1 AND is_revoked = false
+		WHERE is_revoked = false AND ` + condition + `
+		RETURNING id, user_id, token_family
 	`
 
-	_, err := r.db.ExecContext(ctx, query, userID)
-	return err
+	return inTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := rows.Close(); err != nil {
+				// Log error but don't fail the operation
+				log.Printf("Error closing rows: %v", err)
+			}
+		}()
+
+		var events []entity.DomainEvent
+		for rows.Next() {
+			token := &entity.RefreshToken{}
+			if err := rows.Scan(&token.ID, &token.UserID, &token.TokenFamily); err != nil {
+				return err
+			}
+			token.Revoke()
+			events = append(events, token.PullEvents()...)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		// The connection has to be free again before the events are inserted
+		if err := rows.Close(); err != nil {
+			return err
+		}
+
+		return saveEvents(ctx, tx, events)
+	})
 }
 
 // DeleteExpired deletes all expired tokens
 func (r *PostgresRefreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	query := `DELETE FROM refresh_tokens WHERE expires_at < NOW()`
 
-	_, err := r.db.ExecContext(ctx, query)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query)
 	return err
 }
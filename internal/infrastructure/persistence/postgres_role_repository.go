@@ -0,0 +1,205 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+const roleColumns = `name, description, permissions, parent, created_at, updated_at`
+
+// PostgresRoleRepository implements RoleRepository using PostgreSQL
+type PostgresRoleRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRoleRepository creates a new PostgreSQL role repository
+func NewPostgresRoleRepository(db *sql.DB) repository.RoleRepository {
+	return &PostgresRoleRepository{db: db}
+}
+
+// Create creates a new role
+func (r *PostgresRoleRepository) Create(ctx context.Context, role *entity.RoleDefinition) error {
+	query := `
+		INSERT INTO roles (name, description, permissions, parent, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		role.Name,
+		role.Description,
+		pq.Array(permissionNames(role.Permissions)),
+		role.Parent,
+		role.CreatedAt,
+		role.UpdatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return apperrors.ErrRoleAlreadyExists
+		}
+		if isForeignKeyViolation(err) {
+			return apperrors.ErrInvalidParentRole
+		}
+		return err
+	}
+
+	return nil
+}
+
+// FindByName finds a role by name
+func (r *PostgresRoleRepository) FindByName(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error) {
+	query := fmt.Sprintf(`SELECT %s FROM roles WHERE name = $1`, roleColumns)
+
+	roles, err := r.query(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, apperrors.ErrRoleNotFound
+	}
+
+	return roles[0], nil
+}
+
+// FindAll lists every role, ordered by name
+func (r *PostgresRoleRepository) FindAll(ctx context.Context) ([]*entity.RoleDefinition, error) {
+	query := fmt.Sprintf(`SELECT %s FROM roles ORDER BY name`, roleColumns)
+
+	return r.query(ctx, query)
+}
+
+// Update updates a role
+func (r *PostgresRoleRepository) Update(ctx context.Context, role *entity.RoleDefinition) error {
+	query := `
+		UPDATE roles
+		SET description = $2, permissions = $3, parent = $4, updated_at = $5
+		WHERE name = $1
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		role.Name,
+		role.Description,
+		pq.Array(permissionNames(role.Permissions)),
+		role.Parent,
+		role.UpdatedAt,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperrors.ErrInvalidParentRole
+		}
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return apperrors.ErrRoleNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a role that no user holds and no role inherits from.
+// The row is deleted before looking for holders, so an assignment racing with the
+// delete either commits first and is found, or fails on the missing role.
+func (r *PostgresRoleRepository) Delete(ctx context.Context, name entity.Role) error {
+	return inTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM roles WHERE name = $1`, name)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return apperrors.ErrRoleInUse
+			}
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return apperrors.ErrRoleNotFound
+		}
+
+		var held bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE $1 = ANY(roles))`, name).Scan(&held); err != nil {
+			return err
+		}
+		if held {
+			return apperrors.ErrRoleInUse
+		}
+
+		return nil
+	})
+}
+
+func (r *PostgresRoleRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.RoleDefinition, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			// Log error but don't fail the operation
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var roles []*entity.RoleDefinition
+	for rows.Next() {
+		role := &entity.RoleDefinition{}
+		var permissions pq.StringArray
+		var parent sql.NullString
+
+		err := rows.Scan(
+			&role.Name,
+			&role.Description,
+			&permissions,
+			&parent,
+			&role.CreatedAt,
+			&role.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		role.Permissions = make([]entity.Permission, len(permissions))
+		for i, permission := range permissions {
+			role.Permissions[i] = entity.Permission(permission)
+		}
+
+		if parent.Valid {
+			parentRole := entity.Role(parent.String)
+			role.Parent = &parentRole
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+func permissionNames(permissions []entity.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return names
+}
+
+// isForeignKeyViolation reports whether err is a PostgreSQL foreign key violation
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
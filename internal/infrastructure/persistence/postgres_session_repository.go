@@ -0,0 +1,178 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// sessionColumns lists the columns scanned by scanSession
+const sessionColumns = `id, user_id, client_id, device_name, user_agent, ip_address, created_at, last_used_at, auth_time, acr, amr`
+
+// PostgresSessionRepository implements SessionRepository using PostgreSQL
+type PostgresSessionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSessionRepository creates a new PostgreSQL session repository
+func NewPostgresSessionRepository(db *sql.DB) repository.SessionRepository {
+	return &PostgresSessionRepository{db: db}
+}
+
+// Create creates a new session
+func (r *PostgresSessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, client_id, device_name, user_agent, ip_address, created_at, last_used_at, auth_time, acr, amr)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		session.ID,
+		session.UserID,
+		session.ClientID,
+		session.DeviceName,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastUsedAt,
+		session.AuthTime,
+		session.ACR,
+		pq.Array(session.AMR),
+	)
+
+	return err
+}
+
+// FindByID finds a session by ID (token family)
+func (r *PostgresSessionRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+
+	session, err := scanSession(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// FindActiveByUserID lists the sessions of a user that still hold a valid refresh token
+func (r *PostgresSessionRepository) FindActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions s
+		WHERE s.user_id = $1
+		  AND EXISTS (
+			SELECT 1 FROM refresh_tokens rt
+			WHERE rt.token_family = s.id AND rt.is_revoked = false AND rt.expires_at > NOW()
+		  )
+		ORDER BY s.last_used_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var sessions []*entity.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// Update updates a session
+func (r *PostgresSessionRepository) Update(ctx context.Context, session *entity.Session) error {
+	query := `
+		UPDATE sessions
+		SET device_name = $2, user_agent = $3, ip_address = $4, last_used_at = $5,
+		    auth_time = $6, acr = $7, amr = $8
+		WHERE id = $1
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		session.ID,
+		session.DeviceName,
+		session.UserAgent,
+		session.IPAddress,
+		session.LastUsedAt,
+		session.AuthTime,
+		session.ACR,
+		pq.Array(session.AMR),
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apperrors.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// LockUserSessions takes a transaction-level advisory lock on the sessions of a user
+func (r *PostgresSessionRepository) LockUserSessions(ctx context.Context, userID uuid.UUID) error {
+	return inTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('sessions'), hashtext($1))`, userID.String())
+		return err
+	})
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSession scans a session selected with sessionColumns
+func scanSession(row rowScanner) (*entity.Session, error) {
+	session := &entity.Session{}
+	var deviceName sql.NullString
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.ClientID,
+		&deviceName,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.AuthTime,
+		&session.ACR,
+		pq.Array(&session.AMR),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deviceName.Valid {
+		session.DeviceName = &deviceName.String
+	}
+
+	return session, nil
+}
@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"auth-go/internal/domain/repository"
+)
+
+// txKey is the context key holding the ambient transaction
+type txKey struct{}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// PostgresTxManager implements TxManager by storing a *sql.Tx in the context
+type PostgresTxManager struct {
+	db *sql.DB
+}
+
+// NewPostgresTxManager creates a new PostgreSQL transaction manager
+func NewPostgresTxManager(db *sql.DB) repository.TxManager {
+	return &PostgresTxManager{db: db}
+}
+
+// WithinTransaction runs fn in a transaction shared by every repository using its context
+func (m *PostgresTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTransaction(ctx, m.db, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// txFromContext returns the ambient transaction, if any
+func txFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// conn returns the ambient transaction, or db when there is none
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTransaction runs fn in a database transaction, committing if it returns nil.
+// If ctx already carries a transaction fn joins it, and the outermost caller commits.
+func inTransaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// No-op once committed
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -3,9 +3,13 @@ package persistence
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"auth-go/internal/domain/entity"
 	"auth-go/internal/domain/repository"
@@ -37,20 +41,31 @@ func (r *PostgresUserRepository) Create(ctx context.Context, user *entity.User)
 		roles[i] = role.String()
 	}
 
-	_, err := r.db.ExecContext(ctx, query,
-		user.ID,
-		user.Email,
-		user.PasswordHash,
-		pq.Array(roles),
-		user.IsActive,
-		user.CreatedAt,
-		user.UpdatedAt,
-	)
+	err := inTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			user.ID,
+			user.Email,
+			user.PasswordHash,
+			pq.Array(roles),
+			user.IsActive,
+			user.CreatedAt,
+			user.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		return saveEvents(ctx, tx, user.PullEvents())
+	})
 
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
 			return apperrors.ErrUserAlreadyExists
 		}
+		// Every role must be defined (see migration 018)
+		if isForeignKeyViolation(err) {
+			return apperrors.ErrRoleNotFound
+		}
 		return err
 	}
 
@@ -69,7 +84,7 @@ func (r *PostgresUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*e
 	var roles pq.StringArray
 	var lastLoginAt sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
@@ -89,7 +104,7 @@ func (r *PostgresUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*e
 
 	user.Roles = make([]entity.Role, len(roles))
 	for i, role := range roles {
-		user.Roles[i] = entity.ParseRole(role)
+		user.Roles[i] = entity.Role(role)
 	}
 
 	if lastLoginAt.Valid {
@@ -111,7 +126,7 @@ func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string)
 	var roles pq.StringArray
 	var lastLoginAt sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, email).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
@@ -131,7 +146,7 @@ func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string)
 
 	user.Roles = make([]entity.Role, len(roles))
 	for i, role := range roles {
-		user.Roles[i] = entity.ParseRole(role)
+		user.Roles[i] = entity.Role(role)
 	}
 
 	if lastLoginAt.Valid {
@@ -154,29 +169,43 @@ func (r *PostgresUserRepository) Update(ctx context.Context, user *entity.User)
 		roles[i] = role.String()
 	}
 
-	result, err := r.db.ExecContext(ctx, query,
-		user.ID,
-		user.Email,
-		user.PasswordHash,
-		pq.Array(roles),
-		user.IsActive,
-		user.UpdatedAt,
-		user.LastLoginAt,
-	)
+	err := inTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query,
+			user.ID,
+			user.Email,
+			user.PasswordHash,
+			pq.Array(roles),
+			user.IsActive,
+			user.UpdatedAt,
+			user.LastLoginAt,
+		)
+		if err != nil {
+			return err
+		}
 
-	if err != nil {
-		return err
-	}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rows == 0 {
+			return apperrors.ErrUserNotFound
+		}
+
+		return saveEvents(ctx, tx, user.PullEvents())
+	})
 
-	rows, err := result.RowsAffected()
 	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return apperrors.ErrUserAlreadyExists
+		}
+		// Every role must be defined (see migration 018)
+		if isForeignKeyViolation(err) {
+			return apperrors.ErrRoleNotFound
+		}
 		return err
 	}
 
-	if rows == 0 {
-		return apperrors.ErrUserNotFound
-	}
-
 	return nil
 }
 
@@ -184,7 +213,7 @@ func (r *PostgresUserRepository) Update(ctx context.Context, user *entity.User)
 func (r *PostgresUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM users WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -206,19 +235,92 @@ func (r *PostgresUserRepository) ExistsByEmail(ctx context.Context, email string
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&exists)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, email).Scan(&exists)
 	return exists, err
 }
 
-// FindAll finds all users
-func (r *PostgresUserRepository) FindAll(ctx context.Context) ([]*entity.User, error) {
-	query := `
+// Search finds a page of users matching the query using keyset pagination
+func (r *PostgresUserRepository) Search(ctx context.Context, q repository.UserQuery) (*repository.UserPage, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if q.EmailPrefix != "" {
+		conditions = append(conditions, "email LIKE "+arg(escapeLike(strings.ToLower(q.EmailPrefix))+"%"))
+	}
+	if q.Role != nil {
+		conditions = append(conditions, arg(q.Role.String())+" = ANY(roles)")
+	}
+	if q.IsActive != nil {
+		conditions = append(conditions, "is_active = "+arg(*q.IsActive))
+	}
+	if q.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= "+arg(*q.CreatedAfter))
+	}
+	if q.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < "+arg(*q.CreatedBefore))
+	}
+	if q.LastLoginAfter != nil {
+		conditions = append(conditions, "last_login_at >= "+arg(*q.LastLoginAfter))
+	}
+	if q.LastLoginBefore != nil {
+		conditions = append(conditions, "last_login_at < "+arg(*q.LastLoginBefore))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Total count ignores the cursor so it is stable across pages
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM users "+where, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	sortExpr, ok := userSortExpressions[q.SortBy]
+	if !ok {
+		return nil, apperrors.ErrInvalidInput
+	}
+	direction, comparison := "ASC", ">"
+	if q.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if q.Cursor != "" {
+		cursor, err := decodeUserCursor(q.Cursor, q.SortBy, q.SortDesc)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{} = cursor.Value
+		if q.SortBy != repository.UserSortEmail {
+			t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+			if err != nil {
+				return nil, apperrors.ErrInvalidInput
+			}
+			value = t
+		}
+		keyset := fmt.Sprintf("(%s, id) %s (%s, %s)", sortExpr, comparison, arg(value), arg(cursor.ID))
+		if where == "" {
+			where = "WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	query := fmt.Sprintf(`
 		SELECT id, email, password_hash, roles, is_active, created_at, updated_at, last_login_at
 		FROM users
-		ORDER BY created_at DESC
-	`
+		%s
+		ORDER BY %s %s, id %s
+		LIMIT %s
+	`, where, sortExpr, direction, direction, arg(q.Limit+1))
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -229,7 +331,7 @@ func (r *PostgresUserRepository) FindAll(ctx context.Context) ([]*entity.User, e
 		}
 	}()
 
-	var users []*entity.User
+	users := make([]*entity.User, 0, q.Limit)
 	for rows.Next() {
 		user := &entity.User{}
 		var roles pq.StringArray
@@ -251,7 +353,7 @@ func (r *PostgresUserRepository) FindAll(ctx context.Context) ([]*entity.User, e
 
 		user.Roles = make([]entity.Role, len(roles))
 		for i, role := range roles {
-			user.Roles[i] = entity.ParseRole(role)
+			user.Roles[i] = entity.Role(role)
 		}
 
 		if lastLoginAt.Valid {
@@ -260,6 +362,73 @@ func (r *PostgresUserRepository) FindAll(ctx context.Context) ([]*entity.User, e
 
 		users = append(users, user)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &repository.UserPage{Users: users, Total: total}
+	if len(users) > q.Limit {
+		page.Users = users[:q.Limit]
+		page.NextCursor = encodeUserCursor(page.Users[q.Limit-1], q.SortBy, q.SortDesc)
+	}
+
+	return page, nil
+}
+
+// userSortExpressions maps sort fields to SQL; users who never logged in sort first
+var userSortExpressions = map[repository.UserSortField]string{
+	repository.UserSortCreatedAt:   "created_at",
+	repository.UserSortEmail:       "email",
+	repository.UserSortLastLoginAt: "COALESCE(last_login_at, 'epoch'::timestamp)",
+}
+
+// userCursor is the position after the last user of a page
+type userCursor struct {
+	SortBy repository.UserSortField `json:"s"`
+	Desc   bool                     `json:"d"`
+	Value  string                   `json:"v"`
+	ID     uuid.UUID                `json:"id"`
+}
+
+// encodeUserCursor builds the opaque cursor pointing after the given user
+func encodeUserCursor(user *entity.User, sortBy repository.UserSortField, desc bool) string {
+	cursor := userCursor{SortBy: sortBy, Desc: desc, ID: user.ID}
+	switch sortBy {
+	case repository.UserSortEmail:
+		cursor.Value = user.Email
+	case repository.UserSortLastLoginAt:
+		lastLoginAt := time.Unix(0, 0).UTC()
+		if user.LastLoginAt != nil {
+			lastLoginAt = *user.LastLoginAt
+		}
+		cursor.Value = lastLoginAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = user.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeUserCursor parses a cursor and checks it was issued for the same sort order
+func decodeUserCursor(value string, sortBy repository.UserSortField, desc bool) (*userCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	var cursor userCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, apperrors.ErrInvalidInput
+	}
+	if cursor.SortBy != sortBy || cursor.Desc != desc {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	return &cursor, nil
+}
 
-	return users, rows.Err()
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
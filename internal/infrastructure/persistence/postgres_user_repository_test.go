@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestUserCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	lastLoginAt := time.Date(2024, 3, 2, 8, 0, 0, 42, time.UTC)
+	user := &entity.User{ID: uuid.New(), Email: "ada@example.com", CreatedAt: createdAt, LastLoginAt: &lastLoginAt}
+	neverLoggedIn := &entity.User{ID: uuid.New(), Email: "bob@example.com", CreatedAt: createdAt}
+
+	tests := []struct {
+		name      string
+		user      *entity.User
+		sortBy    repository.UserSortField
+		desc      bool
+		wantValue string
+	}{
+		{name: "created_at", user: user, sortBy: repository.UserSortCreatedAt, wantValue: createdAt.Format(time.RFC3339Nano)},
+		{name: "created_at descending", user: user, sortBy: repository.UserSortCreatedAt, desc: true, wantValue: createdAt.Format(time.RFC3339Nano)},
+		{name: "email", user: user, sortBy: repository.UserSortEmail, wantValue: "ada@example.com"},
+		{name: "last_login_at", user: user, sortBy: repository.UserSortLastLoginAt, wantValue: lastLoginAt.Format(time.RFC3339Nano)},
+		{name: "last_login_at never logged in sorts at the epoch", user: neverLoggedIn, sortBy: repository.UserSortLastLoginAt, wantValue: "1970-01-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := encodeUserCursor(tt.user, tt.sortBy, tt.desc)
+
+			cursor, err := decodeUserCursor(encoded, tt.sortBy, tt.desc)
+			if err != nil {
+				t.Fatalf("decodeUserCursor() error = %v", err)
+			}
+			if cursor.ID != tt.user.ID {
+				t.Errorf("ID = %v, want %v", cursor.ID, tt.user.ID)
+			}
+			if cursor.Value != tt.wantValue {
+				t.Errorf("Value = %q, want %q", cursor.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestDecodeUserCursor_Invalid(t *testing.T) {
+	user := &entity.User{ID: uuid.New(), Email: "ada@example.com", CreatedAt: time.Now()}
+	valid := encodeUserCursor(user, repository.UserSortEmail, false)
+
+	tests := []struct {
+		name   string
+		value  string
+		sortBy repository.UserSortField
+		desc   bool
+	}{
+		{name: "not base64", value: "!!!", sortBy: repository.UserSortEmail},
+		{name: "not JSON", value: base64.RawURLEncoding.EncodeToString([]byte("nope")), sortBy: repository.UserSortEmail},
+		{name: "bad ID", value: base64.RawURLEncoding.EncodeToString([]byte(`{"s":"email","id":"x"}`)), sortBy: repository.UserSortEmail},
+		{name: "other sort field", value: valid, sortBy: repository.UserSortCreatedAt},
+		{name: "other direction", value: valid, sortBy: repository.UserSortEmail, desc: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeUserCursor(tt.value, tt.sortBy, tt.desc); !errors.Is(err, apperrors.ErrInvalidInput) {
+				t.Errorf("decodeUserCursor() error = %v, want %v", err, apperrors.ErrInvalidInput)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "ada", want: "ada"},
+		{in: "100%", want: `100\%`},
+		{in: "a_b", want: `a\_b`},
+		{in: `c:\path`, want: `c:\\path`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLike(tt.in); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
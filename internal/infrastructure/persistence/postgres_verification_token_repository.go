@@ -0,0 +1,139 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// PostgresVerificationTokenRepository implements VerificationTokenRepository using PostgreSQL
+type PostgresVerificationTokenRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresVerificationTokenRepository creates a new PostgreSQL verification token repository
+func NewPostgresVerificationTokenRepository(db *sql.DB) repository.VerificationTokenRepository {
+	return &PostgresVerificationTokenRepository{db: db}
+}
+
+// Create creates a new verification token
+func (r *PostgresVerificationTokenRepository) Create(ctx context.Context, token *entity.VerificationToken) error {
+	query := `
+		INSERT INTO verification_tokens (id, user_id, purpose, token_hash, new_email, session_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		token.ID,
+		token.UserID,
+		string(token.Purpose),
+		token.TokenHash,
+		token.NewEmail,
+		token.SessionID,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByTokenHash finds a verification token by the hash of its value
+func (r *PostgresVerificationTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.VerificationToken, error) {
+	query := `
+		SELECT id, user_id, purpose, token_hash, new_email, session_id, expires_at, created_at, used_at
+		FROM verification_tokens
+		WHERE token_hash = $1
+	`
+
+	token := &entity.VerificationToken{}
+	var purpose string
+	var newEmail sql.NullString
+	var sessionID uuid.NullUUID
+	var usedAt sql.NullTime
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&purpose,
+		&token.TokenHash,
+		&newEmail,
+		&sessionID,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&usedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	token.Purpose = entity.TokenPurpose(purpose)
+
+	if newEmail.Valid {
+		token.NewEmail = &newEmail.String
+	}
+
+	if sessionID.Valid {
+		token.SessionID = &sessionID.UUID
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return token, nil
+}
+
+// Consume marks a token as used; it fails if the token was already used
+func (r *PostgresVerificationTokenRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE verification_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return apperrors.ErrInvalidToken
+	}
+
+	return nil
+}
+
+// InvalidateByUserID marks all outstanding tokens of a purpose for a user as used
+func (r *PostgresVerificationTokenRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID, purpose entity.TokenPurpose) error {
+	query := `
+		UPDATE verification_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, string(purpose))
+	return err
+}
+
+// DeleteExpired deletes all expired tokens
+func (r *PostgresVerificationTokenRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM verification_tokens WHERE expires_at < NOW()`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query)
+	return err
+}
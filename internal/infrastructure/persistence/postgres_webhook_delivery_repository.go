@@ -0,0 +1,211 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_attempt_at, last_status_code, last_error, delivered_at, created_at`
+
+// PostgresWebhookDeliveryRepository implements WebhookDeliveryRepository using PostgreSQL
+type PostgresWebhookDeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresWebhookDeliveryRepository creates a new PostgreSQL webhook delivery repository
+func NewPostgresWebhookDeliveryRepository(db *sql.DB) repository.WebhookDeliveryRepository {
+	return &PostgresWebhookDeliveryRepository{db: db}
+}
+
+// Create queues a new delivery; an event already queued for the endpoint is ignored
+func (r *PostgresWebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		delivery.ID,
+		delivery.EndpointID,
+		delivery.EventID,
+		string(delivery.EventType),
+		delivery.Payload,
+		string(delivery.Status),
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByID finds a delivery by ID
+func (r *PostgresWebhookDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	query := fmt.Sprintf(`SELECT %s FROM webhook_deliveries WHERE id = $1`, webhookDeliveryColumns)
+
+	deliveries, err := r.query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, apperrors.ErrWebhookDeliveryNotFound
+	}
+
+	return deliveries[0], nil
+}
+
+// ClaimDue locks due deliveries with SKIP LOCKED, so concurrent workers never claim the same
+// delivery, and pushes their next attempt past the lease while they are being sent
+func (r *PostgresWebhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	query := fmt.Sprintf(`
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`, webhookDeliveryColumns)
+
+	return r.query(ctx, query, limit, lease.Seconds())
+}
+
+// Update saves the outcome of a delivery attempt
+func (r *PostgresWebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_attempt_at = $5,
+			last_status_code = $6, last_error = $7, delivered_at = $8
+		WHERE id = $1
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		delivery.ID,
+		string(delivery.Status),
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return apperrors.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+// Search returns deliveries matching the query, newest first
+func (r *PostgresWebhookDeliveryRepository) Search(ctx context.Context, q repository.WebhookDeliveryQuery) ([]*entity.WebhookDelivery, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if q.EndpointID != nil {
+		conditions = append(conditions, "endpoint_id = "+arg(*q.EndpointID))
+	}
+	if q.Status != "" {
+		conditions = append(conditions, "status = "+arg(string(q.Status)))
+	}
+	if q.EventType != "" {
+		conditions = append(conditions, "event_type = "+arg(string(q.EventType)))
+	}
+	if q.Before != nil {
+		conditions = append(conditions, "(created_at, id) < (SELECT created_at, id FROM webhook_deliveries WHERE id = "+arg(*q.Before)+")")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM webhook_deliveries %s ORDER BY created_at DESC, id DESC LIMIT %s`,
+		webhookDeliveryColumns, where, arg(q.Limit))
+
+	return r.query(ctx, query, args...)
+}
+
+func (r *PostgresWebhookDeliveryRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			// Log error but don't fail the operation
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		delivery := &entity.WebhookDelivery{}
+		var eventType, status string
+		var lastAttemptAt, deliveredAt sql.NullTime
+		var lastStatusCode sql.NullInt64
+
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.EndpointID,
+			&delivery.EventID,
+			&eventType,
+			&delivery.Payload,
+			&status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&lastAttemptAt,
+			&lastStatusCode,
+			&delivery.LastError,
+			&deliveredAt,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery.EventType = entity.WebhookEventType(eventType)
+		delivery.Status = entity.WebhookDeliveryStatus(status)
+		if lastAttemptAt.Valid {
+			delivery.LastAttemptAt = &lastAttemptAt.Time
+		}
+		if lastStatusCode.Valid {
+			code := int(lastStatusCode.Int64)
+			delivery.LastStatusCode = &code
+		}
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,184 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const webhookEndpointColumns = `id, url, description, secret, event_types, is_active, created_at, updated_at`
+
+// PostgresWebhookEndpointRepository implements WebhookEndpointRepository using PostgreSQL
+type PostgresWebhookEndpointRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresWebhookEndpointRepository creates a new PostgreSQL webhook endpoint repository
+func NewPostgresWebhookEndpointRepository(db *sql.DB) repository.WebhookEndpointRepository {
+	return &PostgresWebhookEndpointRepository{db: db}
+}
+
+// Create creates a new webhook endpoint
+func (r *PostgresWebhookEndpointRepository) Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	query := `
+		INSERT INTO webhook_endpoints (id, url, description, secret, event_types, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		endpoint.ID,
+		endpoint.URL,
+		endpoint.Description,
+		endpoint.Secret,
+		pq.Array(webhookEventTypeNames(endpoint.EventTypes)),
+		endpoint.IsActive,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	)
+
+	return err
+}
+
+// FindByID finds a webhook endpoint by ID
+func (r *PostgresWebhookEndpointRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error) {
+	query := fmt.Sprintf(`SELECT %s FROM webhook_endpoints WHERE id = $1`, webhookEndpointColumns)
+
+	endpoints, err := r.query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		return nil, apperrors.ErrWebhookNotFound
+	}
+
+	return endpoints[0], nil
+}
+
+// FindAll returns every webhook endpoint, oldest first
+func (r *PostgresWebhookEndpointRepository) FindAll(ctx context.Context) ([]*entity.WebhookEndpoint, error) {
+	query := fmt.Sprintf(`SELECT %s FROM webhook_endpoints ORDER BY created_at ASC`, webhookEndpointColumns)
+
+	return r.query(ctx, query)
+}
+
+// FindActiveByEventType returns the active endpoints subscribed to an event type
+func (r *PostgresWebhookEndpointRepository) FindActiveByEventType(ctx context.Context, eventType entity.WebhookEventType) ([]*entity.WebhookEndpoint, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM webhook_endpoints
+		WHERE is_active = true AND event_types @> ARRAY[$1]::TEXT[]
+	`, webhookEndpointColumns)
+
+	return r.query(ctx, query, string(eventType))
+}
+
+// Update updates a webhook endpoint
+func (r *PostgresWebhookEndpointRepository) Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	query := `
+		UPDATE webhook_endpoints
+		SET url = $2, description = $3, secret = $4, event_types = $5, is_active = $6, updated_at = $7
+		WHERE id = $1
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		endpoint.ID,
+		endpoint.URL,
+		endpoint.Description,
+		endpoint.Secret,
+		pq.Array(webhookEventTypeNames(endpoint.EventTypes)),
+		endpoint.IsActive,
+		endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return apperrors.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a webhook endpoint; its deliveries are removed by cascade
+func (r *PostgresWebhookEndpointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM webhook_endpoints WHERE id = $1`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return apperrors.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookEndpointRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookEndpoint, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			// Log error but don't fail the operation
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var endpoints []*entity.WebhookEndpoint
+	for rows.Next() {
+		endpoint := &entity.WebhookEndpoint{}
+		var eventTypes pq.StringArray
+
+		err := rows.Scan(
+			&endpoint.ID,
+			&endpoint.URL,
+			&endpoint.Description,
+			&endpoint.Secret,
+			&eventTypes,
+			&endpoint.IsActive,
+			&endpoint.CreatedAt,
+			&endpoint.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint.EventTypes = make([]entity.WebhookEventType, len(eventTypes))
+		for i, eventType := range eventTypes {
+			endpoint.EventTypes[i] = entity.WebhookEventType(eventType)
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, rows.Err()
+}
+
+func webhookEventTypeNames(eventTypes []entity.WebhookEventType) []string {
+	names := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		names[i] = string(eventType)
+	}
+	return names
+}
@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// PostgresStore implements Store in PostgreSQL so limits are shared across replicas
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a new PostgreSQL token bucket store
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Take takes one token from the bucket identified by key.
+// Refill and consumption happen in a single atomic upsert.
+func (s *PostgresStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	query := `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, last_allowed, updated_at)
+		VALUES ($1, $2::float8 - 1, true, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET last_allowed = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM (NOW() - b.updated_at)) * $3::float8) >= 1,
+			tokens = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM (NOW() - b.updated_at)) * $3::float8)
+				- CASE WHEN LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM (NOW() - b.updated_at)) * $3::float8) >= 1 THEN 1 ELSE 0 END,
+			updated_at = NOW()
+		RETURNING tokens, last_allowed
+	`
+
+	var tokens float64
+	var allowed bool
+	if err := s.db.QueryRowContext(ctx, query, key, policy.Limit, policy.RefillRate()).Scan(&tokens, &allowed); err != nil {
+		return Result{}, err
+	}
+
+	return newResult(policy, allowed, tokens), nil
+}
+
+// DeleteIdle deletes buckets that have not been used for the given duration
+func (s *PostgresStore) DeleteIdle(ctx context.Context, idle time.Duration) error {
+	query := `DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - make_interval(secs => $1)`
+
+	_, err := s.db.ExecContext(ctx, query, idle.Seconds())
+	return err
+}
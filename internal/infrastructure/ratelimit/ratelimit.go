@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy describes a token bucket: Limit requests (the bucket capacity) refilled evenly over Window
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// RefillRate returns the number of tokens added per second
+func (p Policy) RefillRate() float64 {
+	return float64(p.Limit) / p.Window.Seconds()
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next token is available (only set when not allowed)
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Store defines the interface for token bucket storage
+type Store interface {
+	// Take takes one token from the bucket identified by key
+	Take(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+// ParsePolicy parses a "LIMIT/WINDOW" specification such as "20/1m"
+func ParsePolicy(name, spec string) (Policy, error) {
+	parts := strings.SplitN(strings.TrimSpace(spec), "/", 2)
+	if len(parts) != 2 {
+		return Policy{}, fmt.Errorf("invalid rate limit %q, expected LIMIT/WINDOW", spec)
+	}
+
+	limit, err := strconv.Atoi(parts[0])
+	if err != nil || limit <= 0 {
+		return Policy{}, fmt.Errorf("invalid rate limit %q: limit must be a positive integer", spec)
+	}
+
+	window, err := time.ParseDuration(parts[1])
+	if err != nil || window <= 0 {
+		return Policy{}, fmt.Errorf("invalid rate limit %q: window must be a positive duration", spec)
+	}
+
+	return Policy{Name: name, Limit: limit, Window: window}, nil
+}
+
+// newResult builds a Result from the tokens left in a bucket
+func newResult(policy Policy, allowed bool, tokens float64) Result {
+	rate := policy.RefillRate()
+	result := Result{
+		Allowed:   allowed,
+		Limit:     policy.Limit,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     time.Duration((float64(policy.Limit) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Policy
+		wantErr bool
+	}{
+		{spec: "20/1m", want: Policy{Name: "ip", Limit: 20, Window: time.Minute}},
+		{spec: " 5/15m ", want: Policy{Name: "ip", Limit: 5, Window: 15 * time.Minute}},
+		{spec: "20", wantErr: true},
+		{spec: "0/1m", wantErr: true},
+		{spec: "-1/1m", wantErr: true},
+		{spec: "x/1m", wantErr: true},
+		{spec: "20/0s", wantErr: true},
+		{spec: "20/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePolicy("ip", tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePolicy(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParsePolicy(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	// 10 tokens per 10 seconds refill one token per second
+	policy := Policy{Limit: 10, Window: 10 * time.Second}
+
+	tests := []struct {
+		name    string
+		allowed bool
+		tokens  float64
+		want    Result
+	}{
+		{name: "full", allowed: true, tokens: 10, want: Result{Allowed: true, Limit: 10, Remaining: 10}},
+		{name: "partly used", allowed: true, tokens: 6.5, want: Result{Allowed: true, Limit: 10, Remaining: 6, Reset: 3500 * time.Millisecond}},
+		{
+			name:    "empty",
+			allowed: false,
+			tokens:  0.25,
+			want:    Result{Allowed: false, Limit: 10, Remaining: 0, RetryAfter: 750 * time.Millisecond, Reset: 9750 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newResult(policy, tt.allowed, tt.tokens); got != tt.want {
+				t.Errorf("newResult() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	policy := Policy{Name: "ip", Limit: 3, Window: 300 * time.Millisecond}
+
+	// The bucket starts full and allows a burst of Limit requests
+	for i := 0; i < 3; i++ {
+		result, err := store.Take(ctx, "a", policy)
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		if !result.Allowed || result.Remaining != 2-i {
+			t.Fatalf("Take() #%d = %+v, want allowed with %d remaining", i+1, result, 2-i)
+		}
+	}
+
+	result, err := store.Take(ctx, "a", policy)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if result.Allowed {
+		t.Fatalf("Take() beyond the limit = %+v, want rejected", result)
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > 100*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want up to one refill interval (100ms)", result.RetryAfter)
+	}
+
+	// Other keys have buckets of their own
+	if result, _ := store.Take(ctx, "b", policy); !result.Allowed {
+		t.Error("Take() for another key was rejected")
+	}
+
+	// One token is refilled every 100ms
+	time.Sleep(result.RetryAfter + 20*time.Millisecond)
+	if result, _ := store.Take(ctx, "a", policy); !result.Allowed {
+		t.Errorf("Take() after the refill = %+v, want allowed", result)
+	}
+	if result, _ := store.Take(ctx, "a", policy); result.Allowed {
+		t.Errorf("Take() after using the refilled token = %+v, want rejected", result)
+	}
+}
+
+func TestMemoryStore_RefillIsCapped(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	policy := Policy{Name: "ip", Limit: 2, Window: 20 * time.Millisecond}
+
+	if _, err := store.Take(ctx, "a", policy); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// Idle time never fills the bucket beyond its capacity
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if result, _ := store.Take(ctx, "a", policy); result.Allowed {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("allowed %d requests after idling, want the capacity of 2", allowed)
+	}
+}
@@ -0,0 +1,61 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"auth-go/internal/domain/service"
+)
+
+// AESGCMSecretBox implements SecretBox using AES-256-GCM with a random nonce per message
+type AESGCMSecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMSecretBox creates a new secret box from a base64 encoded 32-byte key
+func NewAESGCMSecretBox(encodedKey string) (service.SecretBox, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, base64 encoded")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESGCMSecretBox{aead: aead}, nil
+}
+
+// Seal encrypts plaintext as base64(nonce || ciphertext)
+func (b *AESGCMSecretBox) Seal(plaintext, associatedData []byte) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := b.aead.Seal(nonce, nonce, plaintext, associatedData)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a ciphertext made by Seal
+func (b *AESGCMSecretBox) Open(ciphertext string, associatedData []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < b.aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, sealed := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	return b.aead.Open(nil, nonce, sealed, associatedData)
+}
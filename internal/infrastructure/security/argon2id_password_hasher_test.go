@@ -0,0 +1,150 @@
+package security
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2idParams keeps hashing fast in tests
+var testArgon2idParams = Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// phcHash encodes a hash of password in PHC format with the given parameters and salt
+func phcHash(password string, params Argon2idParams, salt []byte) string {
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func TestDecodeArgon2idHash(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	valid := phcHash("secret", testArgon2idParams, salt)
+
+	tests := []struct {
+		name       string
+		hash       string
+		wantParams Argon2idParams
+		wantErr    bool
+	}{
+		{name: "valid", hash: valid, wantParams: testArgon2idParams},
+		{name: "short key", hash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHQ$a2V5", wantParams: Argon2idParams{Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 8, KeyLength: 3}},
+		{name: "empty", hash: "", wantErr: true},
+		{name: "bcrypt", hash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", wantErr: true},
+		{name: "argon2i", hash: "$argon2i$v=19$m=65536,t=3,p=2$c2FsdHNhbHQ$a2V5", wantErr: true},
+		{name: "wrong version", hash: "$argon2id$v=16$m=65536,t=3,p=2$c2FsdHNhbHQ$a2V5", wantErr: true},
+		{name: "missing parameters", hash: "$argon2id$v=19$m=65536$c2FsdHNhbHQ$a2V5", wantErr: true},
+		{name: "invalid salt", hash: "$argon2id$v=19$m=65536,t=3,p=2$!!!$a2V5", wantErr: true},
+		{name: "invalid key", hash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHQ$!!!", wantErr: true},
+		{name: "too many parts", hash: valid + "$extra", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _, _, err := decodeArgon2idHash(tt.hash)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeArgon2idHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && params != tt.wantParams {
+				t.Errorf("decodeArgon2idHash() params = %+v, want %+v", params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestArgon2idPasswordHasher_HashAndCompare(t *testing.T) {
+	hasher := NewArgon2idPasswordHasher(testArgon2idParams)
+
+	hash, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if err := hasher.Compare("correct horse", hash); err != nil {
+		t.Errorf("Compare() with the right password error = %v", err)
+	}
+	if err := hasher.Compare("wrong horse", hash); err != ErrPasswordMismatch {
+		t.Errorf("Compare() with a wrong password error = %v, want ErrPasswordMismatch", err)
+	}
+
+	// Hashes made with other parameters are verified with the parameters they carry
+	other := phcHash("correct horse", Argon2idParams{Memory: 2048, Iterations: 2, Parallelism: 1, KeyLength: 16}, []byte("saltsalt"))
+	if err := hasher.Compare("correct horse", other); err != nil {
+		t.Errorf("Compare() with a hash of other parameters error = %v", err)
+	}
+}
+
+func TestArgon2idPasswordHasher_NeedsRehash(t *testing.T) {
+	hasher := NewArgon2idPasswordHasher(testArgon2idParams)
+	salt := []byte("0123456789abcdef")
+
+	stronger := testArgon2idParams
+	stronger.Memory *= 2
+	moreIterations := testArgon2idParams
+	moreIterations.Iterations++
+	shorterSalt := testArgon2idParams
+	shorterSalt.SaltLength = 8
+
+	tests := []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{name: "current parameters", hash: phcHash("pw", testArgon2idParams, salt), want: false},
+		{name: "other memory", hash: phcHash("pw", stronger, salt), want: true},
+		{name: "other iterations", hash: phcHash("pw", moreIterations, salt), want: true},
+		{name: "other salt length", hash: phcHash("pw", shorterSalt, salt[:8]), want: true},
+		{name: "bcrypt", hash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", want: true},
+		{name: "garbage", hash: "not a hash", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasher.NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiPasswordHasher(t *testing.T) {
+	argon2Hasher := NewArgon2idPasswordHasher(testArgon2idParams).(FormatAwareHasher)
+	bcryptHasher := NewBcryptPasswordHasher(bcrypt.MinCost).(FormatAwareHasher)
+	hasher := NewMultiPasswordHasher(argon2Hasher, bcryptHasher)
+
+	bcryptHash, err := bcryptHasher.Hash("legacy")
+	if err != nil {
+		t.Fatalf("bcrypt Hash() error = %v", err)
+	}
+	argon2Hash, err := hasher.Hash("current")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		hash       string
+		wantErr    bool
+		wantRehash bool
+	}{
+		{name: "legacy bcrypt hash", password: "legacy", hash: bcryptHash, wantRehash: true},
+		{name: "legacy bcrypt hash, wrong password", password: "wrong", hash: bcryptHash, wantErr: true, wantRehash: true},
+		{name: "primary argon2id hash", password: "current", hash: argon2Hash, wantRehash: false},
+		{name: "primary argon2id hash, wrong password", password: "wrong", hash: argon2Hash, wantErr: true, wantRehash: false},
+		{name: "unknown format", password: "current", hash: "$1$md5$hash", wantErr: true, wantRehash: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := hasher.Compare(tt.password, tt.hash); (err != nil) != tt.wantErr {
+				t.Errorf("Compare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := hasher.NeedsRehash(tt.hash); got != tt.wantRehash {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.wantRehash)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package security
 
 import (
+	"strings"
+
 	"auth-go/internal/domain/service"
 
 	"golang.org/x/crypto/bcrypt"
@@ -11,14 +13,19 @@ type BcryptPasswordHasher struct {
 	cost int
 }
 
-// NewBcryptPasswordHasher creates a new bcrypt password hasher
-func NewBcryptPasswordHasher() service.PasswordHasher {
+// NewBcryptPasswordHasher creates a new bcrypt password hasher.
+// A cost outside bcrypt's allowed range falls back to bcrypt.DefaultCost.
+func NewBcryptPasswordHasher(cost int) service.PasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
 	return &BcryptPasswordHasher{
-		cost: bcrypt.DefaultCost,
+		cost: cost,
 	}
 }
 
-// Hash hashes a plain text password using bcrypt
+// Hash hashes a plain text password using bcrypt.
+// Passwords longer than 72 bytes are rejected instead of being truncated.
 func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
 	if err != nil {
@@ -31,3 +38,17 @@ func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
 func (h *BcryptPasswordHasher) Compare(password, hash string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
+
+// NeedsRehash reports whether a hash is not a bcrypt hash of the configured cost
+func (h *BcryptPasswordHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return true
+	}
+	return cost != h.cost
+}
+
+// CanVerify reports whether the hash is in bcrypt format
+func (h *BcryptPasswordHasher) CanVerify(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
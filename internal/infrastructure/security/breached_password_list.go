@@ -0,0 +1,106 @@
+package security
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// breachedPrefixLength is the SHA-1 prefix length used for k-anonymity ranges (as in Have I Been Pwned)
+const breachedPrefixLength = 5
+
+// BreachedPasswordList is an in-memory set of SHA-1 hashes of breached passwords, grouped by
+// 5 character prefix the same way the Have I Been Pwned range API serves them. The list is
+// loaded from a local file so no password (or hash) ever leaves the service.
+type BreachedPasswordList struct {
+	ranges map[string]map[string]struct{}
+	size   int
+}
+
+// LoadBreachedPasswordList loads a k-anonymity prefix file. Each line is either
+// "PREFIX:SUFFIX[:COUNT]" (5 + 35 hex characters) or a full 40 character SHA-1 hash
+// optionally followed by ":COUNT". Empty lines and lines starting with '#' are ignored.
+func LoadBreachedPasswordList(path string) (*BreachedPasswordList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := &BreachedPasswordList{
+		ranges: make(map[string]map[string]struct{}),
+	}
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(strings.ToUpper(line), ":")
+		var prefix, suffix string
+		switch {
+		case len(fields[0]) == 40:
+			prefix, suffix = fields[0][:breachedPrefixLength], fields[0][breachedPrefixLength:]
+		case len(fields) >= 2 && len(fields[0]) == breachedPrefixLength && len(fields[1]) == 40-breachedPrefixLength:
+			prefix, suffix = fields[0], fields[1]
+		default:
+			return nil, fmt.Errorf("%s:%d: invalid breached password entry", path, lineNumber)
+		}
+
+		if _, err := hex.DecodeString(prefix + suffix); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid breached password entry", path, lineNumber)
+		}
+
+		list.add(prefix, suffix)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Contains reports whether the password appears in the breached password list
+func (l *BreachedPasswordList) Contains(password string) bool {
+	if l == nil {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, ok := l.ranges[hash[:breachedPrefixLength]]
+	if !ok {
+		return false
+	}
+	_, found := suffixes[hash[breachedPrefixLength:]]
+	return found
+}
+
+// Size returns the number of hashes in the list
+func (l *BreachedPasswordList) Size() int {
+	if l == nil {
+		return 0
+	}
+	return l.size
+}
+
+func (l *BreachedPasswordList) add(prefix, suffix string) {
+	suffixes, ok := l.ranges[prefix]
+	if !ok {
+		suffixes = make(map[string]struct{})
+		l.ranges[prefix] = suffixes
+	}
+	if _, exists := suffixes[suffix]; !exists {
+		suffixes[suffix] = struct{}{}
+		l.size++
+	}
+}
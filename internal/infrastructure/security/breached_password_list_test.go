@@ -0,0 +1,106 @@
+package security
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sha1Hex returns the upper case hex SHA-1 of s, as in breached password lists
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// writeBreachedList writes the lines to a temporary file and returns its path
+func writeBreachedList(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadBreachedPasswordList(t *testing.T) {
+	password := sha1Hex("password")
+	letmein := sha1Hex("letmein")
+	dragon := sha1Hex("dragon")
+
+	list, err := LoadBreachedPasswordList(writeBreachedList(t,
+		"# comment",
+		"",
+		password[:5]+":"+password[5:]+":3861493",
+		strings.ToLower(letmein),
+		dragon+":42",
+		// Duplicates are counted once
+		password[:5]+":"+password[5:],
+	))
+	if err != nil {
+		t.Fatalf("LoadBreachedPasswordList() error = %v", err)
+	}
+
+	if list.Size() != 3 {
+		t.Errorf("Size() = %d, want 3", list.Size())
+	}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{password: "password", want: true},
+		{password: "letmein", want: true},
+		{password: "dragon", want: true},
+		{password: "Password", want: false},
+		{password: "correct horse battery staple", want: false},
+	}
+	for _, tt := range tests {
+		if got := list.Contains(tt.password); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestLoadBreachedPasswordList_SamePrefix(t *testing.T) {
+	// Another hash in the range of "password" must not match it
+	password := sha1Hex("password")
+	neighbour := password[:5] + strings.Repeat("0", 35)
+
+	list, err := LoadBreachedPasswordList(writeBreachedList(t, neighbour))
+	if err != nil {
+		t.Fatalf("LoadBreachedPasswordList() error = %v", err)
+	}
+	if list.Contains("password") {
+		t.Error("Contains() matched a hash with the same prefix but another suffix")
+	}
+}
+
+func TestLoadBreachedPasswordList_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "too short", line: "5BAA6"},
+		{name: "prefix without suffix", line: "5BAA6:"},
+		{name: "wrong suffix length", line: "5BAA6:1E4C9B93F3F0682250B6CF8331B7EE68F"},
+		{name: "not hex", line: strings.Repeat("Z", 40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadBreachedPasswordList(writeBreachedList(t, tt.line)); err == nil {
+				t.Errorf("LoadBreachedPasswordList(%q) succeeded, want an error", tt.line)
+			}
+		})
+	}
+}
+
+func TestBreachedPasswordList_Nil(t *testing.T) {
+	var list *BreachedPasswordList
+	if list.Contains("password") || list.Size() != 0 {
+		t.Error("a nil list must be empty")
+	}
+}
@@ -1,9 +1,13 @@
 package security
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"auth-go/internal/domain/entity"
@@ -23,12 +27,61 @@ type JWTTokenService struct {
 
 // Claims represents custom JWT claims
 type Claims struct {
-	UserID uuid.UUID     `json:"user_id"`
-	Email  string        `json:"email"`
-	Roles  []entity.Role `json:"roles"`
+	UserID uuid.UUID  `json:"user_id"`
+	Email  string     `json:"email"`
+	Roles  claimRoles `json:"roles"`
+	// SessionID is the refresh token family of the session
+	SessionID uuid.UUID `json:"sid"`
+	// AuthTime, ACR and AMR describe how and when the user authenticated
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	ACR      string           `json:"acr,omitempty"`
+	AMR      []string         `json:"amr,omitempty"`
+	// Permissions are embedded unless they are resolved server-side
+	Permissions []entity.Permission `json:"permissions,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// claimRoles are the roles claim: role names, or the numbers that tokens issued before
+// roles were stored in the database carry, so those tokens stay valid until they expire
+type claimRoles []entity.Role
+
+// legacyRoles maps the numbers of the former built-in roles to their names
+var legacyRoles = map[int]entity.Role{
+	1: entity.RoleUser,
+	2: entity.RoleModerator,
+	3: entity.RoleAdmin,
+}
+
+// UnmarshalJSON accepts role names as well as legacy role numbers
+func (r *claimRoles) UnmarshalJSON(data []byte) error {
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	roles := make(claimRoles, len(values))
+	for i, value := range values {
+		var name string
+		if err := json.Unmarshal(value, &name); err == nil {
+			roles[i] = entity.Role(name)
+			continue
+		}
+
+		var number int
+		if err := json.Unmarshal(value, &number); err != nil {
+			return fmt.Errorf("invalid role %s", value)
+		}
+		role, ok := legacyRoles[number]
+		if !ok {
+			return fmt.Errorf("unknown legacy role %d", number)
+		}
+		roles[i] = role
+	}
+
+	*r = roles
+	return nil
+}
+
 // NewJWTTokenService creates a new JWT token service
 func NewJWTTokenService(
 	secretKey string,
@@ -46,19 +99,32 @@ func NewJWTTokenService(
 
 // GenerateAccessToken generates a JWT access token
 func (s *JWTTokenService) GenerateAccessToken(claims service.TokenClaims) (string, error) {
+	return s.GenerateAccessTokenWithExpiry(claims, s.accessTokenExpiry)
+}
+
+// GenerateAccessTokenWithExpiry generates a JWT access token valid for the given duration
+func (s *JWTTokenService) GenerateAccessTokenWithExpiry(claims service.TokenClaims, expiry time.Duration) (string, error) {
 	now := time.Now()
 	jwtClaims := Claims{
-		UserID: claims.UserID,
-		Email:  claims.Email,
-		Roles:  claims.Roles,
+		UserID:      claims.UserID,
+		Email:       claims.Email,
+		Roles:       claimRoles(claims.Roles),
+		SessionID:   claims.SessionID,
+		ACR:         claims.ACR,
+		AMR:         claims.AMR,
+		Permissions: claims.Permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTokenExpiry)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
 			Issuer:    s.issuer,
 			Subject:   claims.UserID.String(),
 		},
 	}
 
+	if !claims.AuthTime.IsZero() {
+		jwtClaims.AuthTime = jwt.NewNumericDate(claims.AuthTime)
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
 	return token.SignedString(s.secretKey)
 }
@@ -72,6 +138,16 @@ func (s *JWTTokenService) GenerateRefreshToken() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// refreshSuccessorContext separates successor derivation from other uses of the secret key
+const refreshSuccessorContext = "refresh-token-successor:"
+
+// DeriveRefreshToken derives the successor of a refresh token with HMAC-SHA-256
+func (s *JWTTokenService) DeriveRefreshToken(parent string) string {
+	mac := hmac.New(sha256.New, s.secretKey)
+	mac.Write([]byte(refreshSuccessorContext + parent))
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+}
+
 // ValidateAccessToken validates and parses an access token
 func (s *JWTTokenService) ValidateAccessToken(tokenString string) (*service.TokenClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -94,11 +170,20 @@ func (s *JWTTokenService) ValidateAccessToken(tokenString string) (*service.Toke
 		return nil, errors.New("invalid token claims")
 	}
 
-	return &service.TokenClaims{
-		UserID: claims.UserID,
-		Email:  claims.Email,
-		Roles:  claims.Roles,
-	}, nil
+	tokenClaims := &service.TokenClaims{
+		UserID:      claims.UserID,
+		Email:       claims.Email,
+		Roles:       []entity.Role(claims.Roles),
+		SessionID:   claims.SessionID,
+		ACR:         claims.ACR,
+		AMR:         claims.AMR,
+		Permissions: claims.Permissions,
+	}
+	if claims.AuthTime != nil {
+		tokenClaims.AuthTime = claims.AuthTime.Time
+	}
+
+	return tokenClaims, nil
 }
 
 // GetAccessTokenExpiry returns the access token expiry duration
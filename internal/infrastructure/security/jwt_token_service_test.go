@@ -0,0 +1,74 @@
+package security
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestJWTTokenService_ValidateAccessToken_Roles(t *testing.T) {
+	const secret = "test-secret"
+	tokenService := NewJWTTokenService(secret, time.Minute, time.Hour, "auth-go")
+	userID := uuid.New()
+
+	tests := []struct {
+		name      string
+		roles     interface{}
+		wantRoles []entity.Role
+		wantErr   bool
+	}{
+		{name: "role names", roles: []string{"user", "support"}, wantRoles: []entity.Role{entity.RoleUser, "support"}},
+		{name: "legacy role numbers", roles: []int{1, 2, 3}, wantRoles: []entity.Role{entity.RoleUser, entity.RoleModerator, entity.RoleAdmin}},
+		{name: "unknown legacy role number", roles: []int{4}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"user_id": userID.String(),
+				"email":   "ada@example.com",
+				"roles":   tt.roles,
+				"exp":     time.Now().Add(time.Minute).Unix(),
+			}).SignedString([]byte(secret))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, err := tokenService.ValidateAccessToken(token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAccessToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(claims.Roles, tt.wantRoles) {
+				t.Errorf("Roles = %v, want %v", claims.Roles, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestJWTTokenService_RoundTrip(t *testing.T) {
+	tokenService := NewJWTTokenService("test-secret", time.Minute, time.Hour, "auth-go")
+	want := service.TokenClaims{
+		UserID:    uuid.New(),
+		Email:     "ada@example.com",
+		Roles:     []entity.Role{entity.RoleAdmin},
+		SessionID: uuid.New(),
+	}
+
+	token, err := tokenService.GenerateAccessToken(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tokenService.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	if got.UserID != want.UserID || got.SessionID != want.SessionID || !reflect.DeepEqual(got.Roles, want.Roles) {
+		t.Errorf("claims = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package security
+
+import (
+	"fmt"
+
+	"auth-go/internal/domain/service"
+)
+
+// FormatAwareHasher is a PasswordHasher that can recognise its own hash format
+type FormatAwareHasher interface {
+	service.PasswordHasher
+
+	// CanVerify reports whether the hash was produced by this algorithm
+	CanVerify(hash string) bool
+}
+
+// MultiPasswordHasher hashes new passwords with a primary algorithm while still
+// verifying hashes produced by legacy algorithms (e.g. bcrypt)
+type MultiPasswordHasher struct {
+	primary FormatAwareHasher
+	legacy  []FormatAwareHasher
+}
+
+// NewMultiPasswordHasher creates a new password hasher that hashes with primary and
+// verifies hashes of primary and any of the legacy hashers
+func NewMultiPasswordHasher(primary FormatAwareHasher, legacy ...FormatAwareHasher) service.PasswordHasher {
+	return &MultiPasswordHasher{
+		primary: primary,
+		legacy:  legacy,
+	}
+}
+
+// Hash hashes a plain text password with the primary algorithm
+func (h *MultiPasswordHasher) Hash(password string) (string, error) {
+	return h.primary.Hash(password)
+}
+
+// Compare compares a plain text password with a hash of any supported algorithm
+func (h *MultiPasswordHasher) Compare(password, hash string) error {
+	if h.primary.CanVerify(hash) {
+		return h.primary.Compare(password, hash)
+	}
+
+	for _, hasher := range h.legacy {
+		if hasher.CanVerify(hash) {
+			return hasher.Compare(password, hash)
+		}
+	}
+
+	return ErrInvalidHashFormat
+}
+
+// NeedsRehash reports whether a hash was not produced by the primary algorithm with its current parameters
+func (h *MultiPasswordHasher) NeedsRehash(hash string) bool {
+	if !h.primary.CanVerify(hash) {
+		return true
+	}
+	return h.primary.NeedsRehash(hash)
+}
+
+// NewPasswordHasher builds the password hasher for the configured algorithm ("argon2id" or "bcrypt").
+// Hashes of the other algorithm are still verified and reported as needing a rehash.
+func NewPasswordHasher(algorithm string, bcryptCost int, argon2Params Argon2idParams) (service.PasswordHasher, error) {
+	argon2Hasher := NewArgon2idPasswordHasher(argon2Params).(FormatAwareHasher)
+	bcryptHasher := NewBcryptPasswordHasher(bcryptCost).(FormatAwareHasher)
+
+	switch algorithm {
+	case "argon2id":
+		return NewMultiPasswordHasher(argon2Hasher, bcryptHasher), nil
+	case "bcrypt":
+		return NewMultiPasswordHasher(bcryptHasher, argon2Hasher), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", algorithm)
+	}
+}
@@ -0,0 +1,45 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"auth-go/internal/domain/service"
+)
+
+// OpaqueTokenService implements OpaqueTokenService using SHA-256 (or HMAC-SHA-256 when a pepper is set)
+type OpaqueTokenService struct {
+	pepper []byte
+}
+
+// NewOpaqueTokenService creates a new opaque token service.
+// An empty pepper results in plain SHA-256 digests.
+func NewOpaqueTokenService(pepper string) service.OpaqueTokenService {
+	return &OpaqueTokenService{
+		pepper: []byte(pepper),
+	}
+}
+
+// Generate generates a cryptographically secure random token
+func (s *OpaqueTokenService) Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns the hex encoded digest of a token
+func (s *OpaqueTokenService) Hash(token string) string {
+	if len(s.pepper) == 0 {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+
+	mac := hmac.New(sha256.New, s.pepper)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,153 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+	apperrors "auth-go/pkg/errors"
+)
+
+// PasswordPolicyConfig holds the configurable password rules
+type PasswordPolicyConfig struct {
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	// MinStrength is the minimum EstimatePasswordStrength score (0 disables the check)
+	MinStrength int
+	// HistorySize is the number of previous passwords that cannot be reused (0 disables the check)
+	HistorySize int
+}
+
+// PasswordPolicyEngine implements PasswordPolicy
+type PasswordPolicyEngine struct {
+	config         PasswordPolicyConfig
+	passwordHasher service.PasswordHasher
+	historyRepo    repository.PasswordHistoryRepository
+	breached       *BreachedPasswordList
+}
+
+// NewPasswordPolicy creates a new password policy engine. breached may be nil to disable the breached password check.
+func NewPasswordPolicy(
+	config PasswordPolicyConfig,
+	passwordHasher service.PasswordHasher,
+	historyRepo repository.PasswordHistoryRepository,
+	breached *BreachedPasswordList,
+) service.PasswordPolicy {
+	return &PasswordPolicyEngine{
+		config:         config,
+		passwordHasher: passwordHasher,
+		historyRepo:    historyRepo,
+		breached:       breached,
+	}
+}
+
+// Validate checks a candidate password against every rule and reports all failures at once
+func (p *PasswordPolicyEngine) Validate(ctx context.Context, password string, user *entity.User) error {
+	var violations []apperrors.PolicyViolation
+	violate := func(rule, message string) {
+		violations = append(violations, apperrors.PolicyViolation{Rule: rule, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.config.MinLength {
+		violate(apperrors.PasswordRuleMinLength, fmt.Sprintf("password must be at least %d characters long", p.config.MinLength))
+	}
+	if p.config.MaxLength > 0 && length > p.config.MaxLength {
+		violate(apperrors.PasswordRuleMaxLength, fmt.Sprintf("password must not exceed %d characters", p.config.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsNumber(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			hasSymbol = true
+		}
+	}
+
+	if p.config.RequireUppercase && !hasUpper {
+		violate(apperrors.PasswordRuleUppercase, "password must contain at least one uppercase letter")
+	}
+	if p.config.RequireLowercase && !hasLower {
+		violate(apperrors.PasswordRuleLowercase, "password must contain at least one lowercase letter")
+	}
+	if p.config.RequireDigit && !hasDigit {
+		violate(apperrors.PasswordRuleDigit, "password must contain at least one number")
+	}
+	if p.config.RequireSymbol && !hasSymbol {
+		violate(apperrors.PasswordRuleSymbol, "password must contain at least one symbol")
+	}
+
+	if p.config.MinStrength > 0 {
+		var userInputs []string
+		if user != nil {
+			userInputs = append(userInputs, user.Email)
+		}
+		if int(EstimatePasswordStrength(password, userInputs...)) < p.config.MinStrength {
+			violate(apperrors.PasswordRuleStrength, "password is too easy to guess; avoid common words, names, sequences and repeated characters")
+		}
+	}
+
+	if p.breached.Contains(password) {
+		violate(apperrors.PasswordRuleBreached, "password has appeared in a data breach and cannot be used")
+	}
+
+	if user != nil && p.config.HistorySize > 0 {
+		reused, err := p.isReused(ctx, password, user)
+		if err != nil {
+			return err
+		}
+		if reused {
+			violate(apperrors.PasswordRuleHistory, fmt.Sprintf("password must differ from your last %d passwords", p.config.HistorySize))
+		}
+	}
+
+	if len(violations) > 0 {
+		return &apperrors.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// Remember records the user's current password hash and drops entries beyond the history size
+func (p *PasswordPolicyEngine) Remember(ctx context.Context, user *entity.User) error {
+	if p.config.HistorySize <= 0 {
+		return nil
+	}
+
+	if err := p.historyRepo.Create(ctx, entity.NewPasswordHistoryEntry(user.ID, user.PasswordHash)); err != nil {
+		return err
+	}
+	return p.historyRepo.Prune(ctx, user.ID, p.config.HistorySize)
+}
+
+// isReused compares the password against the current hash and the stored history
+func (p *PasswordPolicyEngine) isReused(ctx context.Context, password string, user *entity.User) (bool, error) {
+	if user.PasswordHash != "" && p.passwordHasher.Compare(password, user.PasswordHash) == nil {
+		return true, nil
+	}
+
+	entries, err := p.historyRepo.FindRecentByUserID(ctx, user.ID, p.config.HistorySize)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		if p.passwordHasher.Compare(password, entry.PasswordHash) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,173 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"auth-go/internal/domain/entity"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// fakePasswordHistory is an in-memory PasswordHistoryRepository
+type fakePasswordHistory struct {
+	entries []*entity.PasswordHistoryEntry
+}
+
+func (r *fakePasswordHistory) Create(ctx context.Context, entry *entity.PasswordHistoryEntry) error {
+	r.entries = append([]*entity.PasswordHistoryEntry{entry}, r.entries...)
+	return nil
+}
+
+func (r *fakePasswordHistory) FindRecentByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*entity.PasswordHistoryEntry, error) {
+	var entries []*entity.PasswordHistoryEntry
+	for _, entry := range r.entries {
+		if entry.UserID == userID && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakePasswordHistory) Prune(ctx context.Context, userID uuid.UUID, keep int) error {
+	if len(r.entries) > keep {
+		r.entries = r.entries[:keep]
+	}
+	return nil
+}
+
+// violatedRules returns the sorted rules of a password policy error
+func violatedRules(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var policyErr *apperrors.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("Validate() error = %v, want a PasswordPolicyError", err)
+	}
+	rules := make([]string, len(policyErr.Violations))
+	for i, violation := range policyErr.Violations {
+		rules[i] = violation.Rule
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+func TestPasswordPolicyEngine_Validate(t *testing.T) {
+	breached, err := LoadBreachedPasswordList(writeBreachedList(t, sha1Hex("Breached#Pass1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strict := PasswordPolicyConfig{
+		MinLength:        10,
+		MaxLength:        20,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}
+
+	tests := []struct {
+		name     string
+		config   PasswordPolicyConfig
+		password string
+		want     []string
+	}{
+		{name: "valid", config: strict, password: "Valid#Pass123"},
+		{name: "too short", config: strict, password: "Va#1b", want: []string{apperrors.PasswordRuleMinLength}},
+		{name: "too long", config: strict, password: "Valid#Pass123456789012", want: []string{apperrors.PasswordRuleMaxLength}},
+		{name: "length counts runes", config: PasswordPolicyConfig{MinLength: 4, MaxLength: 4}, password: "äöüß"},
+		{
+			name:     "every character class missing",
+			config:   strict,
+			password: "          ",
+			want: []string{
+				apperrors.PasswordRuleDigit,
+				apperrors.PasswordRuleLowercase,
+				apperrors.PasswordRuleUppercase,
+			},
+		},
+		{name: "missing symbol", config: strict, password: "ValidPass123", want: []string{apperrors.PasswordRuleSymbol}},
+		{name: "breached", config: strict, password: "Breached#Pass1", want: []string{apperrors.PasswordRuleBreached}},
+		{name: "guessable", config: PasswordPolicyConfig{MinStrength: 3}, password: "password123", want: []string{apperrors.PasswordRuleStrength}},
+		{name: "strong enough", config: PasswordPolicyConfig{MinStrength: 3}, password: "violet-tractor-umbrella-91"},
+		{name: "no rules", config: PasswordPolicyConfig{}, password: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewPasswordPolicy(tt.config, NewArgon2idPasswordHasher(testArgon2idParams), &fakePasswordHistory{}, breached)
+
+			got := violatedRules(t, policy.Validate(context.Background(), tt.password, nil))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%q) violations = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyEngine_History(t *testing.T) {
+	hasher := NewArgon2idPasswordHasher(testArgon2idParams)
+	history := &fakePasswordHistory{}
+	policy := NewPasswordPolicy(PasswordPolicyConfig{HistorySize: 2}, hasher, history, nil)
+	ctx := context.Background()
+
+	user := entity.NewUser("user@example.com", "")
+	for _, password := range []string{"first", "second", "third"} {
+		hash, err := hasher.Hash(password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		user.ChangePassword(hash)
+		if err := policy.Remember(ctx, user); err != nil {
+			t.Fatalf("Remember() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		password string
+		want     []string
+	}{
+		{password: "third", want: []string{apperrors.PasswordRuleHistory}},
+		{password: "second", want: []string{apperrors.PasswordRuleHistory}},
+		// Pruned beyond the history size
+		{password: "first"},
+		{password: "fourth"},
+	}
+
+	for _, tt := range tests {
+		got := violatedRules(t, policy.Validate(ctx, tt.password, user))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Validate(%q) violations = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestEstimatePasswordStrength(t *testing.T) {
+	tests := []struct {
+		password   string
+		userInputs []string
+		max        PasswordStrength
+		min        PasswordStrength
+	}{
+		{password: "password", max: 1},
+		{password: "qwertyuiop", max: 1},
+		{password: "aaaaaaaaaaaa", max: 1},
+		{password: "P@ssw0rd", max: 1},
+		{password: "jane.doe1984", userInputs: []string{"jane.doe@example.com"}, max: 2},
+		{password: "violet-tractor-umbrella-91", min: 3},
+	}
+
+	for _, tt := range tests {
+		got := EstimatePasswordStrength(tt.password, tt.userInputs...)
+		if got < tt.min || (tt.max > 0 && got > tt.max) {
+			t.Errorf("EstimatePasswordStrength(%q) = %d, want between %d and %d", tt.password, got, tt.min, tt.max)
+		}
+	}
+}
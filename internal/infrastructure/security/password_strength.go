@@ -0,0 +1,222 @@
+package security
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// commonPasswords is a short list of the most frequently used passwords and
+// password fragments, ordered by frequency (rank matters for the estimate)
+var commonPasswords = []string{
+	"password", "123456", "12345678", "qwerty", "123456789", "12345", "1234", "111111",
+	"1234567", "dragon", "123123", "baseball", "abc123", "football", "monkey", "letmein",
+	"696969", "shadow", "master", "666666", "qwertyuiop", "123321", "mustang", "1234567890",
+	"michael", "654321", "superman", "1qaz2wsx", "7777777", "121212", "000000", "qazwsx",
+	"123qwe", "killer", "trustno1", "jordan", "jennifer", "zxcvbnm", "asdfgh", "hunter",
+	"buster", "soccer", "harley", "batman", "andrew", "tigger", "sunshine", "iloveyou",
+	"2000", "charlie", "robert", "thomas", "hockey", "ranger", "daniel", "starwars",
+	"klaster", "112233", "george", "computer", "michelle", "jessica", "pepper", "1111",
+	"zxcvbn", "555555", "11111111", "131313", "freedom", "777777", "pass", "maggie",
+	"159753", "aaaaaa", "ginger", "princess", "joshua", "cheese", "amanda", "summer",
+	"love", "ashley", "nicole", "chelsea", "biteme", "matthew", "access", "yankees",
+	"987654321", "dallas", "austin", "thunder", "taylor", "matrix", "welcome", "admin",
+	"login", "secret", "passw0rd", "changeme", "default", "root", "test", "guest",
+	"winter", "spring", "autumn", "monday", "january", "company", "qwerty123", "password1",
+}
+
+// keyboardRows are adjacent key sequences on a QWERTY keyboard
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+}
+
+// leetSubstitutions maps common character substitutions back to letters
+var leetSubstitutions = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+// bruteForceBitsPerChar is log2(10), the per character cost of unmatched characters
+const bruteForceBitsPerChar = 3.3219
+
+// rankedWord is a dictionary word with its guess rank
+type rankedWord struct {
+	word string
+	rank int
+}
+
+// PasswordStrength is an estimated password strength score between 0 (too guessable) and 4 (very unguessable)
+type PasswordStrength int
+
+// EstimatePasswordStrength estimates how hard a password is to guess, in the spirit of zxcvbn.
+// It looks for dictionary words (including l33t variants and the user's own inputs such as their
+// email), keyboard walks, sequences and repeats, and charges each match only the guesses an
+// attacker would need to find it instead of full brute-force entropy.
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	if password == "" {
+		return 0
+	}
+
+	runes := []rune(password)
+	normalized := []rune(unleet(strings.ToLower(password)))
+	lower := []rune(strings.ToLower(password))
+
+	covered := make([]bool, len(runes))
+	bits := 0.0
+
+	// User inputs are the first guesses of a targeted attack, followed by common passwords
+	var dictionary []rankedWord
+	for _, input := range userInputs {
+		for _, part := range splitUserInput(strings.ToLower(input)) {
+			if len(part) >= 3 {
+				dictionary = append(dictionary, rankedWord{word: part, rank: len(dictionary) + 1})
+			}
+		}
+	}
+	userInputCount := len(dictionary)
+	for i, word := range commonPasswords {
+		dictionary = append(dictionary, rankedWord{word: word, rank: userInputCount + i + 1})
+	}
+
+	// Dictionary words (longest match first), cost ~ log2(rank)
+	for _, entry := range sortedByLength(dictionary) {
+		w := []rune(entry.word)
+		for start := indexRunes(normalized, w, covered); start >= 0; start = indexRunes(normalized, w, covered) {
+			bits += math.Log2(float64(entry.rank)) + 1
+			if string(runes[start:start+len(w)]) != entry.word {
+				bits++ // l33t or case variation
+			}
+			markCovered(covered, start, len(w))
+		}
+	}
+
+	// Keyboard walks, sequences and repeats of at least 3 characters
+	for i := 0; i < len(lower); {
+		if covered[i] {
+			i++
+			continue
+		}
+		n := patternRunLength(lower, covered, i)
+		if n >= 3 {
+			bits += math.Log2(float64(len(keyboardRows)*26)) + math.Log2(float64(n))
+			markCovered(covered, i, n)
+			i += n
+			continue
+		}
+		i++
+	}
+
+	// Remaining characters are brute forced; like zxcvbn, assume ~10 guesses per character
+	for i := range runes {
+		if !covered[i] {
+			bits += bruteForceBitsPerChar
+		}
+	}
+
+	// Thresholds follow zxcvbn's guesses cut-offs (10^3, 10^6, 10^8, 10^10)
+	switch {
+	case bits < 10:
+		return 0
+	case bits < 20:
+		return 1
+	case bits < 26.6:
+		return 2
+	case bits < 33.2:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// patternRunLength returns the length of the keyboard walk, sequence or repeat starting at i
+func patternRunLength(s []rune, covered []bool, i int) int {
+	n := 1
+	for j := i + 1; j < len(s) && !covered[j]; j++ {
+		if !followsPattern(s[i : j+1]) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// followsPattern reports whether every character of s continues a single repeat, sequence or keyboard walk
+func followsPattern(s []rune) bool {
+	if len(s) < 2 {
+		return true
+	}
+
+	repeat, ascending, descending := true, true, true
+	for k := 1; k < len(s); k++ {
+		d := s[k] - s[k-1]
+		repeat = repeat && d == 0
+		ascending = ascending && d == 1
+		descending = descending && d == -1
+	}
+	if repeat || ascending || descending {
+		return true
+	}
+
+	for _, row := range keyboardRows {
+		if strings.Contains(row, string(s)) || strings.Contains(reverse(row), string(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexRunes finds word in s at a position not yet covered by another match
+func indexRunes(s, word []rune, covered []bool) int {
+outer:
+	for i := 0; i+len(word) <= len(s); i++ {
+		for j := range word {
+			if covered[i+j] || s[i+j] != word[j] {
+				continue outer
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+func markCovered(covered []bool, start, n int) {
+	for i := start; i < start+n; i++ {
+		covered[i] = true
+	}
+}
+
+// sortedByLength returns words ordered longest first, keeping the rank order otherwise
+func sortedByLength(words []rankedWord) []rankedWord {
+	sorted := make([]rankedWord, len(words))
+	copy(sorted, words)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && len(sorted[j].word) > len(sorted[j-1].word); j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+	return sorted
+}
+
+// splitUserInput splits user inputs such as emails into words
+func splitUserInput(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func unleet(s string) string {
+	return strings.Map(func(r rune) rune {
+		if sub, ok := leetSubstitutions[r]; ok {
+			return sub
+		}
+		return r
+	}, s)
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
@@ -0,0 +1,57 @@
+package security
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+)
+
+// roleCacheTTL bounds how long role changes take to reach permission checks
+const roleCacheTTL = 10 * time.Second
+
+// RolePermissionResolver implements PermissionResolver with the role definitions stored
+// in the database, cached briefly since every permission check resolves roles
+type RolePermissionResolver struct {
+	roleRepo repository.RoleRepository
+
+	mu       sync.Mutex
+	roles    entity.RoleSet
+	loadedAt time.Time
+}
+
+// NewRolePermissionResolver creates a new role permission resolver
+func NewRolePermissionResolver(roleRepo repository.RoleRepository) service.PermissionResolver {
+	return &RolePermissionResolver{roleRepo: roleRepo}
+}
+
+// Resolve returns the union of the permissions of the roles and the roles they inherit from
+func (r *RolePermissionResolver) Resolve(ctx context.Context, roles []entity.Role) ([]entity.Permission, error) {
+	set, err := r.roleSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return set.PermissionsOf(roles), nil
+}
+
+// roleSet returns the cached role definitions, reloading them once they are older than roleCacheTTL
+func (r *RolePermissionResolver) roleSet(ctx context.Context) (entity.RoleSet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.roles != nil && time.Since(r.loadedAt) < roleCacheTTL {
+		return r.roles, nil
+	}
+
+	definitions, err := r.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.roles = entity.NewRoleSet(definitions)
+	r.loadedAt = time.Now()
+	return r.roles, nil
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// Publisher implements WebhookPublisher by queueing one delivery per subscribed endpoint.
+// Deliveries are sent by the Worker, so publishing never waits for a receiver.
+type Publisher struct {
+	endpointRepo repository.WebhookEndpointRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+}
+
+// NewPublisher creates a new webhook publisher
+func NewPublisher(endpointRepo repository.WebhookEndpointRepository, deliveryRepo repository.WebhookDeliveryRepository) service.WebhookPublisher {
+	return &Publisher{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+// payload is the JSON body of a webhook delivery
+type payload struct {
+	ID        uuid.UUID              `json:"id"`
+	Type      string                 `json:"type"`
+	CreatedAt string                 `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Publish queues the event for every active endpoint subscribed to its type
+func (p *Publisher) Publish(ctx context.Context, event entity.WebhookEvent) error {
+	endpoints, err := p.endpointRepo.FindActiveByEventType(ctx, event.Type)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	body, err := json.Marshal(payload{
+		ID:        event.ID,
+		Type:      string(event.Type),
+		CreatedAt: event.OccurredAt.UTC().Format(time.RFC3339),
+		Data:      event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		delivery := entity.NewWebhookDelivery(endpoint.ID, event.ID, event.Type, body)
+		if err := p.deliveryRepo.Create(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerifySignature(t *testing.T) {
+	const secret = "whsec_test"
+	payload := []byte(`{"id":"1","type":"user.registered"}`)
+	signedAt := time.Unix(1700000000, 0)
+	header := Sign(secret, payload, signedAt)
+
+	if !strings.HasPrefix(header, "t=1700000000,v1=") {
+		t.Fatalf("Sign() = %q, want t=<unix time>,v1=<mac>", header)
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		payload []byte
+		header  string
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "valid", secret: secret, payload: payload, header: header, now: signedAt},
+		{name: "within tolerance", secret: secret, payload: payload, header: header, now: signedAt.Add(4 * time.Minute)},
+		{name: "clock skew within tolerance", secret: secret, payload: payload, header: header, now: signedAt.Add(-4 * time.Minute)},
+		{name: "spaces and a rotated secret's signature", secret: secret, payload: payload, header: "t=1700000000, v1=00ff, " + header[strings.Index(header, "v1="):], now: signedAt},
+		{name: "wrong secret", secret: "whsec_other", payload: payload, header: header, now: signedAt, wantErr: true},
+		{name: "modified payload", secret: secret, payload: []byte(`{"id":"2","type":"user.registered"}`), header: header, now: signedAt, wantErr: true},
+		{name: "replayed too late", secret: secret, payload: payload, header: header, now: signedAt.Add(6 * time.Minute), wantErr: true},
+		{name: "timestamp too far ahead", secret: secret, payload: payload, header: header, now: signedAt.Add(-6 * time.Minute), wantErr: true},
+		{name: "timestamp changed", secret: secret, payload: payload, header: strings.Replace(header, "t=1700000000", "t=1700000001", 1), now: signedAt, wantErr: true},
+		{name: "missing timestamp", secret: secret, payload: payload, header: header[strings.Index(header, "v1="):], now: signedAt, wantErr: true},
+		{name: "missing signature", secret: secret, payload: payload, header: "t=" + strconv.FormatInt(signedAt.Unix(), 10), now: signedAt, wantErr: true},
+		{name: "empty header", secret: secret, payload: payload, header: "", now: signedAt, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.secret, tt.payload, tt.header, 5*time.Minute, tt.now)
+			if tt.wantErr && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("VerifySignature() error = %v, want %v", err, ErrInvalidSignature)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("VerifySignature() error = %v", err)
+			}
+		})
+	}
+}
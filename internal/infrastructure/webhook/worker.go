@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+)
+
+// maxResponseSnippet is the number of response body bytes kept in the delivery log
+const maxResponseSnippet = 512
+
+// WorkerConfig configures webhook delivery
+type WorkerConfig struct {
+	// MaxAttempts before a delivery is moved to the dead letter state
+	MaxAttempts int
+	// BaseDelay before the first retry; every further retry waits twice as long
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries
+	MaxDelay time.Duration
+	// Timeout of a single delivery request
+	Timeout      time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// Worker sends queued webhook deliveries. Several replicas can run a worker against the
+// same database: deliveries are claimed with row locks, so each attempt is made once.
+type Worker struct {
+	endpointRepo repository.WebhookEndpointRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	client       *http.Client
+	config       WorkerConfig
+}
+
+// NewWorker creates a new webhook delivery worker
+func NewWorker(
+	endpointRepo repository.WebhookEndpointRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	config WorkerConfig,
+) *Worker {
+	return &Worker{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			// A redirect is reported as a failure rather than followed to an unverified URL
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config: config,
+	}
+}
+
+// Run polls for due deliveries until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while full batches are waiting, otherwise sleep until the next tick
+		claimed := w.ProcessDue(ctx)
+		if claimed == w.config.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue sends one batch of due deliveries and returns how many were claimed
+func (w *Worker) ProcessDue(ctx context.Context) int {
+	// The lease outlives a delivery attempt, so a claimed delivery is only retried
+	// by another worker if this one died while sending it
+	deliveries, err := w.deliveryRepo.ClaimDue(ctx, w.config.BatchSize, w.config.Timeout+time.Minute)
+	if err != nil {
+		log.Printf("Error claiming webhook deliveries: %v", err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *entity.WebhookDelivery) {
+			defer wg.Done()
+			w.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+// deliver makes one attempt and records its outcome
+func (w *Worker) deliver(ctx context.Context, delivery *entity.WebhookDelivery) {
+	endpoint, err := w.endpointRepo.FindByID(ctx, delivery.EndpointID)
+	switch {
+	case err == apperrors.ErrWebhookNotFound:
+		// Deleted endpoints take their deliveries with them
+		return
+	case err != nil:
+		log.Printf("Error loading webhook endpoint %s: %v", delivery.EndpointID, err)
+		return
+	case !endpoint.IsActive:
+		delivery.MarkFailed(0, "endpoint is disabled", nil)
+	default:
+		statusCode, err := w.send(ctx, endpoint, delivery)
+		if err != nil {
+			delivery.MarkFailed(statusCode, err.Error(), w.nextAttempt(delivery.Attempts+1))
+		} else {
+			delivery.MarkDelivered(statusCode)
+		}
+	}
+
+	if err := w.deliveryRepo.Update(ctx, delivery); err != nil {
+		log.Printf("Error saving webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send posts the signed payload and returns the response status code
+func (w *Worker) send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "auth-go-webhooks/1.0")
+	req.Header.Set(HeaderEventID, delivery.EventID.String())
+	req.Header.Set(HeaderEventType, string(delivery.EventType))
+	req.Header.Set(HeaderDeliveryID, delivery.ID.String())
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, delivery.Payload, time.Now()))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Error closing webhook response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSnippet))
+	return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+}
+
+// nextAttempt returns when to retry after the given number of failed attempts,
+// or nil once the delivery has run out of attempts
+func (w *Worker) nextAttempt(attempts int) *time.Time {
+	if attempts >= w.config.MaxAttempts {
+		return nil
+	}
+
+	// A zero MaxDelay leaves the back-off uncapped; doubling stops before it overflows
+	delay := w.config.BaseDelay
+	for i := 1; i < attempts; i++ {
+		if (w.config.MaxDelay > 0 && delay >= w.config.MaxDelay) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if w.config.MaxDelay > 0 && delay > w.config.MaxDelay {
+		delay = w.config.MaxDelay
+	}
+
+	next := time.Now().Add(delay)
+	return &next
+}
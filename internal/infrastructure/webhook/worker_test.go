@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// fakeEndpointRepo holds webhook endpoints in memory
+type fakeEndpointRepo struct {
+	endpoints map[uuid.UUID]*entity.WebhookEndpoint
+}
+
+func (r *fakeEndpointRepo) Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	r.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+func (r *fakeEndpointRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error) {
+	endpoint, ok := r.endpoints[id]
+	if !ok {
+		return nil, apperrors.ErrWebhookNotFound
+	}
+	return endpoint, nil
+}
+
+func (r *fakeEndpointRepo) FindAll(ctx context.Context) ([]*entity.WebhookEndpoint, error) {
+	return nil, nil
+}
+
+func (r *fakeEndpointRepo) FindActiveByEventType(ctx context.Context, eventType entity.WebhookEventType) ([]*entity.WebhookEndpoint, error) {
+	return nil, nil
+}
+
+func (r *fakeEndpointRepo) Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return nil
+}
+
+func (r *fakeEndpointRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.endpoints, id)
+	return nil
+}
+
+// fakeDeliveryRepo is an in-memory delivery queue. ClaimDue ignores NextAttemptAt,
+// so every call to ProcessDue stands for the retry delay having passed.
+type fakeDeliveryRepo struct {
+	mu         sync.Mutex
+	deliveries []*entity.WebhookDelivery
+}
+
+func (r *fakeDeliveryRepo) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *fakeDeliveryRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	return nil, apperrors.ErrWebhookDeliveryNotFound
+}
+
+func (r *fakeDeliveryRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*entity.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == entity.WebhookDeliveryPending && len(due) < limit {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeDeliveryRepo) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return nil
+}
+
+func (r *fakeDeliveryRepo) Search(ctx context.Context, query repository.WebhookDeliveryQuery) ([]*entity.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func testWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		MaxAttempts:  3,
+		BaseDelay:    time.Minute,
+		MaxDelay:     time.Hour,
+		Timeout:      5 * time.Second,
+		PollInterval: time.Second,
+		BatchSize:    10,
+	}
+}
+
+// newTestWorker queues one delivery for an endpoint at url
+func newTestWorker(url string, config WorkerConfig) (*Worker, *entity.WebhookEndpoint, *entity.WebhookDelivery) {
+	endpoint := entity.NewWebhookEndpoint(url, "test", "whsec_test", []entity.WebhookEventType{entity.WebhookEventUserRegistered})
+	delivery := entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookEventUserRegistered, []byte(`{"type":"user.registered"}`))
+
+	endpointRepo := &fakeEndpointRepo{endpoints: map[uuid.UUID]*entity.WebhookEndpoint{endpoint.ID: endpoint}}
+	deliveryRepo := &fakeDeliveryRepo{deliveries: []*entity.WebhookDelivery{delivery}}
+	return NewWorker(endpointRepo, deliveryRepo, config), endpoint, delivery
+}
+
+func TestWorker_DeliversSignedPayload(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	worker, endpoint, delivery := newTestWorker(server.URL, testWorkerConfig())
+	if claimed := worker.ProcessDue(context.Background()); claimed != 1 {
+		t.Fatalf("ProcessDue() = %d, want 1", claimed)
+	}
+
+	if got == nil {
+		t.Fatal("endpoint was not called")
+	}
+	if string(body) != string(delivery.Payload) {
+		t.Errorf("body = %s, want %s", body, delivery.Payload)
+	}
+	if err := VerifySignature(endpoint.Secret, body, got.Header.Get(HeaderSignature), time.Minute, time.Now()); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+	for header, want := range map[string]string{
+		HeaderEventID:    delivery.EventID.String(),
+		HeaderEventType:  string(entity.WebhookEventUserRegistered),
+		HeaderDeliveryID: delivery.ID.String(),
+	} {
+		if value := got.Header.Get(header); value != want {
+			t.Errorf("%s = %q, want %q", header, value, want)
+		}
+	}
+
+	if delivery.Status != entity.WebhookDeliveryDelivered || delivery.Attempts != 1 || *delivery.LastStatusCode != http.StatusAccepted {
+		t.Errorf("delivery = %s after %d attempts, want delivered after 1", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestWorker_RetriesThenDeadLetters(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr string
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "database unavailable", http.StatusInternalServerError)
+			},
+			wantErr: "unexpected status 500: database unavailable",
+		},
+		{
+			name: "redirect is not followed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "https://example.com/", http.StatusFound)
+			},
+			wantErr: "unexpected status 302",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			config := testWorkerConfig()
+			worker, _, delivery := newTestWorker(server.URL, config)
+
+			for attempt := 1; attempt < config.MaxAttempts; attempt++ {
+				before := time.Now()
+				worker.ProcessDue(context.Background())
+
+				if delivery.Status != entity.WebhookDeliveryPending || delivery.Attempts != attempt {
+					t.Fatalf("after attempt %d: %s with %d attempts, want pending", attempt, delivery.Status, delivery.Attempts)
+				}
+				if !strings.HasPrefix(delivery.LastError, tt.wantErr) {
+					t.Errorf("LastError = %q, want prefix %q", delivery.LastError, tt.wantErr)
+				}
+				wantDelay := config.BaseDelay << (attempt - 1)
+				if delay := delivery.NextAttemptAt.Sub(before); delay < wantDelay || delay > wantDelay+time.Second {
+					t.Errorf("after attempt %d: retry in %v, want %v", attempt, delay, wantDelay)
+				}
+			}
+
+			worker.ProcessDue(context.Background())
+			if delivery.Status != entity.WebhookDeliveryDead || delivery.Attempts != config.MaxAttempts {
+				t.Fatalf("after %d attempts: %s, want dead", delivery.Attempts, delivery.Status)
+			}
+
+			// Dead deliveries are only retried manually
+			if claimed := worker.ProcessDue(context.Background()); claimed != 0 {
+				t.Errorf("ProcessDue() claimed %d dead deliveries", claimed)
+			}
+			if calls != config.MaxAttempts {
+				t.Errorf("endpoint called %d times, want %d", calls, config.MaxAttempts)
+			}
+		})
+	}
+}
+
+func TestWorker_DisabledEndpointDeadLetters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("disabled endpoint was called")
+	}))
+	defer server.Close()
+
+	worker, endpoint, delivery := newTestWorker(server.URL, testWorkerConfig())
+	endpoint.IsActive = false
+	worker.ProcessDue(context.Background())
+
+	if delivery.Status != entity.WebhookDeliveryDead {
+		t.Errorf("Status = %s, want dead", delivery.Status)
+	}
+}
+
+func TestWorker_NextAttempt(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDelay time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first retry", maxDelay: time.Hour, attempts: 1, want: time.Minute},
+		{name: "doubles", maxDelay: time.Hour, attempts: 3, want: 4 * time.Minute},
+		{name: "capped", maxDelay: time.Hour, attempts: 9, want: time.Hour},
+		{name: "uncapped", attempts: 9, want: 256 * time.Minute},
+		{name: "uncapped does not overflow", attempts: 99, want: time.Minute << 27},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worker := NewWorker(nil, nil, WorkerConfig{MaxAttempts: 100, BaseDelay: time.Minute, MaxDelay: tt.maxDelay})
+
+			before := time.Now()
+			next := worker.nextAttempt(tt.attempts)
+			if next == nil {
+				t.Fatal("nextAttempt() = nil, want a retry")
+			}
+			if delay := next.Sub(before); delay < tt.want || delay > tt.want+time.Second {
+				t.Errorf("nextAttempt(%d) in %v, want %v", tt.attempts, delay, tt.want)
+			}
+		})
+	}
+
+	worker := NewWorker(nil, nil, WorkerConfig{MaxAttempts: 3, BaseDelay: time.Minute})
+	if next := worker.nextAttempt(3); next != nil {
+		t.Errorf("nextAttempt(MaxAttempts) = %v, want nil (dead letter)", next)
+	}
+}
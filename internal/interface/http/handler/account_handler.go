@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/interface/http/middleware"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// AccountHandler handles credential and session changes of the authenticated user
+type AccountHandler struct {
+	sessionRepo               repository.SessionRepository
+	changePasswordUseCase     *usecase.ChangePasswordUseCase
+	changeEmailUseCase        *usecase.ChangeEmailUseCase
+	confirmEmailChangeUseCase *usecase.ConfirmEmailChangeUseCase
+	revokeSessionUseCase      *usecase.RevokeSessionUseCase
+	reauthenticateUseCase     *usecase.ReauthenticateUseCase
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(
+	sessionRepo repository.SessionRepository,
+	changePasswordUseCase *usecase.ChangePasswordUseCase,
+	changeEmailUseCase *usecase.ChangeEmailUseCase,
+	confirmEmailChangeUseCase *usecase.ConfirmEmailChangeUseCase,
+	revokeSessionUseCase *usecase.RevokeSessionUseCase,
+	reauthenticateUseCase *usecase.ReauthenticateUseCase,
+) *AccountHandler {
+	return &AccountHandler{
+		sessionRepo:               sessionRepo,
+		changePasswordUseCase:     changePasswordUseCase,
+		changeEmailUseCase:        changeEmailUseCase,
+		confirmEmailChangeUseCase: confirmEmailChangeUseCase,
+		revokeSessionUseCase:      revokeSessionUseCase,
+		reauthenticateUseCase:     reauthenticateUseCase,
+	}
+}
+
+// Reauthenticate confirms the password of the authenticated user and returns a short-lived
+// elevated access token, required by high-risk operations such as email or role changes
+func (h *AccountHandler) Reauthenticate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID); !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(uuid.UUID)
+
+	var req dto.ReauthenticateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	response, err := h.reauthenticateUseCase.Execute(r.Context(), actorFromRequest(r), sessionID, req)
+	if err != nil {
+		if respondWithLockedError(w, err) {
+			return
+		}
+		switch err {
+		case apperrors.ErrInvalidCredentials:
+			respondWithError(w, http.StatusBadRequest, "password is incorrect")
+		case apperrors.ErrSessionNotFound:
+			respondWithError(w, http.StatusUnauthorized, "session has ended, please sign in again")
+		case apperrors.ErrUserInactive:
+			respondWithError(w, http.StatusForbidden, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// ChangePassword handles a password change of the authenticated user
+func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID); !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(uuid.UUID)
+
+	var req dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.changePasswordUseCase.Execute(r.Context(), actorFromRequest(r), sessionID, req); err != nil {
+		if respondWithLockedError(w, err) {
+			return
+		}
+		if respondWithPasswordPolicyError(w, err) {
+			return
+		}
+		switch err {
+		case apperrors.ErrInvalidPassword:
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		case apperrors.ErrInvalidCredentials:
+			respondWithError(w, http.StatusBadRequest, "current password is incorrect")
+		case apperrors.ErrUserInactive:
+			respondWithError(w, http.StatusForbidden, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "password changed successfully"})
+}
+
+// ChangeEmail handles an email change request of the authenticated user
+func (h *AccountHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID); !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(uuid.UUID)
+
+	var req dto.ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.changeEmailUseCase.Execute(r.Context(), actorFromRequest(r), sessionID, req); err != nil {
+		if respondWithLockedError(w, err) {
+			return
+		}
+		switch err {
+		case apperrors.ErrInvalidEmail:
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		case apperrors.ErrInvalidInput:
+			respondWithError(w, http.StatusBadRequest, "new email must differ from the current one")
+		case apperrors.ErrInvalidCredentials:
+			respondWithError(w, http.StatusBadRequest, "current password is incorrect")
+		case apperrors.ErrUserAlreadyExists:
+			respondWithError(w, http.StatusConflict, "email is already in use")
+		case apperrors.ErrUserInactive:
+			respondWithError(w, http.StatusForbidden, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, map[string]string{
+		"message": "a verification link has been sent to the new email address",
+	})
+}
+
+// ConfirmEmailChange handles the verification of a new email address
+func (h *AccountHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req dto.ConfirmEmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.confirmEmailChangeUseCase.Execute(r.Context(), req); err != nil {
+		switch err {
+		case apperrors.ErrInvalidToken, apperrors.ErrExpiredToken:
+			respondWithError(w, http.StatusBadRequest, "invalid or expired verification token")
+		case apperrors.ErrUserAlreadyExists:
+			respondWithError(w, http.StatusConflict, "email is already in use")
+		case apperrors.ErrUserInactive:
+			respondWithError(w, http.StatusForbidden, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "email address updated successfully"})
+}
+
+// ListSessions lists the active sessions (signed-in devices) of the authenticated user
+func (h *AccountHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	currentID, _ := r.Context().Value(middleware.SessionIDKey).(uuid.UUID)
+
+	sessions, err := h.sessionRepo.FindActiveByUserID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response := dto.SessionListResponse{Sessions: make([]dto.SessionResponse, len(sessions))}
+	for i, session := range sessions {
+		response.Sessions[i] = toSessionResponse(session, currentID)
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// RevokeSession signs the authenticated user out of one of their sessions
+func (h *AccountHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID); !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID, ok := pathUUID(w, r, "id", "invalid session id")
+	if !ok {
+		return
+	}
+
+	if err := h.revokeSessionUseCase.Execute(r.Context(), actorFromRequest(r), sessionID); err != nil {
+		switch err {
+		case apperrors.ErrSessionNotFound:
+			respondWithError(w, http.StatusNotFound, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "session revoked"})
+}
+
+// toSessionResponse converts a session to its API representation
+func toSessionResponse(session *entity.Session, currentID uuid.UUID) dto.SessionResponse {
+	return dto.SessionResponse{
+		ID:         session.ID.String(),
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+		LastUsedAt: session.LastUsedAt.Format(time.RFC3339),
+		Current:    session.ID == currentID,
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	"auth-go/internal/interface/http/middleware"
+
+	"github.com/google/uuid"
+)
+
+// activeSessionRepo lists the sessions it holds as the active sessions of their users
+type activeSessionRepo struct {
+	repository.SessionRepository
+	sessions []*entity.Session
+}
+
+func (r *activeSessionRepo) FindActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func TestAccountHandler_ListSessions(t *testing.T) {
+	userID := uuid.New()
+	phone := entity.NewSession(userID, "web", "Phone", "test", "203.0.113.7")
+	laptop := entity.NewSession(userID, "web", "Laptop", "test", "203.0.113.8")
+	elsewhere := entity.NewSession(uuid.New(), "web", "", "test", "203.0.113.9")
+	h := NewAccountHandler(&activeSessionRepo{sessions: []*entity.Session{phone, laptop, elsewhere}}, nil, nil, nil, nil, nil)
+
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, userID)
+	ctx = context.WithValue(ctx, middleware.SessionIDKey, laptop.ID)
+	rec := httptest.NewRecorder()
+	h.ListSessions(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/sessions", nil).WithContext(ctx))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var response dto.SessionListResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Sessions) != 2 {
+		t.Fatalf("sessions = %+v, want the phone and the laptop", response.Sessions)
+	}
+	for _, session := range response.Sessions {
+		if want := session.ID == laptop.ID.String(); session.Current != want {
+			t.Errorf("session %s current = %v, want %v", session.ID, session.Current, want)
+		}
+	}
+}
+
+func TestAccountHandler_ListSessions_Unauthenticated(t *testing.T) {
+	h := NewAccountHandler(&activeSessionRepo{}, nil, nil, nil, nil, nil)
+
+	rec := httptest.NewRecorder()
+	h.ListSessions(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/sessions", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
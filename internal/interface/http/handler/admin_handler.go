@@ -1,67 +1,402 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"auth-go/internal/application/dto"
+	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
 	"auth-go/internal/domain/repository"
+	"auth-go/internal/interface/http/middleware"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
 )
 
 // AdminHandler handles admin HTTP requests
 type AdminHandler struct {
-	userRepo repository.UserRepository
+	userRepo                  repository.UserRepository
+	unlockAccountUseCase      *usecase.UnlockAccountUseCase
+	assignRoleUseCase         *usecase.AssignRoleUseCase
+	revokeRoleUseCase         *usecase.RevokeRoleUseCase
+	createUserUseCase         *usecase.CreateUserUseCase
+	updateUserUseCase         *usecase.UpdateUserUseCase
+	setUserActiveUseCase      *usecase.SetUserActiveUseCase
+	forcePasswordResetUseCase *usecase.ForcePasswordResetUseCase
+	revokeSessionsUseCase     *usecase.RevokeSessionsUseCase
+	deleteUserUseCase         *usecase.DeleteUserUseCase
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(userRepo repository.UserRepository) *AdminHandler {
+func NewAdminHandler(
+	userRepo repository.UserRepository,
+	unlockAccountUseCase *usecase.UnlockAccountUseCase,
+	assignRoleUseCase *usecase.AssignRoleUseCase,
+	revokeRoleUseCase *usecase.RevokeRoleUseCase,
+	createUserUseCase *usecase.CreateUserUseCase,
+	updateUserUseCase *usecase.UpdateUserUseCase,
+	setUserActiveUseCase *usecase.SetUserActiveUseCase,
+	forcePasswordResetUseCase *usecase.ForcePasswordResetUseCase,
+	revokeSessionsUseCase *usecase.RevokeSessionsUseCase,
+	deleteUserUseCase *usecase.DeleteUserUseCase,
+) *AdminHandler {
 	return &AdminHandler{
-		userRepo: userRepo,
+		userRepo:                  userRepo,
+		unlockAccountUseCase:      unlockAccountUseCase,
+		assignRoleUseCase:         assignRoleUseCase,
+		revokeRoleUseCase:         revokeRoleUseCase,
+		createUserUseCase:         createUserUseCase,
+		updateUserUseCase:         updateUserUseCase,
+		setUserActiveUseCase:      setUserActiveUseCase,
+		forcePasswordResetUseCase: forcePasswordResetUseCase,
+		revokeSessionsUseCase:     revokeSessionsUseCase,
+		deleteUserUseCase:         deleteUserUseCase,
 	}
 }
 
-// ListUsers lists all users (admin only)
+// ListUsers lists one page of users matching the query parameters (admin only)
 func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.userRepo.FindAll(r.Context())
+	query, err := parseUserQuery(r.URL.Query())
 	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.userRepo.Search(r.Context(), query)
+	if err != nil {
+		if err == apperrors.ErrInvalidInput {
+			respondWithError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, "failed to fetch users")
 		return
 	}
 
-	// Convert to response format
-	type UserResponse struct {
-		ID        string   `json:"id"`
-		Email     string   `json:"email"`
-		Roles     []string `json:"roles"`
-		IsActive  bool     `json:"is_active"`
-		CreatedAt string   `json:"created_at"`
+	response := dto.UserListResponse{
+		Users:      make([]dto.AdminUserResponse, len(page.Users)),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+	for i, user := range page.Users {
+		response.Users[i] = toAdminUserResponse(user)
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// GetUser returns a single user (admin only)
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.userRepo.FindByID(r.Context(), userID)
+	if err != nil {
+		respondWithAdminError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toAdminUserResponse(user))
+}
+
+// CreateUser creates a user and emails them an invite to choose a password (admin only)
+func (h *AdminHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Choosing roles is a role assignment
+	if len(req.Roles) > 0 && !middleware.HasPermission(r, entity.PermissionRolesAssign) {
+		respondWithError(w, http.StatusForbidden, apperrors.ErrForbidden.Error())
+		return
+	}
+
+	user, err := h.createUserUseCase.Execute(r.Context(), actorFromRequest(r), req)
+	if err != nil {
+		respondWithAdminError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, toAdminUserResponse(user))
+}
+
+// UpdateUser changes the email and/or roles of a user (admin only)
+func (h *AdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Changing roles is a role assignment
+	if req.Roles != nil && !middleware.HasPermission(r, entity.PermissionRolesAssign) {
+		respondWithError(w, http.StatusForbidden, apperrors.ErrForbidden.Error())
+		return
+	}
+
+	user, err := h.updateUserUseCase.Execute(r.Context(), actorFromRequest(r), userID, req)
+	if err != nil {
+		respondWithAdminError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toAdminUserResponse(user))
+}
+
+// ActivateUser activates a user account (admin only)
+func (h *AdminHandler) ActivateUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserActive(w, r, true)
+}
+
+// DeactivateUser deactivates a user account and revokes its sessions (admin only)
+func (h *AdminHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserActive(w, r, false)
+}
+
+func (h *AdminHandler) setUserActive(w http.ResponseWriter, r *http.Request, active bool) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.setUserActiveUseCase.Execute(r.Context(), actorFromRequest(r), userID, active)
+	if err != nil {
+		respondWithAdminError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toAdminUserResponse(user))
+}
+
+// ForcePasswordReset invalidates a user's password and emails them a reset link (admin only)
+func (h *AdminHandler) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.forcePasswordResetUseCase.Execute(r.Context(), actorFromRequest(r), userID); err != nil {
+		respondWithAdminError(w, err)
+		return
 	}
 
-	response := make([]UserResponse, len(users))
-	for i, user := range users {
-		roles := make([]string, len(user.Roles))
-		for j, role := range user.Roles {
-			roles[j] = role.String()
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "password reset link sent"})
+}
+
+// RevokeSessions signs a user out of every session (admin only)
+func (h *AdminHandler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.revokeSessionsUseCase.Execute(r.Context(), actorFromRequest(r), userID); err != nil {
+		respondWithAdminError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "sessions revoked successfully"})
+}
+
+// DeleteUser permanently deletes a user (admin only)
+func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.deleteUserUseCase.Execute(r.Context(), actorFromRequest(r), userID); err != nil {
+		respondWithAdminError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "user deleted successfully"})
+}
+
+// UnlockUser lifts a failed login lockout of a user (admin only)
+func (h *AdminHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.unlockAccountUseCase.Execute(r.Context(), actorFromRequest(r), userID); err != nil {
+		respondWithAdminError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "user unlocked successfully"})
+}
+
+// AssignRole grants a role to a user (admin only)
+func (h *AdminHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	h.changeRole(w, r, h.assignRoleUseCase.Execute)
+}
+
+// RevokeRole removes a role from a user (admin only)
+func (h *AdminHandler) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	h.changeRole(w, r, h.revokeRoleUseCase.Execute)
+}
+
+// changeRole decodes a role request and applies it with the given use case
+func (h *AdminHandler) changeRole(
+	w http.ResponseWriter,
+	r *http.Request,
+	execute func(ctx context.Context, actor dto.Actor, userID uuid.UUID, req dto.RoleRequest) (*entity.User, error),
+) {
+	userID, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.RoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := execute(r.Context(), actorFromRequest(r), userID, req)
+	if err != nil {
+		respondWithAdminError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toAdminUserResponse(user))
+}
+
+// parseUserQuery builds a user search from query parameters:
+// email (prefix), role, active, created_after, created_before, last_login_after, last_login_before
+// (RFC 3339), sort (created_at, email or last_login_at; prefix with - for descending), limit and cursor
+func parseUserQuery(values url.Values) (repository.UserQuery, error) {
+	query := repository.UserQuery{
+		EmailPrefix: strings.TrimSpace(values.Get("email")),
+		SortBy:      repository.UserSortCreatedAt,
+		SortDesc:    true,
+		Limit:       defaultUserPageSize,
+		Cursor:      values.Get("cursor"),
+	}
+
+	if role := values.Get("role"); role != "" {
+		// Undefined roles match no users
+		filter := entity.Role(role)
+		query.Role = &filter
+	}
+
+	if active := values.Get("active"); active != "" {
+		isActive, err := strconv.ParseBool(active)
+		if err != nil {
+			return query, errors.New("invalid active filter")
 		}
+		query.IsActive = &isActive
+	}
 
-		response[i] = UserResponse{
-			ID:        user.ID.String(),
-			Email:     user.Email,
-			Roles:     roles,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	for name, target := range map[string]**time.Time{
+		"created_after":     &query.CreatedAfter,
+		"created_before":    &query.CreatedBefore,
+		"last_login_after":  &query.LastLoginAfter,
+		"last_login_before": &query.LastLoginBefore,
+	} {
+		if value := values.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return query, fmt.Errorf("invalid %s, expected an RFC 3339 timestamp", name)
+			}
+			*target = &t
 		}
 	}
 
-	respondWithJSON(w, http.StatusOK, response)
+	if sort := values.Get("sort"); sort != "" {
+		query.SortDesc = strings.HasPrefix(sort, "-")
+		query.SortBy = repository.UserSortField(strings.TrimPrefix(sort, "-"))
+		switch query.SortBy {
+		case repository.UserSortCreatedAt, repository.UserSortEmail, repository.UserSortLastLoginAt:
+		default:
+			return query, errors.New("invalid sort, expected created_at, email or last_login_at")
+		}
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxUserPageSize {
+			return query, fmt.Errorf("invalid limit, expected 1 to %d", maxUserPageSize)
+		}
+		query.Limit = n
+	}
+
+	return query, nil
+}
+
+// respondWithAdminError maps errors of admin use cases to HTTP responses
+func respondWithAdminError(w http.ResponseWriter, err error) {
+	switch err {
+	case apperrors.ErrInvalidEmail:
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case apperrors.ErrInvalidInput:
+		respondWithError(w, http.StatusBadRequest, "invalid roles, every user needs at least one valid role")
+	case apperrors.ErrRoleNotFound:
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case apperrors.ErrForbidden:
+		respondWithError(w, http.StatusForbidden, "admins cannot perform this action on their own account or on users with permissions they do not hold, nor grant such permissions")
+	case apperrors.ErrUserNotFound:
+		respondWithError(w, http.StatusNotFound, err.Error())
+	case apperrors.ErrUserAlreadyExists:
+		respondWithError(w, http.StatusConflict, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// pathUserID parses the {id} path parameter, answering 400 if it is not a UUID
+func pathUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid user id")
+		return uuid.Nil, false
+	}
+	return userID, true
 }
 
-// // Helper functions
-// func respondWithError(w http.ResponseWriter, code int, message string) {
-// 	respondWithJSON(w, code, map[string]string{"error": message})
-// }
+// toAdminUserResponse converts a user to its admin representation
+func toAdminUserResponse(user *entity.User) dto.AdminUserResponse {
+	roles := make([]string, len(user.Roles))
+	for i, role := range user.Roles {
+		roles[i] = role.String()
+	}
+
+	response := dto.AdminUserResponse{
+		ID:          user.ID.String(),
+		Email:       user.Email,
+		Roles:       roles,
+		IsActive:    user.IsActive,
+		HasPassword: user.HasPassword(),
+		CreatedAt:   user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   user.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if user.LastLoginAt != nil {
+		lastLoginAt := user.LastLoginAt.Format("2006-01-02T15:04:05Z")
+		response.LastLoginAt = &lastLoginAt
+	}
 
-// func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-// 	response, _ := json.Marshal(payload)
-// 	w.Header().Set("Content-Type", "application/json")
-// 	w.WriteHeader(code)
-// 	w.Write(response)
-// }
+	return response
+}
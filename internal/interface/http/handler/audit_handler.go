@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+	// auditExportBatchSize is the number of events loaded at a time while exporting
+	auditExportBatchSize = 1000
+)
+
+// AuditHandler serves the audit trail to admins
+type AuditHandler struct {
+	auditRepo               repository.AuditEventRepository
+	verifyAuditChainUseCase *usecase.VerifyAuditChainUseCase
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditRepo repository.AuditEventRepository, verifyAuditChainUseCase *usecase.VerifyAuditChainUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditRepo:               auditRepo,
+		verifyAuditChainUseCase: verifyAuditChainUseCase,
+	}
+}
+
+// ListEvents lists one page of audit events matching the query parameters, newest first (admin only)
+func (h *AuditHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	query, err := parseAuditEventQuery(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, err := h.auditRepo.Search(r.Context(), query)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "failed to fetch audit events")
+		return
+	}
+
+	response := dto.AuditEventListResponse{Events: make([]dto.AuditEventResponse, len(events))}
+	for i, event := range events {
+		response.Events[i] = toAuditEventResponse(event)
+	}
+	if len(events) == query.Limit {
+		response.NextCursor = strconv.FormatInt(events[len(events)-1].Sequence, 10)
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// ExportEvents streams every audit event matching the query parameters as CSV or JSON (admin only)
+func (h *AuditHandler) ExportEvents(w http.ResponseWriter, r *http.Request) {
+	query, err := parseAuditEventQuery(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query.Limit = auditExportBatchSize
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		respondWithError(w, http.StatusBadRequest, "invalid format, expected csv or json")
+		return
+	}
+
+	// Load the first batch before writing anything so errors can still be reported
+	events, err := h.auditRepo.Search(r.Context(), query)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "failed to fetch audit events")
+		return
+	}
+
+	filename := "audit-events-" + time.Now().UTC().Format("20060102T150405Z") + "." + format
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	var writeEvent func(event *entity.AuditEvent) error
+	var finish func() error
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"sequence", "id", "type", "actor_id", "subject_id", "ip_address", "user_agent", "details", "created_at", "prev_hash", "hash"}); err != nil {
+			log.Printf("Error writing audit export: %v", err)
+			return
+		}
+		writeEvent = func(event *entity.AuditEvent) error {
+			details, _ := json.Marshal(event.Details)
+			return writer.Write([]string{
+				strconv.FormatInt(event.Sequence, 10),
+				event.ID.String(),
+				string(event.Type),
+				optionalID(event.ActorID),
+				optionalID(event.SubjectID),
+				event.IPAddress,
+				event.UserAgent,
+				string(details),
+				event.CreatedAt.UTC().Format(time.RFC3339Nano),
+				event.PrevHash,
+				event.Hash,
+			})
+		}
+		finish = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		first := true
+		if _, err := w.Write([]byte("[")); err != nil {
+			log.Printf("Error writing audit export: %v", err)
+			return
+		}
+		writeEvent = func(event *entity.AuditEvent) error {
+			if !first {
+				if _, err := w.Write([]byte(",")); err != nil {
+					return err
+				}
+			}
+			first = false
+			return encoder.Encode(toAuditEventResponse(event))
+		}
+		finish = func() error {
+			_, err := w.Write([]byte("]"))
+			return err
+		}
+	}
+
+	for {
+		for _, event := range events {
+			if err := writeEvent(event); err != nil {
+				log.Printf("Error writing audit export: %v", err)
+				return
+			}
+		}
+		if len(events) < query.Limit {
+			break
+		}
+
+		query.BeforeSequence = events[len(events)-1].Sequence
+		events, err = h.auditRepo.Search(r.Context(), query)
+		if err != nil {
+			// The response has started; the truncated export is the only signal left
+			log.Printf("Error fetching audit events for export: %v", err)
+			return
+		}
+	}
+
+	if err := finish(); err != nil {
+		log.Printf("Error writing audit export: %v", err)
+	}
+}
+
+// VerifyChain checks the audit trail hash chain for tampering (admin only)
+func (h *AuditHandler) VerifyChain(w http.ResponseWriter, r *http.Request) {
+	report, err := h.verifyAuditChainUseCase.Execute(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "failed to verify audit trail")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// parseAuditEventQuery builds an audit event search from query parameters:
+// type, actor_id, subject_id, ip, from, to (RFC 3339), limit and cursor
+func parseAuditEventQuery(values url.Values) (repository.AuditEventQuery, error) {
+	query := repository.AuditEventQuery{
+		Type:      entity.AuditEventType(values.Get("type")),
+		IPAddress: values.Get("ip"),
+		Limit:     defaultAuditPageSize,
+	}
+
+	for name, target := range map[string]**uuid.UUID{
+		"actor_id":   &query.ActorID,
+		"subject_id": &query.SubjectID,
+	} {
+		if value := values.Get(name); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return query, fmt.Errorf("invalid %s", name)
+			}
+			*target = &id
+		}
+	}
+
+	for name, target := range map[string]**time.Time{
+		"from": &query.From,
+		"to":   &query.To,
+	} {
+		if value := values.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return query, fmt.Errorf("invalid %s, expected an RFC 3339 timestamp", name)
+			}
+			*target = &t
+		}
+	}
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		sequence, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || sequence <= 0 {
+			return query, errors.New("invalid cursor")
+		}
+		query.BeforeSequence = sequence
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxAuditPageSize {
+			return query, fmt.Errorf("invalid limit, expected 1 to %d", maxAuditPageSize)
+		}
+		query.Limit = n
+	}
+
+	return query, nil
+}
+
+// toAuditEventResponse converts an audit event to its API representation
+func toAuditEventResponse(event *entity.AuditEvent) dto.AuditEventResponse {
+	response := dto.AuditEventResponse{
+		Sequence:  event.Sequence,
+		ID:        event.ID.String(),
+		Type:      string(event.Type),
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		Details:   event.Details,
+		CreatedAt: event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Hash:      event.Hash,
+	}
+	if event.ActorID != nil {
+		actorID := event.ActorID.String()
+		response.ActorID = &actorID
+	}
+	if event.SubjectID != nil {
+		subjectID := event.SubjectID.String()
+		response.SubjectID = &subjectID
+	}
+	return response
+}
+
+func optionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
@@ -2,11 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"auth-go/internal/application/dto"
 	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
 	"auth-go/internal/interface/http/middleware"
 	apperrors "auth-go/pkg/errors"
 
@@ -44,7 +48,13 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.IPAddress = middleware.ClientIP(r)
+	req.UserAgent = r.UserAgent()
+
 	if err := h.registerUseCase.Execute(r.Context(), req); err != nil {
+		if respondWithPasswordPolicyError(w, err) {
+			return
+		}
 		switch err {
 		case apperrors.ErrInvalidEmail, apperrors.ErrInvalidPassword:
 			respondWithError(w, http.StatusBadRequest, err.Error())
@@ -56,6 +66,12 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Enumeration-safe mode gives the same answer whether or not the email was already registered
+	if h.registerUseCase.IsEnumerationSafe() {
+		respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "registration received, you can now sign in"})
+		return
+	}
+
 	respondWithJSON(w, http.StatusCreated, map[string]string{"message": "user registered successfully"})
 }
 
@@ -67,13 +83,23 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.IPAddress = middleware.ClientIP(r)
+	req.UserAgent = r.UserAgent()
+	req.ClientID = middleware.ClientID(r)
+	req.DeviceID = middleware.DeviceID(r)
+
 	response, err := h.loginUseCase.Execute(r.Context(), req)
 	if err != nil {
+		if respondWithLockedError(w, err) {
+			return
+		}
 		switch err {
 		case apperrors.ErrInvalidCredentials:
 			respondWithError(w, http.StatusUnauthorized, err.Error())
 		case apperrors.ErrUserInactive:
 			respondWithError(w, http.StatusForbidden, err.Error())
+		case apperrors.ErrSessionLimit:
+			respondWithError(w, http.StatusConflict, err.Error())
 		default:
 			respondWithError(w, http.StatusInternalServerError, "internal server error")
 		}
@@ -91,12 +117,15 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.IPAddress = middleware.ClientIP(r)
+	req.UserAgent = r.UserAgent()
+
 	response, err := h.refreshTokenUseCase.Execute(r.Context(), req)
 	if err != nil {
 		switch err {
-		case apperrors.ErrInvalidToken:
+		case apperrors.ErrInvalidToken, apperrors.ErrTokenRevoked:
 			respondWithError(w, http.StatusUnauthorized, err.Error())
-		case apperrors.ErrExpiredToken:
+		case apperrors.ErrExpiredToken, apperrors.ErrSessionExpired:
 			respondWithError(w, http.StatusUnauthorized, err.Error())
 		case apperrors.ErrTokenReuse:
 			respondWithError(w, http.StatusUnauthorized, "token reuse detected - all tokens revoked")
@@ -111,16 +140,17 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, response)
 }
 
-// Logout handles user logout
+// Logout handles user logout; it ends the current session, or every session with ?all=true
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
-	userID, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
-	if !ok {
+	if _, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID); !ok {
 		respondWithError(w, http.StatusUnauthorized, "unauthorized")
 		return
 	}
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(uuid.UUID)
+	all := r.URL.Query().Get("all") == "true"
 
-	if err := h.logoutUseCase.Execute(r.Context(), userID); err != nil {
+	if err := h.logoutUseCase.Execute(r.Context(), actorFromRequest(r), sessionID, all); err != nil {
 		respondWithError(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
@@ -132,11 +162,11 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	userID, _ := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
 	email, _ := r.Context().Value(middleware.UserEmailKey).(string)
-	roles, _ := r.Context().Value(middleware.UserRolesKey).([]interface{})
+	roles, _ := r.Context().Value(middleware.UserRolesKey).([]entity.Role)
 
 	roleStrings := make([]string, len(roles))
 	for i, role := range roles {
-		roleStrings[i] = role.(string)
+		roleStrings[i] = role.String()
 	}
 
 	response := dto.UserResponse{
@@ -148,6 +178,18 @@ func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, response)
 }
 
+// actorFromRequest identifies the authenticated user performing a request, for the audit trail
+func actorFromRequest(r *http.Request) dto.Actor {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	permissions, _ := r.Context().Value(middleware.PermissionsKey).([]entity.Permission)
+	return dto.Actor{
+		UserID:      userID,
+		IPAddress:   middleware.ClientIP(r),
+		UserAgent:   r.UserAgent(),
+		Permissions: permissions,
+	}
+}
+
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	response, _ := json.Marshal(payload)
 	w.Header().Set("Content-Type", "application/json")
@@ -161,3 +203,35 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 func respondWithError(w http.ResponseWriter, code int, message string) {
 	respondWithJSON(w, code, map[string]string{"error": message})
 }
+
+// respondWithPasswordPolicyError reports every failed password rule so the UI can explain them.
+// It returns false if err is not a password policy error.
+func respondWithPasswordPolicyError(w http.ResponseWriter, err error) bool {
+	var policyErr *apperrors.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	respondWithJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"error":      apperrors.ErrInvalidPassword.Error(),
+		"violations": policyErr.Violations,
+	})
+	return true
+}
+
+// respondWithLockedError answers 429 with a Retry-After header while logins are blocked.
+// It returns false if err is not a lockout error.
+func respondWithLockedError(w http.ResponseWriter, err error) bool {
+	var lockedErr *apperrors.AccountLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(lockedErr.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	respondWithJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+		"error":       lockedErr.Error(),
+		"retry_after": retryAfter,
+	})
+	return true
+}
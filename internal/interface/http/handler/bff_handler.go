@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/interface/http/middleware"
+	apperrors "auth-go/pkg/errors"
+)
+
+// bffAccessTokenKey carries the access token of a proxied request to the reverse proxy
+type bffAccessTokenKey struct{}
+
+// BFFHandler handles the backend-for-frontend: the browser signs in through it and calls
+// upstream services through it, holding nothing but an opaque session cookie
+type BFFHandler struct {
+	bffUseCase    *usecase.BFFUseCase
+	routes        map[string]*url.URL
+	proxies       map[string]*httputil.ReverseProxy
+	secureCookies bool
+}
+
+// NewBFFHandler creates a new BFF handler proxying /bff/api/{name}/... to the upstream of each route
+func NewBFFHandler(bffUseCase *usecase.BFFUseCase, routes map[string]*url.URL, secureCookies bool) *BFFHandler {
+	proxies := make(map[string]*httputil.ReverseProxy, len(routes))
+	for name, upstream := range routes {
+		proxies[name] = newBFFProxy(upstream)
+	}
+
+	return &BFFHandler{
+		bffUseCase:    bffUseCase,
+		routes:        routes,
+		proxies:       proxies,
+		secureCookies: secureCookies,
+	}
+}
+
+// ParseBFFRoutes parses a comma-separated list of NAME=URL routes,
+// e.g. "orders=http://orders:8081,billing=https://billing.internal/api"
+func ParseBFFRoutes(spec string) (map[string]*url.URL, error) {
+	routes := make(map[string]*url.URL)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, rawURL, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid BFF route %q, expected NAME=URL", part)
+		}
+
+		upstream, err := url.Parse(strings.TrimSpace(rawURL))
+		if err != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") || upstream.Host == "" {
+			return nil, fmt.Errorf("invalid BFF route %q: upstream must be an http(s) URL", part)
+		}
+		routes[name] = upstream
+	}
+
+	return routes, nil
+}
+
+// Login handles sign in through the BFF; the tokens stay on the server
+func (h *BFFHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	req.IPAddress = middleware.ClientIP(r)
+	req.UserAgent = r.UserAgent()
+	req.ClientID = middleware.ClientID(r)
+	req.DeviceID = middleware.DeviceID(r)
+
+	sessionToken, session, err := h.bffUseCase.Login(r.Context(), req)
+	if err != nil {
+		if respondWithLockedError(w, err) {
+			return
+		}
+		switch err {
+		case apperrors.ErrInvalidCredentials:
+			respondWithError(w, http.StatusUnauthorized, err.Error())
+		case apperrors.ErrUserInactive:
+			respondWithError(w, http.StatusForbidden, err.Error())
+		case apperrors.ErrSessionLimit:
+			respondWithError(w, http.StatusConflict, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	h.setSessionCookie(w, sessionToken, session.ExpiresAt)
+	respondWithJSON(w, http.StatusOK, toBFFSessionResponse(session))
+}
+
+// Session describes the current BFF session, refreshing its tokens if needed
+func (h *BFFHandler) Session(w http.ResponseWriter, r *http.Request) {
+	sessionToken, session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+
+	h.setSessionCookie(w, sessionToken, session.ExpiresAt)
+	respondWithJSON(w, http.StatusOK, toBFFSessionResponse(session))
+}
+
+// Logout ends the BFF session and its underlying session
+func (h *BFFHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(middleware.BFFSessionCookie)
+	if err != nil || cookie.Value == "" {
+		respondWithError(w, http.StatusUnauthorized, apperrors.ErrUnauthorized.Error())
+		return
+	}
+
+	err = h.bffUseCase.Logout(r.Context(), cookie.Value, actorFromRequest(r))
+	h.clearSessionCookie(w)
+	if err != nil && err != apperrors.ErrSessionNotFound {
+		respondWithError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "logged out successfully"})
+}
+
+// Proxy forwards /bff/api/{name}/{path...} to the upstream of the route with the access token of the session
+func (h *BFFHandler) Proxy(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	proxy, ok := h.proxies[name]
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "unknown BFF route")
+		return
+	}
+	if _, _, ok := bffUpstreamPath(h.routes[name], r); !ok {
+		respondWithError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	sessionToken, session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+
+	h.setSessionCookie(w, sessionToken, session.ExpiresAt)
+	proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), bffAccessTokenKey{}, session.AccessToken)))
+}
+
+// session loads the BFF session of the request's cookie, responding with an error if there is none
+func (h *BFFHandler) session(w http.ResponseWriter, r *http.Request) (string, *entity.BFFSession, bool) {
+	cookie, err := r.Cookie(middleware.BFFSessionCookie)
+	if err != nil || cookie.Value == "" {
+		respondWithError(w, http.StatusUnauthorized, apperrors.ErrUnauthorized.Error())
+		return "", nil, false
+	}
+
+	session, err := h.bffUseCase.Session(r.Context(), cookie.Value, actorFromRequest(r))
+	if err != nil {
+		switch err {
+		case apperrors.ErrSessionNotFound, apperrors.ErrInvalidToken, apperrors.ErrTokenRevoked, apperrors.ErrExpiredToken,
+			apperrors.ErrSessionExpired, apperrors.ErrTokenReuse:
+			h.clearSessionCookie(w)
+			respondWithError(w, http.StatusUnauthorized, apperrors.ErrUnauthorized.Error())
+		case apperrors.ErrUserInactive:
+			h.clearSessionCookie(w)
+			respondWithError(w, http.StatusForbidden, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return "", nil, false
+	}
+
+	return cookie.Value, session, true
+}
+
+// setSessionCookie stores the session token in a cookie that scripts cannot read and other sites cannot send
+func (h *BFFHandler) setSessionCookie(w http.ResponseWriter, sessionToken string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.BFFSessionCookie,
+		Value:    sessionToken,
+		Path:     "/bff/",
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// clearSessionCookie makes the browser drop the session cookie
+func (h *BFFHandler) clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.BFFSessionCookie,
+		Path:     "/bff/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// newBFFProxy creates a reverse proxy to an upstream service. Browser credentials are
+// replaced by the access token, and upstream cookies are not passed back to the browser.
+func newBFFProxy(upstream *url.URL) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(upstream)
+			// Proxy only validates paths, so the ok result is always true here
+			pr.Out.URL.Path, pr.Out.URL.RawPath, _ = bffUpstreamPath(upstream, pr.In)
+			pr.SetXForwarded()
+
+			pr.Out.Header.Del("Cookie")
+			pr.Out.Header.Del(middleware.CSRFTokenHeader)
+			accessToken, _ := pr.In.Context().Value(bffAccessTokenKey{}).(string)
+			pr.Out.Header.Set("Authorization", "Bearer "+accessToken)
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			resp.Header.Del("Set-Cookie")
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Printf("BFF upstream %s failed: %v", upstream.Host, err)
+			respondWithError(w, http.StatusBadGateway, "upstream service unavailable")
+		},
+	}
+}
+
+// bffUpstreamPath maps the {path...} of a proxied request below the upstream's base path,
+// returning the path and its escaped form as sent by the browser. It fails if the path
+// leaves the base path, e.g. through an encoded "..%2F" that the router does not clean.
+func bffUpstreamPath(upstream *url.URL, r *http.Request) (string, string, bool) {
+	base := strings.TrimSuffix(upstream.Path, "/")
+	upstreamPath := base + "/" + r.PathValue("path")
+	if cleaned := path.Clean(upstreamPath); cleaned != base && !strings.HasPrefix(cleaned, base+"/") {
+		return "", "", false
+	}
+
+	// /bff/api/{name}/{path...}: the escaped path has the same segments
+	segments := strings.SplitN(r.URL.EscapedPath(), "/", 5)
+	if len(segments) < 5 {
+		return "", "", false
+	}
+	rawPath := strings.TrimSuffix(upstream.EscapedPath(), "/") + "/" + segments[4]
+
+	return upstreamPath, rawPath, true
+}
+
+// toBFFSessionResponse describes a BFF session without its tokens
+func toBFFSessionResponse(session *entity.BFFSession) dto.BFFSessionResponse {
+	return dto.BFFSessionResponse{
+		UserID:    session.UserID.String(),
+		SessionID: session.SessionID.String(),
+		ExpiresAt: session.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestBFFUpstreamPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		upstream    string
+		requestPath string
+		wantPath    string
+		wantRawPath string
+		wantOK      bool
+	}{
+		{name: "root upstream", upstream: "http://orders:8081", requestPath: "/bff/api/orders/v1/orders", wantPath: "/v1/orders", wantRawPath: "/v1/orders", wantOK: true},
+		{name: "base path", upstream: "https://billing.internal/api/", requestPath: "/bff/api/billing/invoices/42", wantPath: "/api/invoices/42", wantRawPath: "/api/invoices/42", wantOK: true},
+		{name: "escapes are kept", upstream: "https://billing.internal/api", requestPath: "/bff/api/billing/files/a%2Fb%20c", wantPath: "/api/files/a/b c", wantRawPath: "/api/files/a%2Fb%20c", wantOK: true},
+		{name: "encoded dot segment inside the base path", upstream: "https://billing.internal/api", requestPath: "/bff/api/billing/a/..%2Fb", wantPath: "/api/a/../b", wantRawPath: "/api/a/..%2Fb", wantOK: true},
+		{name: "encoded traversal out of the base path", upstream: "https://billing.internal/api", requestPath: "/bff/api/billing/..%2Fadmin", wantOK: false},
+		{name: "encoded traversal to a sibling prefix", upstream: "https://billing.internal/api", requestPath: "/bff/api/billing/..%2Fapi-internal", wantOK: false},
+		{name: "encoded dots", upstream: "https://billing.internal/api", requestPath: "/bff/api/billing/%2E%2E%2F%2E%2E%2Fadmin", wantOK: false},
+		{name: "traversal at a root upstream stays at the root", upstream: "http://orders:8081", requestPath: "/bff/api/orders/..%2Fv1", wantPath: "/../v1", wantRawPath: "/..%2Fv1", wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream, err := url.Parse(tt.upstream)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotPath, gotRawPath string
+			gotOK := false
+			mux := http.NewServeMux()
+			mux.HandleFunc("/bff/api/{name}/{path...}", func(w http.ResponseWriter, r *http.Request) {
+				gotPath, gotRawPath, gotOK = bffUpstreamPath(upstream, r)
+			})
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.requestPath, nil))
+
+			if gotOK != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", gotOK, tt.wantOK)
+			}
+			if gotPath != tt.wantPath || gotRawPath != tt.wantRawPath {
+				t.Errorf("path = %q (raw %q), want %q (raw %q)", gotPath, gotRawPath, tt.wantPath, tt.wantRawPath)
+			}
+		})
+	}
+}
+
+func TestBFFProxy_Rewrite(t *testing.T) {
+	var got *http.Request
+	upstreamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		http.SetCookie(w, &http.Cookie{Name: "upstream", Value: "x"})
+	}))
+	defer upstreamServer.Close()
+
+	upstream, err := url.Parse(upstreamServer.URL + "/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := newBFFProxy(upstream)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bff/api/{name}/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), bffAccessTokenKey{}, "access-token")))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/bff/api/billing/files/a%2Fb?page=2", nil)
+	req.Header.Set("Cookie", "bff_session=secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if got == nil {
+		t.Fatalf("upstream not called, status %d", rec.Code)
+	}
+	if raw := got.URL.EscapedPath(); raw != "/api/files/a%2Fb" {
+		t.Errorf("upstream path = %q, want %q", raw, "/api/files/a%2Fb")
+	}
+	if got.URL.RawQuery != "page=2" {
+		t.Errorf("upstream query = %q, want %q", got.URL.RawQuery, "page=2")
+	}
+	if auth := got.Header.Get("Authorization"); auth != "Bearer access-token" {
+		t.Errorf("Authorization = %q, want the session's access token", auth)
+	}
+	if cookie := got.Header.Get("Cookie"); cookie != "" {
+		t.Errorf("browser cookies forwarded upstream: %q", cookie)
+	}
+	if setCookie := rec.Header().Get("Set-Cookie"); setCookie != "" {
+		t.Errorf("upstream cookies passed to the browser: %q", setCookie)
+	}
+}
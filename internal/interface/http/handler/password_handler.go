@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/application/usecase"
+	apperrors "auth-go/pkg/errors"
+)
+
+// PasswordHandler handles password recovery HTTP requests
+type PasswordHandler struct {
+	forgotPasswordUseCase *usecase.ForgotPasswordUseCase
+	resetPasswordUseCase  *usecase.ResetPasswordUseCase
+}
+
+// NewPasswordHandler creates a new password handler
+func NewPasswordHandler(
+	forgotPasswordUseCase *usecase.ForgotPasswordUseCase,
+	resetPasswordUseCase *usecase.ResetPasswordUseCase,
+) *PasswordHandler {
+	return &PasswordHandler{
+		forgotPasswordUseCase: forgotPasswordUseCase,
+		resetPasswordUseCase:  resetPasswordUseCase,
+	}
+}
+
+// ForgotPassword handles password reset link requests.
+// It always answers 202 so it cannot be used to find out whether an account exists.
+func (h *PasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.forgotPasswordUseCase.Execute(r.Context(), req); err != nil && err != apperrors.ErrInvalidEmail {
+		log.Printf("Error processing forgot password request: %v", err)
+	}
+
+	respondWithJSON(w, http.StatusAccepted, map[string]string{
+		"message": "if an account exists for this email, a password reset link has been sent",
+	})
+}
+
+// ResetPassword handles setting a new password with a reset token
+func (h *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.resetPasswordUseCase.Execute(r.Context(), req); err != nil {
+		if respondWithPasswordPolicyError(w, err) {
+			return
+		}
+		switch err {
+		case apperrors.ErrInvalidPassword:
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		case apperrors.ErrInvalidToken, apperrors.ErrExpiredToken:
+			respondWithError(w, http.StatusBadRequest, "invalid or expired reset token")
+		case apperrors.ErrUserInactive:
+			respondWithError(w, http.StatusForbidden, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "password has been reset successfully"})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+)
+
+// RoleHandler handles role administration HTTP requests
+type RoleHandler struct {
+	roleRepo          repository.RoleRepository
+	createRoleUseCase *usecase.CreateRoleUseCase
+	updateRoleUseCase *usecase.UpdateRoleUseCase
+	deleteRoleUseCase *usecase.DeleteRoleUseCase
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(
+	roleRepo repository.RoleRepository,
+	createRoleUseCase *usecase.CreateRoleUseCase,
+	updateRoleUseCase *usecase.UpdateRoleUseCase,
+	deleteRoleUseCase *usecase.DeleteRoleUseCase,
+) *RoleHandler {
+	return &RoleHandler{
+		roleRepo:          roleRepo,
+		createRoleUseCase: createRoleUseCase,
+		updateRoleUseCase: updateRoleUseCase,
+		deleteRoleUseCase: deleteRoleUseCase,
+	}
+}
+
+// ListRoles lists every role (admin only)
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	definitions, err := h.roleRepo.FindAll(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "failed to fetch roles")
+		return
+	}
+
+	roles := entity.NewRoleSet(definitions)
+	response := make([]dto.RoleResponse, len(definitions))
+	for i, definition := range definitions {
+		response[i] = toRoleResponse(definition, roles)
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"roles":       response,
+		"permissions": entity.AllPermissions,
+	})
+}
+
+// GetRole returns a single role (admin only)
+func (h *RoleHandler) GetRole(w http.ResponseWriter, r *http.Request) {
+	role, err := h.roleRepo.FindByName(r.Context(), entity.Role(r.PathValue("name")))
+	if err != nil {
+		respondWithRoleError(w, err)
+		return
+	}
+
+	h.respondWithRole(w, r, http.StatusOK, role)
+}
+
+// CreateRole defines a new role (admin only)
+func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	role, err := h.createRoleUseCase.Execute(r.Context(), actorFromRequest(r), req)
+	if err != nil {
+		respondWithRoleError(w, err)
+		return
+	}
+
+	h.respondWithRole(w, r, http.StatusCreated, role)
+}
+
+// UpdateRole changes the description, permissions and parent of a role (admin only)
+func (h *RoleHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	role, err := h.updateRoleUseCase.Execute(r.Context(), actorFromRequest(r), r.PathValue("name"), req)
+	if err != nil {
+		respondWithRoleError(w, err)
+		return
+	}
+
+	h.respondWithRole(w, r, http.StatusOK, role)
+}
+
+// DeleteRole removes a role that no user holds and no role inherits from (admin only)
+func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	if err := h.deleteRoleUseCase.Execute(r.Context(), actorFromRequest(r), r.PathValue("name")); err != nil {
+		respondWithRoleError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "role deleted successfully"})
+}
+
+// respondWithRole writes a role, resolving its effective permissions against the current role definitions
+func (h *RoleHandler) respondWithRole(w http.ResponseWriter, r *http.Request, status int, role *entity.RoleDefinition) {
+	definitions, err := h.roleRepo.FindAll(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "failed to fetch roles")
+		return
+	}
+
+	respondWithJSON(w, status, toRoleResponse(role, entity.NewRoleSet(definitions)))
+}
+
+// respondWithRoleError maps errors of role use cases to HTTP responses
+func respondWithRoleError(w http.ResponseWriter, err error) {
+	switch err {
+	case apperrors.ErrInvalidInput:
+		respondWithError(w, http.StatusBadRequest, "invalid role, expected a lowercase name and known permissions")
+	case apperrors.ErrInvalidParentRole:
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case apperrors.ErrForbidden:
+		respondWithError(w, http.StatusForbidden, "built-in roles cannot be deleted, the admin role keeps every permission and roles cannot grant permissions you do not hold")
+	case apperrors.ErrRoleNotFound:
+		respondWithError(w, http.StatusNotFound, err.Error())
+	case apperrors.ErrRoleAlreadyExists, apperrors.ErrRoleInUse:
+		respondWithError(w, http.StatusConflict, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// toRoleResponse converts a role to its API representation
+func toRoleResponse(role *entity.RoleDefinition, roles entity.RoleSet) dto.RoleResponse {
+	response := dto.RoleResponse{
+		Name:                 role.Name.String(),
+		Description:          role.Description,
+		Permissions:          permissionStrings(role.Permissions),
+		EffectivePermissions: permissionStrings(roles.PermissionsOf([]entity.Role{role.Name})),
+		BuiltIn:              role.Name.IsBuiltIn(),
+		CreatedAt:            role.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:            role.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if role.Parent != nil {
+		parent := role.Parent.String()
+		response.Parent = &parent
+	}
+
+	return response
+}
+
+// permissionStrings converts permissions to their string representation
+func permissionStrings(permissions []entity.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return names
+}
@@ -6,44 +6,67 @@ import (
 	"log"
 	"net/http"
 	"path/filepath"
+	"time"
 
 	"auth-go/internal/application/dto"
 	"auth-go/internal/application/usecase"
 	"auth-go/internal/domain/repository"
 	"auth-go/internal/interface/http/middleware"
+	apperrors "auth-go/pkg/errors"
 
 	"github.com/google/uuid"
 )
 
+// WebCookieOptions configures the web UI cookie mode
+type WebCookieOptions struct {
+	// Enabled keeps the tokens in HttpOnly cookies instead of the browser's localStorage
+	Enabled bool
+	Secure  bool
+	// RefreshTokenExpiry bounds the refresh token cookie; the server still enforces the token's own expiry
+	RefreshTokenExpiry time.Duration
+}
+
 // WebHandler handles web UI requests
 type WebHandler struct {
 	templates           *template.Template
+	loginUseCase        *usecase.LoginUseCase
 	logoutUseCase       *usecase.LogoutUseCase
 	refreshTokenUseCase *usecase.RefreshTokenUseCase
 	userRepo            repository.UserRepository
+	cookies             WebCookieOptions
 }
 
 // NewWebHandler creates a new web handler
 func NewWebHandler(
+	loginUseCase *usecase.LoginUseCase,
 	logoutUseCase *usecase.LogoutUseCase,
 	refreshTokenUseCase *usecase.RefreshTokenUseCase,
 	userRepo repository.UserRepository,
+	cookies WebCookieOptions,
 ) *WebHandler {
 	// Parse all templates
 	templates := template.Must(template.ParseGlob(filepath.Join("web", "templates", "*.html")))
 
 	return &WebHandler{
 		templates:           templates,
+		loginUseCase:        loginUseCase,
 		logoutUseCase:       logoutUseCase,
 		refreshTokenUseCase: refreshTokenUseCase,
 		userRepo:            userRepo,
+		cookies:             cookies,
 	}
 }
 
+// CookieMode reports whether the web UI keeps its tokens in cookies
+func (h *WebHandler) CookieMode() bool {
+	return h.cookies.Enabled
+}
+
 // ServeLogin serves the login page
 func (h *WebHandler) ServeLogin(w http.ResponseWriter, r *http.Request) {
 	data := map[string]interface{}{
-		"Title": "Login",
+		"Title":      "Login",
+		"CookieMode": h.cookies.Enabled,
 	}
 	// Parse login template with layout
 	t := template.Must(template.ParseFiles(
@@ -58,7 +81,8 @@ func (h *WebHandler) ServeLogin(w http.ResponseWriter, r *http.Request) {
 // ServeRegister serves the register page
 func (h *WebHandler) ServeRegister(w http.ResponseWriter, r *http.Request) {
 	data := map[string]interface{}{
-		"Title": "Register",
+		"Title":      "Register",
+		"CookieMode": h.cookies.Enabled,
 	}
 	// Parse register template with layout
 	t := template.Must(template.ParseFiles(
@@ -70,11 +94,62 @@ func (h *WebHandler) ServeRegister(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ServeForgotPassword serves the forgot password page
+func (h *WebHandler) ServeForgotPassword(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"Title":      "Forgot Password",
+		"CookieMode": h.cookies.Enabled,
+	}
+	// Parse forgot password template with layout
+	t := template.Must(template.ParseFiles(
+		filepath.Join("web", "templates", "layout.html"),
+		filepath.Join("web", "templates", "forgot_password.html"),
+	))
+	if err := t.ExecuteTemplate(w, "layout.html", data); err != nil {
+		log.Printf("Error executing forgot password template: %v", err)
+	}
+}
+
+// ServeResetPassword serves the reset password page (linked from the reset email)
+func (h *WebHandler) ServeResetPassword(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"Title":      "Reset Password",
+		"Token":      r.URL.Query().Get("token"),
+		"CookieMode": h.cookies.Enabled,
+	}
+	// Parse reset password template with layout
+	t := template.Must(template.ParseFiles(
+		filepath.Join("web", "templates", "layout.html"),
+		filepath.Join("web", "templates", "reset_password.html"),
+	))
+	if err := t.ExecuteTemplate(w, "layout.html", data); err != nil {
+		log.Printf("Error executing reset password template: %v", err)
+	}
+}
+
+// ServeVerifyEmail serves the email verification page (linked from the email change message)
+func (h *WebHandler) ServeVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"Title":      "Verify Email",
+		"Token":      r.URL.Query().Get("token"),
+		"CookieMode": h.cookies.Enabled,
+	}
+	// Parse verify email template with layout
+	t := template.Must(template.ParseFiles(
+		filepath.Join("web", "templates", "layout.html"),
+		filepath.Join("web", "templates", "verify_email.html"),
+	))
+	if err := t.ExecuteTemplate(w, "layout.html", data); err != nil {
+		log.Printf("Error executing verify email template: %v", err)
+	}
+}
+
 // ServeDashboard serves the dashboard page
 func (h *WebHandler) ServeDashboard(w http.ResponseWriter, r *http.Request) {
 	data := map[string]interface{}{
-		"Title":     "Dashboard",
-		"Dashboard": true,
+		"Title":      "Dashboard",
+		"Dashboard":  true,
+		"CookieMode": h.cookies.Enabled,
 	}
 	// Parse dashboard template with layout
 	t := template.Must(template.ParseFiles(
@@ -89,8 +164,9 @@ func (h *WebHandler) ServeDashboard(w http.ResponseWriter, r *http.Request) {
 // ServeProfile serves the profile page
 func (h *WebHandler) ServeProfile(w http.ResponseWriter, r *http.Request) {
 	data := map[string]interface{}{
-		"Title":     "Profile",
-		"Dashboard": true,
+		"Title":      "Profile",
+		"Dashboard":  true,
+		"CookieMode": h.cookies.Enabled,
 	}
 	// Parse profile template with layout
 	t := template.Must(template.ParseFiles(
@@ -208,22 +284,121 @@ func (h *WebHandler) ServeProfileData(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// HandleLogout handles logout from web UI
+// HandleLogout handles logout from web UI (current session only)
 func (h *WebHandler) HandleLogout(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
-	if !ok {
+	if _, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID); !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(uuid.UUID)
 
-	if err := h.logoutUseCase.Execute(r.Context(), userID); err != nil {
+	if err := h.logoutUseCase.Execute(r.Context(), actorFromRequest(r), sessionID, false); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	if h.cookies.Enabled {
+		h.clearSessionCookies(w)
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
+// HandleSessionLogin signs in from the web UI in cookie mode; the tokens are set as
+// HttpOnly cookies and never reach the page's JavaScript
+func (h *WebHandler) HandleSessionLogin(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	req.IPAddress = middleware.ClientIP(r)
+	req.UserAgent = r.UserAgent()
+	req.ClientID = middleware.ClientID(r)
+	req.DeviceID = middleware.DeviceID(r)
+
+	response, err := h.loginUseCase.Execute(r.Context(), req)
+	if err != nil {
+		if respondWithLockedError(w, err) {
+			return
+		}
+		switch err {
+		case apperrors.ErrInvalidCredentials:
+			respondWithError(w, http.StatusUnauthorized, err.Error())
+		case apperrors.ErrUserInactive:
+			respondWithError(w, http.StatusForbidden, err.Error())
+		case apperrors.ErrSessionLimit:
+			respondWithError(w, http.StatusConflict, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	h.setSessionCookies(w, response)
+	respondWithJSON(w, http.StatusOK, map[string]int64{"expires_in": response.ExpiresIn})
+}
+
+// HandleSessionRefresh rotates the refresh token cookie and renews the access token cookie
+func (h *WebHandler) HandleSessionRefresh(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(middleware.RefreshTokenCookie)
+	if err != nil || cookie.Value == "" {
+		respondWithError(w, http.StatusUnauthorized, apperrors.ErrUnauthorized.Error())
+		return
+	}
+
+	response, err := h.refreshTokenUseCase.Execute(r.Context(), dto.RefreshTokenRequest{
+		RefreshToken: cookie.Value,
+		IPAddress:    middleware.ClientIP(r),
+		UserAgent:    r.UserAgent(),
+	})
+	if err != nil {
+		// Only a spent or revoked refresh token makes the browser sign in again;
+		// after a server error the same token can be retried
+		switch err {
+		case apperrors.ErrUserInactive:
+			h.clearSessionCookies(w)
+			respondWithError(w, http.StatusForbidden, err.Error())
+		case apperrors.ErrInvalidToken, apperrors.ErrTokenRevoked, apperrors.ErrExpiredToken, apperrors.ErrSessionExpired,
+			apperrors.ErrTokenReuse:
+			h.clearSessionCookies(w)
+			respondWithError(w, http.StatusUnauthorized, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	h.setSessionCookies(w, response)
+	respondWithJSON(w, http.StatusOK, map[string]int64{"expires_in": response.ExpiresIn})
+}
+
+// setSessionCookies stores the tokens in HttpOnly cookies: the access token is sent to
+// all web UI routes, the refresh token only to the session endpoints
+func (h *WebHandler) setSessionCookies(w http.ResponseWriter, response *dto.AuthResponse) {
+	http.SetCookie(w, h.sessionCookie(middleware.AccessTokenCookie, response.AccessToken, "/web/", int(response.ExpiresIn)))
+	http.SetCookie(w, h.sessionCookie(middleware.RefreshTokenCookie, response.RefreshToken, "/web/session", int(h.cookies.RefreshTokenExpiry.Seconds())))
+}
+
+// clearSessionCookies makes the browser drop both token cookies
+func (h *WebHandler) clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, h.sessionCookie(middleware.AccessTokenCookie, "", "/web/", -1))
+	http.SetCookie(w, h.sessionCookie(middleware.RefreshTokenCookie, "", "/web/session", -1))
+}
+
+// sessionCookie builds a token cookie that scripts cannot read and other sites cannot send
+func (h *WebHandler) sessionCookie(name, value, path string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.cookies.Secure,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
+
 // HandleRefreshToken handles token refresh from web UI
 func (h *WebHandler) HandleRefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -243,6 +418,8 @@ func (h *WebHandler) HandleRefreshToken(w http.ResponseWriter, r *http.Request)
 	// Use the refresh token use case
 	response, err := h.refreshTokenUseCase.Execute(r.Context(), dto.RefreshTokenRequest{
 		RefreshToken: req.RefreshToken,
+		IPAddress:    middleware.ClientIP(r),
+		UserAgent:    r.UserAgent(),
 	})
 
 	if err != nil {
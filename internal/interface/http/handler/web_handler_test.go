@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/interface/http/middleware"
+	apperrors "auth-go/pkg/errors"
+)
+
+// failingTxManager fails every unit of work with err
+type failingTxManager struct {
+	err error
+}
+
+func (m failingTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.err
+}
+
+// failingRefreshTokenUseCase returns a refresh token use case whose every execution fails with err
+func failingRefreshTokenUseCase(err error) *usecase.RefreshTokenUseCase {
+	return usecase.NewRefreshTokenUseCase(nil, nil, nil, failingTxManager{err: err}, nil, nil, nil, entity.SessionLifetimePolicy{}, nil, 0)
+}
+
+func TestWebHandler_HandleSessionRefresh(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantStatus    int
+		wantCleared   bool
+		withoutCookie bool
+	}{
+		{name: "no refresh token cookie", withoutCookie: true, wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", err: apperrors.ErrInvalidToken, wantStatus: http.StatusUnauthorized, wantCleared: true},
+		{name: "revoked token", err: apperrors.ErrTokenRevoked, wantStatus: http.StatusUnauthorized, wantCleared: true},
+		{name: "reused token", err: apperrors.ErrTokenReuse, wantStatus: http.StatusUnauthorized, wantCleared: true},
+		{name: "expired session", err: apperrors.ErrSessionExpired, wantStatus: http.StatusUnauthorized, wantCleared: true},
+		{name: "inactive user", err: apperrors.ErrUserInactive, wantStatus: http.StatusForbidden, wantCleared: true},
+		// The same refresh token can be retried once the server recovers
+		{name: "server error", err: errors.New("database unavailable"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &WebHandler{
+				refreshTokenUseCase: failingRefreshTokenUseCase(tt.err),
+				cookies:             WebCookieOptions{Enabled: true, RefreshTokenExpiry: time.Hour},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/web/session/refresh", nil)
+			if !tt.withoutCookie {
+				req.AddCookie(&http.Cookie{Name: middleware.RefreshTokenCookie, Value: "refresh-token"})
+			}
+			rec := httptest.NewRecorder()
+			h.HandleSessionRefresh(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			cleared := map[string]bool{}
+			for _, cookie := range rec.Result().Cookies() {
+				cleared[cookie.Name] = cookie.MaxAge < 0
+			}
+			for _, name := range []string{middleware.AccessTokenCookie, middleware.RefreshTokenCookie} {
+				if cleared[name] != tt.wantCleared {
+					t.Errorf("cookie %s cleared = %v, want %v", name, cleared[name], tt.wantCleared)
+				}
+			}
+		})
+	}
+}
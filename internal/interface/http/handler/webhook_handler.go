@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"auth-go/internal/application/dto"
+	"auth-go/internal/application/usecase"
+	"auth-go/internal/domain/entity"
+	"auth-go/internal/domain/repository"
+	apperrors "auth-go/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultDeliveryPageSize = 50
+	maxDeliveryPageSize     = 200
+)
+
+// WebhookHandler handles webhook administration HTTP requests
+type WebhookHandler struct {
+	endpointRepo            repository.WebhookEndpointRepository
+	deliveryRepo            repository.WebhookDeliveryRepository
+	createWebhookUseCase    *usecase.CreateWebhookUseCase
+	updateWebhookUseCase    *usecase.UpdateWebhookUseCase
+	deleteWebhookUseCase    *usecase.DeleteWebhookUseCase
+	redeliverWebhookUseCase *usecase.RedeliverWebhookUseCase
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(
+	endpointRepo repository.WebhookEndpointRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	createWebhookUseCase *usecase.CreateWebhookUseCase,
+	updateWebhookUseCase *usecase.UpdateWebhookUseCase,
+	deleteWebhookUseCase *usecase.DeleteWebhookUseCase,
+	redeliverWebhookUseCase *usecase.RedeliverWebhookUseCase,
+) *WebhookHandler {
+	return &WebhookHandler{
+		endpointRepo:            endpointRepo,
+		deliveryRepo:            deliveryRepo,
+		createWebhookUseCase:    createWebhookUseCase,
+		updateWebhookUseCase:    updateWebhookUseCase,
+		deleteWebhookUseCase:    deleteWebhookUseCase,
+		redeliverWebhookUseCase: redeliverWebhookUseCase,
+	}
+}
+
+// ListWebhooks lists every webhook endpoint (admin only)
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := h.endpointRepo.FindAll(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "failed to fetch webhooks")
+		return
+	}
+
+	response := make([]dto.WebhookResponse, len(endpoints))
+	for i, endpoint := range endpoints {
+		response[i] = toWebhookResponse(endpoint, false)
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"webhooks":    response,
+		"event_types": entity.WebhookEventTypes,
+	})
+}
+
+// GetWebhook returns a single webhook endpoint (admin only)
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := pathUUID(w, r, "id", "invalid webhook id")
+	if !ok {
+		return
+	}
+
+	endpoint, err := h.endpointRepo.FindByID(r.Context(), webhookID)
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toWebhookResponse(endpoint, false))
+}
+
+// CreateWebhook subscribes an endpoint to webhook events; the signing secret is only returned here (admin only)
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	endpoint, err := h.createWebhookUseCase.Execute(r.Context(), actorFromRequest(r), req)
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, toWebhookResponse(endpoint, true))
+}
+
+// UpdateWebhook changes a webhook endpoint, optionally rotating its secret (admin only)
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := pathUUID(w, r, "id", "invalid webhook id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	endpoint, err := h.updateWebhookUseCase.Execute(r.Context(), actorFromRequest(r), webhookID, req)
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toWebhookResponse(endpoint, req.RotateSecret))
+}
+
+// DeleteWebhook removes a webhook endpoint and its delivery log (admin only)
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := pathUUID(w, r, "id", "invalid webhook id")
+	if !ok {
+		return
+	}
+
+	if err := h.deleteWebhookUseCase.Execute(r.Context(), actorFromRequest(r), webhookID); err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "webhook deleted successfully"})
+}
+
+// ListDeliveries lists one page of the delivery log of an endpoint, newest first (admin only)
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := pathUUID(w, r, "id", "invalid webhook id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.endpointRepo.FindByID(r.Context(), webhookID); err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	query, err := parseWebhookDeliveryQuery(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query.EndpointID = &webhookID
+
+	deliveries, err := h.deliveryRepo.Search(r.Context(), query)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "failed to fetch webhook deliveries")
+		return
+	}
+
+	response := dto.WebhookDeliveryListResponse{Deliveries: make([]dto.WebhookDeliveryResponse, len(deliveries))}
+	for i, delivery := range deliveries {
+		response.Deliveries[i] = toWebhookDeliveryResponse(delivery)
+	}
+	if len(deliveries) == query.Limit {
+		response.NextCursor = deliveries[len(deliveries)-1].ID.String()
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// RedeliverWebhook queues a delivery for another attempt (admin only)
+func (h *WebhookHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := pathUUID(w, r, "id", "invalid webhook id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := pathUUID(w, r, "deliveryID", "invalid delivery id")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.redeliverWebhookUseCase.Execute(r.Context(), actorFromRequest(r), webhookID, deliveryID)
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, toWebhookDeliveryResponse(delivery))
+}
+
+// parseWebhookDeliveryQuery builds a delivery log search from query parameters:
+// status (pending, delivered or dead), event_type, limit and cursor
+func parseWebhookDeliveryQuery(values url.Values) (repository.WebhookDeliveryQuery, error) {
+	query := repository.WebhookDeliveryQuery{
+		Status: entity.WebhookDeliveryStatus(values.Get("status")),
+		Limit:  defaultDeliveryPageSize,
+	}
+
+	switch query.Status {
+	case "", entity.WebhookDeliveryPending, entity.WebhookDeliveryDelivered, entity.WebhookDeliveryDead:
+	default:
+		return query, errors.New("invalid status, expected pending, delivered or dead")
+	}
+
+	if eventType := values.Get("event_type"); eventType != "" {
+		if !entity.IsValidWebhookEventType(eventType) {
+			return query, errors.New("invalid event_type")
+		}
+		query.EventType = entity.WebhookEventType(eventType)
+	}
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		before, err := uuid.Parse(cursor)
+		if err != nil {
+			return query, errors.New("invalid cursor")
+		}
+		query.Before = &before
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxDeliveryPageSize {
+			return query, fmt.Errorf("invalid limit, expected 1 to %d", maxDeliveryPageSize)
+		}
+		query.Limit = n
+	}
+
+	return query, nil
+}
+
+// respondWithWebhookError maps errors of webhook use cases to HTTP responses
+func respondWithWebhookError(w http.ResponseWriter, err error) {
+	switch err {
+	case apperrors.ErrInvalidInput:
+		respondWithError(w, http.StatusBadRequest, "invalid webhook, expected an http(s) url and at least one known event type")
+	case apperrors.ErrWebhookNotFound, apperrors.ErrWebhookDeliveryNotFound:
+		respondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// pathUUID parses a UUID path parameter, answering 400 with message if it is invalid
+func pathUUID(w http.ResponseWriter, r *http.Request, name, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, message)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// toWebhookResponse converts a webhook endpoint to its API representation
+func toWebhookResponse(endpoint *entity.WebhookEndpoint, withSecret bool) dto.WebhookResponse {
+	eventTypes := make([]string, len(endpoint.EventTypes))
+	for i, eventType := range endpoint.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	response := dto.WebhookResponse{
+		ID:          endpoint.ID.String(),
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		EventTypes:  eventTypes,
+		IsActive:    endpoint.IsActive,
+		CreatedAt:   endpoint.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   endpoint.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if withSecret {
+		response.Secret = endpoint.Secret
+	}
+
+	return response
+}
+
+// toWebhookDeliveryResponse converts a delivery to its delivery log representation
+func toWebhookDeliveryResponse(delivery *entity.WebhookDelivery) dto.WebhookDeliveryResponse {
+	response := dto.WebhookDeliveryResponse{
+		ID:             delivery.ID.String(),
+		EndpointID:     delivery.EndpointID.String(),
+		EventID:        delivery.EventID.String(),
+		EventType:      string(delivery.EventType),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+		LastAttemptAt:  optionalTime(delivery.LastAttemptAt),
+		DeliveredAt:    optionalTime(delivery.DeliveredAt),
+	}
+	if delivery.Status == entity.WebhookDeliveryPending {
+		response.NextAttemptAt = optionalTime(&delivery.NextAttemptAt)
+	}
+	if err := json.Unmarshal(delivery.Payload, &response.Payload); err != nil {
+		response.Payload = map[string]interface{}{}
+	}
+
+	return response
+}
+
+func optionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
@@ -2,9 +2,11 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"auth-go/internal/domain/entity"
 	"auth-go/internal/domain/service"
@@ -17,39 +19,54 @@ const (
 	UserIDKey    contextKey = "user_id"
 	UserEmailKey contextKey = "user_email"
 	UserRolesKey contextKey = "user_roles"
+	SessionIDKey contextKey = "session_id"
+	AuthTimeKey  contextKey = "auth_time"
+	ACRKey       contextKey = "acr"
+	AMRKey       contextKey = "amr"
+	// PermissionsKey holds the permissions embedded in the access token, if any
+	PermissionsKey contextKey = "permissions"
+
+	// cookieAuthKey marks requests that may authenticate with the access token cookie
+	cookieAuthKey contextKey = "cookie_auth"
 )
 
 // AuthMiddleware provides JWT authentication middleware
 type AuthMiddleware struct {
 	tokenService service.TokenService
+	// permissions resolves the permissions of tokens that do not embed them
+	permissions service.PermissionResolver
 }
 
 // NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(tokenService service.TokenService) *AuthMiddleware {
+func NewAuthMiddleware(tokenService service.TokenService, permissions service.PermissionResolver) *AuthMiddleware {
 	return &AuthMiddleware{
 		tokenService: tokenService,
+		permissions:  permissions,
 	}
 }
 
 // Authenticate validates JWT token and adds user context
 func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract token from Authorization header
+		// Extract token from Authorization header, or from the cookie on web UI routes
+		var token string
 		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			respondWithError(w, http.StatusUnauthorized, apperrors.ErrUnauthorized.Error())
-			return
+		if authHeader != "" {
+			// Check Bearer prefix
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				respondWithError(w, http.StatusUnauthorized, "invalid authorization header format")
+				return
+			}
+			token = parts[1]
+		} else if cookie, err := r.Cookie(AccessTokenCookie); err == nil && r.Context().Value(cookieAuthKey) == true {
+			token = cookie.Value
 		}
-
-		// Check Bearer prefix
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			respondWithError(w, http.StatusUnauthorized, "invalid authorization header format")
+		if token == "" {
+			respondWithError(w, http.StatusUnauthorized, apperrors.ErrUnauthorized.Error())
 			return
 		}
 
-		token := parts[1]
-
 		// Validate token
 		claims, err := m.tokenService.ValidateAccessToken(token)
 		if err != nil {
@@ -62,36 +79,101 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
 		ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
 		ctx = context.WithValue(ctx, UserRolesKey, claims.Roles)
+		ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
+		ctx = context.WithValue(ctx, AuthTimeKey, claims.AuthTime)
+		ctx = context.WithValue(ctx, ACRKey, claims.ACR)
+		ctx = context.WithValue(ctx, AMRKey, claims.AMR)
+		if claims.Permissions != nil {
+			ctx = context.WithValue(ctx, PermissionsKey, claims.Permissions)
+		}
 
 		// Continue with authenticated request
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// RequireRole checks if user has required role (RBAC)
+// AcceptCookies lets Authenticate fall back to the access token cookie of the web UI
+// cookie mode; only use it on routes behind the CSRF middleware
+func (m *AuthMiddleware) AcceptCookies(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), cookieAuthKey, true)))
+	})
+}
+
+// RequireRole checks if user has the given role (RBAC); prefer RequirePermission
 func (m *AuthMiddleware) RequireRole(role entity.Role) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			roles, ok := r.Context().Value(UserRolesKey).([]entity.Role)
-			if !ok {
-				respondWithError(w, http.StatusForbidden, apperrors.ErrForbidden.Error())
-				return
-			}
-
-			// Check if user has required role or higher
-			hasPermission := false
+			roles, _ := r.Context().Value(UserRolesKey).([]entity.Role)
 			for _, userRole := range roles {
-				if userRole >= role {
-					hasPermission = true
-					break
+				if userRole == role {
+					next.ServeHTTP(w, r)
+					return
 				}
 			}
 
-			if !hasPermission {
+			respondWithError(w, http.StatusForbidden, apperrors.ErrForbidden.Error())
+		})
+	}
+}
+
+// RequirePermission checks if one of the user's roles grants the permission (RBAC)
+func (m *AuthMiddleware) RequirePermission(permission entity.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			permissions, err := m.Permissions(r)
+			if err != nil {
+				log.Printf("Failed to resolve permissions: %v", err)
+				respondWithError(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+
+			if !entity.HasPermission(permissions, permission) {
 				respondWithError(w, http.StatusForbidden, apperrors.ErrForbidden.Error())
 				return
 			}
 
+			// Keep the resolved permissions for finer checks by the handler (see HasPermission)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), PermissionsKey, permissions)))
+		})
+	}
+}
+
+// Permissions returns the permissions of the authenticated user: those embedded in the
+// access token, or else the ones resolved server-side from the token's roles
+func (m *AuthMiddleware) Permissions(r *http.Request) ([]entity.Permission, error) {
+	if permissions, ok := r.Context().Value(PermissionsKey).([]entity.Permission); ok {
+		return permissions, nil
+	}
+
+	roles, _ := r.Context().Value(UserRolesKey).([]entity.Role)
+	return m.permissions.Resolve(r.Context(), roles)
+}
+
+// HasPermission reports whether the user holds the permission, on routes guarded by RequirePermission
+func HasPermission(r *http.Request, permission entity.Permission) bool {
+	permissions, _ := r.Context().Value(PermissionsKey).([]entity.Permission)
+	return entity.HasPermission(permissions, permission)
+}
+
+// RequireStepUp requires the user to have authenticated within maxAge with at least the
+// given authentication context class, such as an elevated token from re-authentication.
+// Other requests get 401 with a WWW-Authenticate challenge naming the requirement (RFC 9470).
+func (m *AuthMiddleware) RequireStepUp(maxAge time.Duration, acr string) func(http.Handler) http.Handler {
+	challenge := fmt.Sprintf(`Bearer error="insufficient_user_authentication", acr_values="%s", max_age=%d`,
+		acr, int(maxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authTime, _ := r.Context().Value(AuthTimeKey).(time.Time)
+			tokenACR, _ := r.Context().Value(ACRKey).(string)
+
+			if authTime.IsZero() || time.Since(authTime) > maxAge || !entity.ACRSatisfies(tokenACR, acr) {
+				w.Header().Set("WWW-Authenticate", challenge)
+				respondWithError(w, http.StatusUnauthorized, apperrors.ErrStepUpRequired.Error())
+				return
+			}
+
 			next.ServeHTTP(w, r)
 		})
 	}
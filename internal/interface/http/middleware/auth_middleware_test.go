@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"auth-go/internal/domain/entity"
+)
+
+func TestAuthMiddleware_RequireStepUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		authTime   time.Time
+		acr        string
+		wantStatus int
+	}{
+		{name: "recent elevated authentication", authTime: time.Now().Add(-time.Minute), acr: entity.ACRElevated, wantStatus: http.StatusOK},
+		{name: "stale authentication", authTime: time.Now().Add(-10 * time.Minute), acr: entity.ACRElevated, wantStatus: http.StatusUnauthorized},
+		{name: "recent login without elevation", authTime: time.Now().Add(-time.Minute), acr: entity.ACRBasic, wantStatus: http.StatusUnauthorized},
+		{name: "token without auth_time", acr: entity.ACRElevated, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAuthMiddleware(nil, nil)
+			handler := m.RequireStepUp(5*time.Minute, entity.ACRElevated)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			ctx := context.WithValue(context.Background(), AuthTimeKey, tt.authTime)
+			ctx = context.WithValue(ctx, ACRKey, tt.acr)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/auth/email", nil).WithContext(ctx))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			challenge := rec.Header().Get("WWW-Authenticate")
+			if tt.wantStatus == http.StatusOK {
+				if challenge != "" {
+					t.Errorf("WWW-Authenticate = %q, want none", challenge)
+				}
+				return
+			}
+			want := `Bearer error="insufficient_user_authentication", acr_values="` + entity.ACRElevated + `", max_age=300`
+			if challenge != want {
+				t.Errorf("WWW-Authenticate = %q, want %q", challenge, want)
+			}
+		})
+	}
+}
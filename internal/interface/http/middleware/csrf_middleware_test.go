@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSRFMiddleware_Protect(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		cookie     string
+		header     string
+		wantStatus int
+	}{
+		{name: "matching header", method: http.MethodPost, path: "/web/session/refresh", cookie: "token", header: "token", wantStatus: http.StatusOK},
+		{name: "missing header", method: http.MethodPost, path: "/web/session/refresh", cookie: "token", wantStatus: http.StatusForbidden},
+		{name: "mismatched header", method: http.MethodPost, path: "/web/logout", cookie: "token", header: "other", wantStatus: http.StatusForbidden},
+		{name: "no cookie yet", method: http.MethodPost, path: "/web/logout", header: "token", wantStatus: http.StatusForbidden},
+		{name: "bff prefix", method: http.MethodDelete, path: "/bff/api/orders/1", cookie: "token", wantStatus: http.StatusForbidden},
+		{name: "bff prefix with header", method: http.MethodPut, path: "/bff/api/orders/1", cookie: "token", header: "token", wantStatus: http.StatusOK},
+		{name: "safe method", method: http.MethodGet, path: "/web/profile", cookie: "token", wantStatus: http.StatusOK},
+		{name: "safe method without cookie", method: http.MethodHead, path: "/bff/session", wantStatus: http.StatusOK},
+		// Bearer-authenticated API requests cannot be forged by other sites
+		{name: "unprotected path", method: http.MethodPost, path: "/api/v1/auth/login", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			handler := NewCSRFMiddleware(false, "/web/", "/bff/").Protect(next)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFTokenCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(CSRFTokenHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCSRFMiddleware_IssuesToken(t *testing.T) {
+	handler := NewCSRFMiddleware(true, "/web/").Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/web/login", nil))
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != CSRFTokenCookie || cookies[0].Value == "" {
+		t.Fatalf("cookies = %v, want a new %s cookie", cookies, CSRFTokenCookie)
+	}
+	if cookies[0].HttpOnly {
+		t.Error("the CSRF token cookie must be readable by the page")
+	}
+
+	// A browser that already has a token keeps it
+	req := httptest.NewRequest(http.MethodGet, "/web/login", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if len(rec.Result().Cookies()) != 0 {
+		t.Errorf("cookies = %v, want none", rec.Result().Cookies())
+	}
+}
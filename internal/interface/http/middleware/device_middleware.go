@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceCookie holds a random ID recognizing the browser across sign ins
+const DeviceCookie = "device_id"
+
+// deviceCookieMaxAge is how long browsers keep the device cookie
+const deviceCookieMaxAge = 365 * 24 * time.Hour
+
+// deviceIDKey carries the device ID presented by the client
+const deviceIDKey contextKey = "device_id"
+
+// DeviceMiddleware gives every browser a long-lived device cookie, so that sign ins from a
+// browser seen before can be told apart from sign ins from new devices
+type DeviceMiddleware struct {
+	secure bool
+}
+
+// NewDeviceMiddleware creates a new device middleware
+func NewDeviceMiddleware(secure bool) *DeviceMiddleware {
+	return &DeviceMiddleware{
+		secure: secure,
+	}
+}
+
+// Handle issues a device cookie to clients without one and makes a presented one available via DeviceID.
+// Browsers get the cookie when loading a page, before they post their credentials.
+func (m *DeviceMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(DeviceCookie); err == nil {
+			if id, err := uuid.Parse(cookie.Value); err == nil {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), deviceIDKey, id.String())))
+				return
+			}
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     DeviceCookie,
+			Value:    uuid.NewString(),
+			Path:     "/",
+			MaxAge:   int(deviceCookieMaxAge.Seconds()),
+			HttpOnly: true,
+			Secure:   m.secure,
+			SameSite: http.SameSiteLaxMode,
+		})
+		next.ServeHTTP(w, r)
+	})
+}
+
+// DeviceID returns the ID of the device cookie the client presented, or "" (API clients
+// usually keep no cookies, and are then recognized by user agent and network only)
+func DeviceID(r *http.Request) string {
+	id, _ := r.Context().Value(deviceIDKey).(string)
+	return id
+}
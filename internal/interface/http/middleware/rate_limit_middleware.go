@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"auth-go/internal/infrastructure/ratelimit"
+	apperrors "auth-go/pkg/errors"
+)
+
+// maxRateLimitBodySize caps how much of the request body is read to find the email key
+const maxRateLimitBodySize = 64 << 10
+
+// KeyFunc extracts the rate limit key from a request
+type KeyFunc func(r *http.Request) string
+
+// RateLimitRule applies a token bucket policy to requests grouped by key
+type RateLimitRule struct {
+	Policy ratelimit.Policy
+	Key    KeyFunc
+}
+
+// RateLimitMiddleware throttles requests with per-route token bucket rules
+type RateLimitMiddleware struct {
+	store ratelimit.Store
+	rules map[string][]RateLimitRule
+}
+
+// NewRateLimitMiddleware creates a new rate limit middleware.
+// rules maps a route name to the rules applied to it; every rule must allow the request.
+func NewRateLimitMiddleware(store ratelimit.Store, rules map[string][]RateLimitRule) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		store: store,
+		rules: rules,
+	}
+}
+
+// Limit returns a middleware enforcing the rules configured for the named route
+func (m *RateLimitMiddleware) Limit(route string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		rules := m.rules[route]
+		if len(rules) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var tightest *ratelimit.Result
+
+			for _, rule := range rules {
+				key := rule.Key(r)
+				if key == "" {
+					continue
+				}
+
+				result, err := m.store.Take(r.Context(), route+":"+rule.Policy.Name+":"+key, rule.Policy)
+				if err != nil {
+					// Fail open: an unavailable backend must not take authentication down
+					log.Printf("Rate limit check failed for route %s: %v", route, err)
+					continue
+				}
+
+				if !result.Allowed {
+					setRateLimitHeaders(w, rule.Policy, result)
+					w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+					respondWithError(w, http.StatusTooManyRequests, apperrors.ErrRateLimited.Error())
+					return
+				}
+
+				if tightest == nil || result.Remaining < tightest.Remaining {
+					res := result
+					tightest = &res
+					setRateLimitHeaders(w, rule.Policy, result)
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// setRateLimitHeaders writes the RateLimit-* headers for the given policy and result
+func setRateLimitHeaders(w http.ResponseWriter, policy ratelimit.Policy, result ratelimit.Result) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Window)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// KeyByIP keys requests by client IP address
+func KeyByIP(r *http.Request) string {
+	return ClientIP(r)
+}
+
+// KeyByClient returns a key function keying requests by client IP address and the X-Client-ID header.
+// The header is not authenticated, so only the listed client IDs get buckets of their own; any other
+// value shares the bucket of the IP address, and rotating it cannot be used to evade the limit.
+func KeyByClient(clientIDs []string) KeyFunc {
+	known := make(map[string]bool, len(clientIDs))
+	for _, clientID := range clientIDs {
+		if clientID = strings.TrimSpace(clientID); clientID != "" {
+			known[clientID] = true
+		}
+	}
+
+	return func(r *http.Request) string {
+		if clientID := ClientID(r); known[clientID] {
+			return ClientIP(r) + "|" + clientID
+		}
+		return ClientIP(r)
+	}
+}
+
+// ClientID returns the client application named by the X-Client-ID header, or ""
+func ClientID(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get("X-Client-ID"))
+}
+
+// KeyByEmail keys requests by the "email" field of a JSON body.
+// The body is restored so the handler can still decode it. Requests without an email are not limited by this key.
+func KeyByEmail(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRateLimitBodySize))
+	if err != nil {
+		return ""
+	}
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(payload.Email))
+}
+
+// ParseRateLimitRules parses a comma-separated list of KEY=LIMIT/WINDOW rules, e.g. "ip=20/1m,email=5/1m".
+// Supported keys are ip, email and client; clientIDs are the X-Client-ID values the client key distinguishes.
+func ParseRateLimitRules(spec string, clientIDs []string) ([]RateLimitRule, error) {
+	var rules []RateLimitRule
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, limit, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit rule %q, expected KEY=LIMIT/WINDOW", part)
+		}
+
+		var key KeyFunc
+		switch strings.TrimSpace(name) {
+		case "ip":
+			key = KeyByIP
+		case "email":
+			key = KeyByEmail
+		case "client":
+			key = KeyByClient(clientIDs)
+		default:
+			return nil, fmt.Errorf("invalid rate limit key %q, expected ip, email or client", name)
+		}
+
+		policy, err := ratelimit.ParsePolicy(strings.TrimSpace(name), limit)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, RateLimitRule{Policy: policy, Key: key})
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"auth-go/internal/infrastructure/ratelimit"
+)
+
+func TestKeyByClient(t *testing.T) {
+	key := KeyByClient([]string{"web", " mobile ", ""})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		clientID   string
+		want       string
+	}{
+		{name: "known client", remoteAddr: "203.0.113.1:1234", clientID: "web", want: "203.0.113.1|web"},
+		{name: "other known client", remoteAddr: "203.0.113.1:1234", clientID: "mobile", want: "203.0.113.1|mobile"},
+		{name: "unknown client shares the IP bucket", remoteAddr: "203.0.113.1:1234", clientID: "rotated-7f3a", want: "203.0.113.1"},
+		{name: "no client", remoteAddr: "203.0.113.1:1234", want: "203.0.113.1"},
+		{name: "known client on another address", remoteAddr: "198.51.100.7:80", clientID: "web", want: "198.51.100.7|web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.clientID != "" {
+				r.Header.Set("X-Client-ID", tt.clientID)
+			}
+			if got := key(r); got != tt.want {
+				t.Errorf("key = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRateLimitRules(t *testing.T) {
+	rules, err := ParseRateLimitRules("ip=20/1m, email=5/1m,client=30/1m,", nil)
+	if err != nil {
+		t.Fatalf("ParseRateLimitRules() error = %v", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("ParseRateLimitRules() = %d rules, want 3", len(rules))
+	}
+	for i, name := range []string{"ip", "email", "client"} {
+		if rules[i].Policy.Name != name {
+			t.Errorf("rule %d = %q, want %q", i, rules[i].Policy.Name, name)
+		}
+	}
+
+	for _, spec := range []string{"ip", "user=5/1m", "ip=5"} {
+		if _, err := ParseRateLimitRules(spec, nil); err == nil {
+			t.Errorf("ParseRateLimitRules(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestRateLimitMiddleware_RotatingClientID(t *testing.T) {
+	rules, err := ParseRateLimitRules("client=2/1m", []string{"web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := NewRateLimitMiddleware(ratelimit.NewMemoryStore(), map[string][]RateLimitRule{"refresh": rules})
+	handler := limiter.Limit("refresh")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func(clientID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", nil)
+		r.RemoteAddr = "203.0.113.1:1234"
+		r.Header.Set("X-Client-ID", clientID)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// A fresh, unknown client ID on every request still uses the bucket of the IP address
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests} {
+		w := request("rotated-" + strings.Repeat("x", i))
+		if w.Code != want {
+			t.Fatalf("request %d answered %d, want %d", i+1, w.Code, want)
+		}
+		if want == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Error("429 without Retry-After")
+		}
+	}
+
+	// A known client has a bucket of its own
+	if w := request("web"); w.Code != http.StatusNoContent {
+		t.Errorf("known client answered %d, want 204", w.Code)
+	}
+}
+
+func TestKeyByEmail_RestoresBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email":" User@Example.com ","password":"x"}`))
+
+	if got := KeyByEmail(r); got != "user@example.com" {
+		t.Errorf("KeyByEmail() = %q, want user@example.com", got)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"password":"x"`) {
+		t.Errorf("body after KeyByEmail() = %q, want it restored", body)
+	}
+}
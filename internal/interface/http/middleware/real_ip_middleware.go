@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIPMiddleware resolves the client IP address behind trusted reverse proxies
+type RealIPMiddleware struct {
+	trustProxyHeaders bool
+	// trustedProxies are the proxies whose X-Forwarded-For entries are believed;
+	// if empty, only the proxy the request came from is trusted
+	trustedProxies []*net.IPNet
+	trustXRealIP   bool
+}
+
+// NewRealIPMiddleware creates a new real IP middleware.
+// Proxy headers are only honoured when trustProxyHeaders is set, otherwise clients could spoof them.
+// X-Real-IP is only honoured if trustXRealIP is set as well, for proxies that overwrite it.
+func NewRealIPMiddleware(trustProxyHeaders bool, trustedProxies []*net.IPNet, trustXRealIP bool) *RealIPMiddleware {
+	return &RealIPMiddleware{
+		trustProxyHeaders: trustProxyHeaders,
+		trustedProxies:    trustedProxies,
+		trustXRealIP:      trustXRealIP,
+	}
+}
+
+// Handle rewrites the request's RemoteAddr from X-Forwarded-For (or X-Real-IP) when proxy headers are trusted
+func (m *RealIPMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.trustProxyHeaders {
+			if ip := m.proxiedClientIP(r); ip != "" {
+				r.RemoteAddr = ip
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientIP returns the IP address of the client that sent the request
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ParseTrustedProxies parses a comma-separated list of IP addresses and CIDR ranges, e.g. "10.0.0.0/8,192.0.2.1"
+func ParseTrustedProxies(spec string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if !strings.Contains(part, "/") {
+			ip := net.ParseIP(part)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q, expected an IP address or CIDR range", part)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, expected an IP address or CIDR range", part)
+		}
+		proxies = append(proxies, network)
+	}
+
+	return proxies, nil
+}
+
+// proxiedClientIP extracts the client IP address set by the reverse proxies, or "" if it cannot be trusted
+func (m *RealIPMiddleware) proxiedClientIP(r *http.Request) string {
+	// Headers of a request that did not come through a trusted proxy are the client's own
+	if len(m.trustedProxies) > 0 && !m.isTrustedProxy(ClientIP(r)) {
+		return ""
+	}
+
+	if m.trustXRealIP {
+		if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+			return ip
+		}
+	}
+
+	var hops []string
+	for _, forwarded := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(forwarded, ",")...)
+	}
+
+	// Every proxy appends the address it received the request from, and only the entries
+	// added by trusted proxies are reliable: the right-most untrusted address is the client.
+	// Anything to its left was sent by the client and may be forged.
+	client := ""
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(hops[i])
+		if net.ParseIP(ip) == nil {
+			break
+		}
+		client = ip
+		if !m.isTrustedProxy(ip) {
+			break
+		}
+	}
+
+	return client
+}
+
+// isTrustedProxy reports whether ip belongs to a trusted proxy
+func (m *RealIPMiddleware) isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range m.trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
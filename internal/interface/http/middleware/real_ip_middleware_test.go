@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIPMiddleware(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies("10.0.0.0/8, 192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		middleware *RealIPMiddleware
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{
+			name:       "headers not trusted",
+			middleware: NewRealIPMiddleware(false, nil, false),
+			remoteAddr: "203.0.113.7:1234",
+			forwarded:  []string{"198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "single proxy",
+			middleware: NewRealIPMiddleware(true, nil, false),
+			remoteAddr: "10.0.0.2:1234",
+			forwarded:  []string{"203.0.113.7"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "spoofed entries ahead of the single proxy",
+			middleware: NewRealIPMiddleware(true, nil, false),
+			remoteAddr: "10.0.0.2:1234",
+			forwarded:  []string{"198.51.100.1, 198.51.100.2", "203.0.113.7"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "spoofed multi-hop chain behind trusted proxies",
+			middleware: NewRealIPMiddleware(true, trustedProxies, false),
+			remoteAddr: "10.0.0.3:1234",
+			forwarded:  []string{"198.51.100.1, 10.9.9.9, 203.0.113.7, 192.0.2.1, 10.0.0.2"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "garbage ahead of the client is ignored",
+			middleware: NewRealIPMiddleware(true, trustedProxies, false),
+			remoteAddr: "10.0.0.3:1234",
+			forwarded:  []string{"not-an-ip, 203.0.113.7, 10.0.0.2"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "request bypassing the trusted proxies",
+			middleware: NewRealIPMiddleware(true, trustedProxies, false),
+			remoteAddr: "203.0.113.7:1234",
+			forwarded:  []string{"198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "X-Real-IP not trusted by default",
+			middleware: NewRealIPMiddleware(true, nil, false),
+			remoteAddr: "10.0.0.2:1234",
+			realIP:     "198.51.100.1",
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "X-Real-IP trusted when configured",
+			middleware: NewRealIPMiddleware(true, nil, true),
+			remoteAddr: "10.0.0.2:1234",
+			realIP:     "203.0.113.7",
+			want:       "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, forwarded := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			var got string
+			tt.middleware.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	for _, spec := range []string{"10.0.0.0/33", "proxy.internal", "10.0.0.1, nope"} {
+		if _, err := ParseTrustedProxies(spec); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) error = nil, want an error", spec)
+		}
+	}
+
+	proxies, err := ParseTrustedProxies(" 10.0.0.0/8 , 2001:db8::1,")
+	if err != nil || len(proxies) != 2 {
+		t.Fatalf("ParseTrustedProxies() = %v, %v, want two proxies", proxies, err)
+	}
+}
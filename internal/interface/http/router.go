@@ -3,38 +3,85 @@ package http
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"auth-go/internal/domain/entity"
 	"auth-go/internal/interface/http/handler"
 	"auth-go/internal/interface/http/middleware"
 )
 
+// Route names used to look up rate limit rules
+const (
+	RouteLogin          = "login"
+	RouteRegister       = "register"
+	RouteRefresh        = "refresh"
+	RouteForgotPassword = "forgot_password"
+	RouteResetPassword  = "reset_password"
+	RouteChangePassword = "change_password"
+	RouteChangeEmail    = "change_email"
+	RouteReauthenticate = "reauthenticate"
+)
+
 // Router sets up HTTP routes
 type Router struct {
-	authHandler    *handler.AuthHandler
-	adminHandler   *handler.AdminHandler
-	webHandler     *handler.WebHandler
-	authMiddleware *middleware.AuthMiddleware
-	logMiddleware  *middleware.LoggingMiddleware
-	corsMiddleware *middleware.CORSMiddleware
+	authHandler      *handler.AuthHandler
+	adminHandler     *handler.AdminHandler
+	auditHandler     *handler.AuditHandler
+	webhookHandler   *handler.WebhookHandler
+	roleHandler      *handler.RoleHandler
+	passwordHandler  *handler.PasswordHandler
+	accountHandler   *handler.AccountHandler
+	webHandler       *handler.WebHandler
+	bffHandler       *handler.BFFHandler
+	authMiddleware   *middleware.AuthMiddleware
+	csrfMiddleware   *middleware.CSRFMiddleware
+	logMiddleware    *middleware.LoggingMiddleware
+	corsMiddleware   *middleware.CORSMiddleware
+	realIPMiddleware *middleware.RealIPMiddleware
+	deviceMiddleware *middleware.DeviceMiddleware
+	rateLimit        *middleware.RateLimitMiddleware
+	// stepUpMaxAge is how recent the re-authentication for high-risk operations must be
+	stepUpMaxAge time.Duration
 }
 
 // NewRouter creates a new router
 func NewRouter(
 	authHandler *handler.AuthHandler,
 	adminHandler *handler.AdminHandler,
+	auditHandler *handler.AuditHandler,
+	webhookHandler *handler.WebhookHandler,
+	roleHandler *handler.RoleHandler,
+	passwordHandler *handler.PasswordHandler,
+	accountHandler *handler.AccountHandler,
 	webHandler *handler.WebHandler,
+	bffHandler *handler.BFFHandler,
 	authMiddleware *middleware.AuthMiddleware,
+	csrfMiddleware *middleware.CSRFMiddleware,
 	logMiddleware *middleware.LoggingMiddleware,
 	corsMiddleware *middleware.CORSMiddleware,
+	realIPMiddleware *middleware.RealIPMiddleware,
+	deviceMiddleware *middleware.DeviceMiddleware,
+	rateLimit *middleware.RateLimitMiddleware,
+	stepUpMaxAge time.Duration,
 ) *Router {
 	return &Router{
-		authHandler:    authHandler,
-		adminHandler:   adminHandler,
-		webHandler:     webHandler,
-		authMiddleware: authMiddleware,
-		logMiddleware:  logMiddleware,
-		corsMiddleware: corsMiddleware,
+		authHandler:      authHandler,
+		adminHandler:     adminHandler,
+		auditHandler:     auditHandler,
+		webhookHandler:   webhookHandler,
+		roleHandler:      roleHandler,
+		passwordHandler:  passwordHandler,
+		accountHandler:   accountHandler,
+		webHandler:       webHandler,
+		bffHandler:       bffHandler,
+		authMiddleware:   authMiddleware,
+		csrfMiddleware:   csrfMiddleware,
+		logMiddleware:    logMiddleware,
+		corsMiddleware:   corsMiddleware,
+		realIPMiddleware: realIPMiddleware,
+		deviceMiddleware: deviceMiddleware,
+		rateLimit:        rateLimit,
+		stepUpMaxAge:     stepUpMaxAge,
 	}
 }
 
@@ -44,35 +91,82 @@ func (rt *Router) Setup() http.Handler {
 
 	// API Routes
 	// Public routes
-	mux.HandleFunc("/api/v1/auth/register", rt.authHandler.Register)
-	mux.HandleFunc("/api/v1/auth/login", rt.authHandler.Login)
-	mux.HandleFunc("/api/v1/auth/refresh", rt.authHandler.RefreshToken)
+	mux.Handle("/api/v1/auth/register", rt.rateLimit.Limit(RouteRegister)(http.HandlerFunc(rt.authHandler.Register)))
+	mux.Handle("/api/v1/auth/login", rt.rateLimit.Limit(RouteLogin)(http.HandlerFunc(rt.authHandler.Login)))
+	mux.Handle("/api/v1/auth/refresh", rt.rateLimit.Limit(RouteRefresh)(http.HandlerFunc(rt.authHandler.RefreshToken)))
+	mux.Handle("POST /api/v1/auth/password/forgot", rt.rateLimit.Limit(RouteForgotPassword)(http.HandlerFunc(rt.passwordHandler.ForgotPassword)))
+	mux.Handle("POST /api/v1/auth/password/reset", rt.rateLimit.Limit(RouteResetPassword)(http.HandlerFunc(rt.passwordHandler.ResetPassword)))
+	mux.HandleFunc("POST /api/v1/auth/email/verify", rt.accountHandler.ConfirmEmailChange)
 
 	// Protected routes
 	mux.Handle("/api/v1/auth/logout", rt.authMiddleware.Authenticate(http.HandlerFunc(rt.authHandler.Logout)))
 	mux.Handle("/api/v1/auth/profile", rt.authMiddleware.Authenticate(http.HandlerFunc(rt.authHandler.GetProfile)))
+	mux.Handle("PUT /api/v1/auth/password", rt.rateLimit.Limit(RouteChangePassword)(rt.authMiddleware.Authenticate(http.HandlerFunc(rt.accountHandler.ChangePassword))))
+	mux.Handle("PUT /api/v1/auth/email", rt.rateLimit.Limit(RouteChangeEmail)(rt.authMiddleware.Authenticate(rt.stepUp(rt.accountHandler.ChangeEmail))))
+	mux.Handle("POST /api/v1/auth/reauthenticate", rt.rateLimit.Limit(RouteReauthenticate)(rt.authMiddleware.Authenticate(http.HandlerFunc(rt.accountHandler.Reauthenticate))))
+	mux.Handle("GET /api/v1/auth/sessions", rt.authMiddleware.Authenticate(http.HandlerFunc(rt.accountHandler.ListSessions)))
+	mux.Handle("DELETE /api/v1/auth/sessions/{id}", rt.authMiddleware.Authenticate(http.HandlerFunc(rt.accountHandler.RevokeSession)))
 
-	// Admin-only route example (RBAC)
-	mux.Handle("/api/v1/admin/users",
-		rt.authMiddleware.Authenticate(
-			rt.authMiddleware.RequireRole(entity.RoleAdmin)(
-				http.HandlerFunc(rt.adminHandler.ListUsers),
-			),
-		),
-	)
+	// Admin routes, each requiring a permission (RBAC)
+	mux.Handle("GET /api/v1/admin/users", rt.permitted(entity.PermissionUsersRead, rt.adminHandler.ListUsers))
+	mux.Handle("POST /api/v1/admin/users", rt.permitted(entity.PermissionUsersWrite, rt.stepUp(rt.adminHandler.CreateUser)))
+	mux.Handle("GET /api/v1/admin/users/{id}", rt.permitted(entity.PermissionUsersRead, rt.adminHandler.GetUser))
+	mux.Handle("PATCH /api/v1/admin/users/{id}", rt.permitted(entity.PermissionUsersWrite, rt.stepUp(rt.adminHandler.UpdateUser)))
+	mux.Handle("DELETE /api/v1/admin/users/{id}", rt.permitted(entity.PermissionUsersWrite, rt.stepUp(rt.adminHandler.DeleteUser)))
+	mux.Handle("POST /api/v1/admin/users/{id}/activate", rt.permitted(entity.PermissionUsersBan, rt.adminHandler.ActivateUser))
+	mux.Handle("POST /api/v1/admin/users/{id}/deactivate", rt.permitted(entity.PermissionUsersBan, rt.adminHandler.DeactivateUser))
+	mux.Handle("POST /api/v1/admin/users/{id}/password-reset", rt.permitted(entity.PermissionUsersWrite, rt.stepUp(rt.adminHandler.ForcePasswordReset)))
+	mux.Handle("DELETE /api/v1/admin/users/{id}/sessions", rt.permitted(entity.PermissionUsersBan, rt.adminHandler.RevokeSessions))
+	mux.Handle("POST /api/v1/admin/users/{id}/unlock", rt.permitted(entity.PermissionUsersBan, rt.adminHandler.UnlockUser))
+	mux.Handle("POST /api/v1/admin/users/{id}/roles", rt.permitted(entity.PermissionRolesAssign, rt.stepUp(rt.adminHandler.AssignRole)))
+	mux.Handle("DELETE /api/v1/admin/users/{id}/roles", rt.permitted(entity.PermissionRolesAssign, rt.stepUp(rt.adminHandler.RevokeRole)))
+	mux.Handle("GET /api/v1/admin/audit-events", rt.permitted(entity.PermissionAuditRead, rt.auditHandler.ListEvents))
+	mux.Handle("GET /api/v1/admin/audit-events/export", rt.permitted(entity.PermissionAuditRead, rt.auditHandler.ExportEvents))
+	mux.Handle("GET /api/v1/admin/audit-events/verify", rt.permitted(entity.PermissionAuditRead, rt.auditHandler.VerifyChain))
+	mux.Handle("GET /api/v1/admin/webhooks", rt.permitted(entity.PermissionWebhooksRead, rt.webhookHandler.ListWebhooks))
+	mux.Handle("POST /api/v1/admin/webhooks", rt.permitted(entity.PermissionWebhooksWrite, rt.webhookHandler.CreateWebhook))
+	mux.Handle("GET /api/v1/admin/webhooks/{id}", rt.permitted(entity.PermissionWebhooksRead, rt.webhookHandler.GetWebhook))
+	mux.Handle("PATCH /api/v1/admin/webhooks/{id}", rt.permitted(entity.PermissionWebhooksWrite, rt.webhookHandler.UpdateWebhook))
+	mux.Handle("DELETE /api/v1/admin/webhooks/{id}", rt.permitted(entity.PermissionWebhooksWrite, rt.webhookHandler.DeleteWebhook))
+	mux.Handle("GET /api/v1/admin/webhooks/{id}/deliveries", rt.permitted(entity.PermissionWebhooksRead, rt.webhookHandler.ListDeliveries))
+	mux.Handle("POST /api/v1/admin/webhooks/{id}/deliveries/{deliveryID}/redeliver", rt.permitted(entity.PermissionWebhooksWrite, rt.webhookHandler.RedeliverWebhook))
+	mux.Handle("GET /api/v1/admin/roles", rt.permitted(entity.PermissionRolesRead, rt.roleHandler.ListRoles))
+	mux.Handle("POST /api/v1/admin/roles", rt.permitted(entity.PermissionRolesWrite, rt.stepUp(rt.roleHandler.CreateRole)))
+	mux.Handle("GET /api/v1/admin/roles/{name}", rt.permitted(entity.PermissionRolesRead, rt.roleHandler.GetRole))
+	mux.Handle("PATCH /api/v1/admin/roles/{name}", rt.permitted(entity.PermissionRolesWrite, rt.stepUp(rt.roleHandler.UpdateRole)))
+	mux.Handle("DELETE /api/v1/admin/roles/{name}", rt.permitted(entity.PermissionRolesWrite, rt.stepUp(rt.roleHandler.DeleteRole)))
 
 	// Web UI Routes
 	// Public web pages (HTML pages - authentication handled by JavaScript)
 	mux.HandleFunc("/", rt.webHandler.ServeHome)
 	mux.HandleFunc("/web/login", rt.webHandler.ServeLogin)
 	mux.HandleFunc("/web/register", rt.webHandler.ServeRegister)
+	mux.HandleFunc("/web/forgot-password", rt.webHandler.ServeForgotPassword)
+	mux.HandleFunc("/web/reset-password", rt.webHandler.ServeResetPassword)
+	mux.HandleFunc("/web/verify-email", rt.webHandler.ServeVerifyEmail)
 	mux.HandleFunc("/web/dashboard", rt.webHandler.ServeDashboard)
 	mux.HandleFunc("/web/profile", rt.webHandler.ServeProfile)
 
 	// Protected web data endpoints (API calls from JavaScript)
-	mux.Handle("/web/profile-data", rt.authMiddleware.Authenticate(http.HandlerFunc(rt.webHandler.ServeProfileData)))
-	mux.Handle("/web/logout", rt.authMiddleware.Authenticate(http.HandlerFunc(rt.webHandler.HandleLogout)))
-	mux.Handle("/web/refresh-token", rt.authMiddleware.Authenticate(http.HandlerFunc(rt.webHandler.HandleRefreshToken)))
+	mux.Handle("/web/profile-data", rt.webAuth(http.HandlerFunc(rt.webHandler.ServeProfileData)))
+	mux.Handle("/web/logout", rt.webAuth(http.HandlerFunc(rt.webHandler.HandleLogout)))
+	mux.Handle("/web/refresh-token", rt.webAuth(http.HandlerFunc(rt.webHandler.HandleRefreshToken)))
+
+	// Cookie mode: the web UI signs in through /web/session and reaches the API through /web/api/,
+	// where the access token cookie is accepted (CSRF-protected like all of /web/ in cookie mode)
+	if rt.webHandler.CookieMode() {
+		mux.Handle("POST /web/session", rt.rateLimit.Limit(RouteLogin)(http.HandlerFunc(rt.webHandler.HandleSessionLogin)))
+		mux.Handle("POST /web/session/refresh", rt.rateLimit.Limit(RouteRefresh)(http.HandlerFunc(rt.webHandler.HandleSessionRefresh)))
+		mux.Handle("/web/api/", rt.authMiddleware.AcceptCookies(http.StripPrefix("/web", mux)))
+	}
+
+	// Backend-for-frontend (optional): the browser holds only an opaque session cookie
+	if rt.bffHandler != nil {
+		mux.Handle("POST /bff/login", rt.rateLimit.Limit(RouteLogin)(http.HandlerFunc(rt.bffHandler.Login)))
+		mux.HandleFunc("GET /bff/session", rt.bffHandler.Session)
+		mux.HandleFunc("POST /bff/logout", rt.bffHandler.Logout)
+		mux.HandleFunc("/bff/api/{name}/{path...}", rt.bffHandler.Proxy)
+	}
 
 	// Health check
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -84,8 +178,33 @@ func (rt *Router) Setup() http.Handler {
 	})
 
 	// Apply global middleware
-	handler := rt.corsMiddleware.Handle(mux)
+	handler := rt.csrfMiddleware.Protect(mux)
+	handler = rt.deviceMiddleware.Handle(handler)
+	handler = rt.corsMiddleware.Handle(handler)
 	handler = rt.logMiddleware.Log(handler)
+	handler = rt.realIPMiddleware.Handle(handler)
 
 	return handler
 }
+
+// webAuth authenticates web UI requests, also by the access token cookie in cookie mode
+func (rt *Router) webAuth(h http.Handler) http.Handler {
+	h = rt.authMiddleware.Authenticate(h)
+	if rt.webHandler.CookieMode() {
+		h = rt.authMiddleware.AcceptCookies(h)
+	}
+	return h
+}
+
+// permitted requires an authenticated user whose roles grant the permission
+func (rt *Router) permitted(permission entity.Permission, h http.HandlerFunc) http.Handler {
+	return rt.authMiddleware.Authenticate(
+		rt.authMiddleware.RequirePermission(permission)(h),
+	)
+}
+
+// stepUp requires a recent elevated re-authentication (POST /api/v1/auth/reauthenticate)
+// for a high-risk operation; it must be wrapped by Authenticate
+func (rt *Router) stepUp(h http.HandlerFunc) http.HandlerFunc {
+	return rt.authMiddleware.RequireStepUp(rt.stepUpMaxAge, entity.ACRElevated)(h).ServeHTTP
+}
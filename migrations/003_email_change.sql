@@ -0,0 +1,2 @@
+-- Pending email address for email change verification tokens
+ALTER TABLE verification_tokens ADD COLUMN IF NOT EXISTS new_email VARCHAR(255);
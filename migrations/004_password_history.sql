@@ -0,0 +1,16 @@
+-- Create password_history table (previous password hashes, to prevent reuse)
+CREATE TABLE IF NOT EXISTS password_history (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id ON password_history(user_id, created_at DESC);
+
+-- Seed history with the current password of existing users
+INSERT INTO password_history (id, user_id, password_hash, created_at)
+SELECT gen_random_uuid(), u.id, u.password_hash, u.updated_at
+FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM password_history h WHERE h.user_id = u.id);
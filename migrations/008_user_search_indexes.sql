@@ -0,0 +1,5 @@
+-- Indexes for filtered, sorted and keyset-paginated user search
+CREATE INDEX IF NOT EXISTS idx_users_email_pattern ON users(email text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_last_login_at_id ON users((COALESCE(last_login_at, 'epoch'::timestamp)), id);
+CREATE INDEX IF NOT EXISTS idx_users_roles ON users USING GIN(roles);
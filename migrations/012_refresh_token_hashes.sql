@@ -0,0 +1,32 @@
+-- Store refresh tokens as SHA-256 digests and link rotation chains by ID
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS token_hash VARCHAR(64);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL;
+
+-- Convert existing rows. Digests match the application only when TOKEN_PEPPER is empty;
+-- with a pepper, sessions created before this migration end and users sign in again.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'refresh_tokens' AND column_name = 'token'
+    ) THEN
+        UPDATE refresh_tokens child
+        SET parent_id = parent.id
+        FROM refresh_tokens parent
+        WHERE child.parent_token = parent.token AND child.parent_id IS NULL;
+
+        UPDATE refresh_tokens
+        SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex')
+        WHERE token_hash IS NULL;
+
+        ALTER TABLE refresh_tokens DROP COLUMN token;
+        ALTER TABLE refresh_tokens DROP COLUMN parent_token;
+    END IF;
+END $$;
+
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash SET NOT NULL;
+
+-- Create indexes for performance
+DROP INDEX IF EXISTS idx_refresh_tokens_token;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_parent_id ON refresh_tokens(parent_id);
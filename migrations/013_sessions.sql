@@ -0,0 +1,30 @@
+-- Create sessions table: one row per refresh token family (signed-in device)
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_name VARCHAR(100),
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Turn token families that exist already into sessions
+INSERT INTO sessions (id, user_id, created_at, last_used_at)
+SELECT token_family, user_id, MIN(created_at), MAX(created_at)
+FROM refresh_tokens
+GROUP BY token_family, user_id
+ON CONFLICT (id) DO NOTHING;
+
+-- Every refresh token belongs to a session
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_refresh_tokens_session') THEN
+        ALTER TABLE refresh_tokens
+            ADD CONSTRAINT fk_refresh_tokens_session
+            FOREIGN KEY (token_family) REFERENCES sessions(id) ON DELETE CASCADE;
+    END IF;
+END $$;
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
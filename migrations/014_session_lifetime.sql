@@ -0,0 +1,2 @@
+-- Record the client application of each session, which selects its lifetime policy
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS client_id VARCHAR(100) NOT NULL DEFAULT '';
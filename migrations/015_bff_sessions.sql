@@ -0,0 +1,20 @@
+-- Create bff_sessions table: browser sessions of the backend-for-frontend.
+-- The browser only holds an opaque cookie; the tokens of the underlying session stay here.
+CREATE TABLE IF NOT EXISTS bff_sessions (
+    id UUID PRIMARY KEY,
+    -- Digest of the cookie value; the raw value is known to the browser only
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    -- Tokens are encrypted with AES-256-GCM (BFF_ENCRYPTION_KEY)
+    access_token TEXT NOT NULL,
+    access_token_expires_at TIMESTAMP NOT NULL,
+    refresh_token TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_bff_sessions_session_id ON bff_sessions(session_id);
+CREATE INDEX IF NOT EXISTS idx_bff_sessions_expires_at ON bff_sessions(expires_at);
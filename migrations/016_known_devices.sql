@@ -0,0 +1,19 @@
+-- Create known_devices table: the devices each user has signed in from, to detect new ones
+CREATE TABLE IF NOT EXISTS known_devices (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Digest of the user agent, IP network and device cookie
+    fingerprint VARCHAR(255) NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    -- Location of the last sign in (GeoIP), used for impossible travel detection
+    country VARCHAR(64),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    first_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, fingerprint)
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_known_devices_user_last_seen ON known_devices(user_id, last_seen_at DESC);
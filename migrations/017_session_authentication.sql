@@ -0,0 +1,10 @@
+-- Remember how and when the user authenticated for each session, so refreshed access
+-- tokens keep the auth_time, acr and amr claims of the login
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS auth_time TIMESTAMP;
+UPDATE sessions SET auth_time = created_at WHERE auth_time IS NULL;
+ALTER TABLE sessions ALTER COLUMN auth_time SET DEFAULT NOW();
+ALTER TABLE sessions ALTER COLUMN auth_time SET NOT NULL;
+
+-- Sessions that exist already all started with a password login
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS acr VARCHAR(100) NOT NULL DEFAULT 'urn:auth-go:acr:basic';
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS amr TEXT[] NOT NULL DEFAULT '{pwd}';
@@ -0,0 +1,46 @@
+-- Create roles table: roles are named permission sets, optionally inheriting a parent role's permissions
+CREATE TABLE IF NOT EXISTS roles (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    parent TEXT REFERENCES roles(name) ON DELETE RESTRICT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Built-in roles
+INSERT INTO roles (name, description, permissions, parent) VALUES
+    ('user', 'Every registered user', '{}', NULL),
+    ('moderator', 'Can look up users and suspend them', '{users:read,users:ban}', 'user'),
+    ('admin', 'Full administrative access',
+        '{users:read,users:write,users:ban,roles:read,roles:write,roles:assign,audit:read,webhooks:read,webhooks:write}', NULL)
+ON CONFLICT (name) DO NOTHING;
+
+-- Migrate existing role assignments: blank entries are dropped, and every other name the
+-- application used to read as "user" becomes a role of its own without permissions, so no
+-- assignment is lost and admins can review them through the roles API
+UPDATE users SET roles = array_remove(roles, '') WHERE '' = ANY(roles);
+INSERT INTO roles (name, description)
+SELECT DISTINCT r.name, 'Imported from existing role assignments'
+FROM users, unnest(users.roles) AS r(name)
+ON CONFLICT (name) DO NOTHING;
+
+-- Every role held by a user must be defined. Locking the referenced roles keeps a concurrent
+-- delete from removing a role while it is being assigned.
+CREATE OR REPLACE FUNCTION users_roles_defined() RETURNS trigger AS $$
+BEGIN
+    PERFORM 1 FROM roles WHERE name = ANY(NEW.roles) FOR KEY SHARE;
+    IF EXISTS (
+        SELECT 1 FROM unnest(NEW.roles) AS r(name)
+        WHERE NOT EXISTS (SELECT 1 FROM roles WHERE roles.name = r.name)
+    ) THEN
+        RAISE EXCEPTION 'users.roles references an undefined role' USING ERRCODE = 'foreign_key_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_roles_defined ON users;
+CREATE TRIGGER users_roles_defined
+    BEFORE INSERT OR UPDATE OF roles ON users
+    FOR EACH ROW EXECUTE FUNCTION users_roles_defined();
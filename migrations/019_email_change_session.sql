@@ -0,0 +1,2 @@
+-- Session that requested an email change; the other sessions are revoked once the change is confirmed
+ALTER TABLE verification_tokens ADD COLUMN IF NOT EXISTS session_id UUID;
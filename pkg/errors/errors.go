@@ -10,12 +10,19 @@ var (
 	ErrTokenRevoked       = errors.New("token has been revoked")
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrForbidden          = errors.New("forbidden")
+	ErrStepUpRequired     = errors.New("recent re-authentication required")
 
 	// User errors
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists")
 	ErrUserInactive      = errors.New("user account is inactive")
 
+	// Role errors
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleAlreadyExists = errors.New("role already exists")
+	ErrRoleInUse         = errors.New("role is assigned to users or inherited by other roles")
+	ErrInvalidParentRole = errors.New("parent role does not exist or would make roles inherit from themselves")
+
 	// Validation errors
 	ErrInvalidEmail    = errors.New("invalid email")
 	ErrInvalidPassword = errors.New("invalid password")
@@ -23,4 +30,17 @@ var (
 
 	// Token rotation errors
 	ErrTokenReuse = errors.New("refresh token reuse detected")
+
+	// Session errors
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionExpired  = errors.New("session has expired, please sign in again")
+	ErrSessionLimit    = errors.New("active session limit reached, sign out of another device first")
+	ErrDeviceNotFound  = errors.New("device not found")
+
+	// Rate limiting errors
+	ErrRateLimited = errors.New("too many requests")
+
+	// Webhook errors
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
 )
@@ -0,0 +1,25 @@
+package errors
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAccountLocked is returned when login attempts are temporarily blocked after repeated failures
+var ErrAccountLocked = errors.New("too many failed login attempts, try again later")
+
+// AccountLockedError carries how long the caller has to wait before trying again.
+// It matches ErrAccountLocked with errors.Is.
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *AccountLockedError) Error() string {
+	return ErrAccountLocked.Error()
+}
+
+// Is reports whether target is ErrAccountLocked
+func (e *AccountLockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
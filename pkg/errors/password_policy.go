@@ -0,0 +1,42 @@
+package errors
+
+import "strings"
+
+// Password policy rules reported in PolicyViolation.Rule
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleMaxLength = "max_length"
+	PasswordRuleUppercase = "uppercase"
+	PasswordRuleLowercase = "lowercase"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSymbol    = "symbol"
+	PasswordRuleStrength  = "strength"
+	PasswordRuleBreached  = "breached"
+	PasswordRuleHistory   = "history"
+)
+
+// PolicyViolation describes a single password rule that was not satisfied
+type PolicyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError is returned when a password does not satisfy the password policy.
+// It matches ErrInvalidPassword with errors.Is.
+type PasswordPolicyError struct {
+	Violations []PolicyViolation
+}
+
+// Error implements the error interface
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return ErrInvalidPassword.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is reports whether target is ErrInvalidPassword
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrInvalidPassword
+}